@@ -0,0 +1,47 @@
+package dy
+
+import "sync"
+
+// contextAccumulator records the context fields added to a logger and its
+// descendants
+type contextAccumulator struct {
+	mu     sync.Mutex
+	fields []ContextField
+}
+
+// record appends fields to the accumulator, if any
+func (a *contextAccumulator) record(fields ...ContextField) {
+	if a == nil || len(fields) == 0 {
+		return
+	}
+	a.mu.Lock()
+	a.fields = append(a.fields, fields...)
+	a.mu.Unlock()
+}
+
+// snapshot returns a copy of the recorded fields
+func (a *contextAccumulator) snapshot() []ContextField {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]ContextField(nil), a.fields...)
+}
+
+// WithContextAccumulator creates a new logger that records every context
+// field added through WithContext, WithFields or With, by it or by loggers
+// derived from it. The returned function returns the fields recorded so far
+// in the order they were added, for example to hand them to an analytics
+// pipeline at the end of a request. Fields already in l's context are not
+// recorded, and logging is not affected.
+func (l *Logger) WithContextAccumulator() (*Logger, func() []ContextField) {
+	if l == nil {
+		return nil, func() []ContextField { return nil }
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	child := l.newChild()
+	child.context = l.context.Clone()
+	child.accumulator = &contextAccumulator{}
+	return child, child.accumulator.snapshot
+}
@@ -0,0 +1,67 @@
+package dy
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestWithContextAccumulator(t *testing.T) {
+	var buf bytes.Buffer
+	base := New(WithOutput(&buf), WithTimestamp(false)).WithContext("service", "api")
+
+	l, fields := base.WithContextAccumulator()
+	l = l.WithContext("request_id", "req-1")
+	l = l.With(ContextField{Key: "user_id", Value: 42})
+	l.WithContext("step", "validate").Info("validated")
+
+	// Fields added to the parent afterwards are not recorded
+	base.WithContext("unrelated", true)
+
+	got := fields()
+	want := []ContextField{
+		{Key: "request_id", Value: "req-1"},
+		{Key: "user_id", Value: 42},
+		{Key: "step", Value: "validate"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d fields, got %d: %v", len(want), len(got), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Field %d: expected %v, got %v", i, want[i], got[i])
+		}
+	}
+
+	// Logging is unaffected
+	if !strings.Contains(buf.String(), "step: validate") || !strings.Contains(buf.String(), "service: api") {
+		t.Errorf("Expected the context in the output, got: %s", buf.String())
+	}
+
+	// Snapshots are not changed by later additions
+	l.WithContext("later", 1)
+	if len(got) != 3 || len(fields()) != 4 {
+		t.Errorf("Expected an independent snapshot, got %d and %d fields", len(got), len(fields()))
+	}
+}
+
+func TestWithContextAccumulatorConcurrent(t *testing.T) {
+	l, fields := New(WithOutput(&bytes.Buffer{})).WithContextAccumulator()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				l.WithContext("n", j)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := len(fields()); n != 1000 {
+		t.Errorf("Expected 1000 recorded fields, got %d", n)
+	}
+}
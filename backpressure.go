@@ -0,0 +1,167 @@
+package dy
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// fatalDrainTimeout bounds how long a fatal log waits for queued entries
+// to be written before the process exits
+const fatalDrainTimeout = 5 * time.Second
+
+// queuedEntry is a formatted log line waiting to be written
+type queuedEntry struct {
+	out     io.Writer
+	writeMu *sync.Mutex // Serializes the write with direct writes to out
+	entry   *LogEntry
+	data    []byte
+}
+
+// entryQueue buffers formatted entries and writes them from a background goroutine
+type entryQueue struct {
+	mu      sync.RWMutex
+	entries chan queuedEntry
+	onFull  func(entry *LogEntry)
+	pending int64 // Entries queued or being written
+	closed  bool
+	done    chan struct{}
+}
+
+// newEntryQueue creates a queue holding up to size entries and starts draining it
+func newEntryQueue(size int, onFull func(entry *LogEntry)) *entryQueue {
+	q := &entryQueue{
+		entries: make(chan queuedEntry, size),
+		onFull:  onFull,
+		done:    make(chan struct{}),
+	}
+	go q.drain()
+	return q
+}
+
+// drain writes queued entries until the queue is closed
+func (q *entryQueue) drain() {
+	defer close(q.done)
+	for e := range q.entries {
+		e.writeMu.Lock()
+		e.out.Write(e.data)
+		e.writeMu.Unlock()
+		atomic.AddInt64(&q.pending, -1)
+	}
+}
+
+// enqueue adds an entry to the queue. It returns false if the queue has been
+// closed and the caller should write the entry itself. When the queue is full
+// the entry is dropped and handed to onFull. writeMu is held while the entry
+// is written to out.
+func (q *entryQueue) enqueue(out io.Writer, writeMu *sync.Mutex, entry *LogEntry, data []byte) bool {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	if q.closed {
+		return false
+	}
+
+	atomic.AddInt64(&q.pending, 1)
+	select {
+	case q.entries <- queuedEntry{out: out, writeMu: writeMu, entry: entry, data: data}:
+	default:
+		atomic.AddInt64(&q.pending, -1)
+		if q.onFull != nil {
+			q.onFull(entry)
+		}
+	}
+	return true
+}
+
+// waitForDrain blocks until every queued entry has been written or the timeout expires
+func (q *entryQueue) waitForDrain(timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		pending := atomic.LoadInt64(&q.pending)
+		if pending == 0 {
+			return nil
+		}
+		if !time.Now().Before(deadline) {
+			return fmt.Errorf("timed out waiting for log queue to drain: %d entries pending", pending)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// close stops accepting entries and waits for the queued ones to be written
+func (q *entryQueue) close() {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return
+	}
+	q.closed = true
+	close(q.entries)
+	q.mu.Unlock()
+
+	<-q.done
+}
+
+// WithBackpressure creates a new logger that hands formatted entries to a
+// background goroutine instead of writing them on the calling goroutine, so a
+// slow output never blocks the caller. At most maxQueueLen entries are
+// buffered, with a minimum of 1 as smaller values are raised to it; when the
+// buffer is full the new entry is dropped and passed to onFull, which may be
+// nil. Closing the returned logger writes any queued entries before closing
+// the underlying output.
+func (l *Logger) WithBackpressure(maxQueueLen int, onFull func(entry *LogEntry)) *Logger {
+	if l == nil {
+		return nil
+	}
+
+	// An unbuffered queue would drop nearly every entry
+	if maxQueueLen < 1 {
+		maxQueueLen = 1
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	child := l.newChild()
+	child.context = l.context.Clone()
+
+	queue := newEntryQueue(maxQueueLen, onFull)
+	child.queue = queue
+
+	parentCloser := l.closer
+	child.closer = func() error {
+		queue.close()
+		if parentCloser != nil {
+			return parentCloser()
+		}
+		return nil
+	}
+
+	return child
+}
+
+// WaitForDrain blocks until all entries buffered by WithBackpressure have been
+// written or the timeout expires, in which case an error is returned. It
+// returns nil immediately when backpressure is not enabled.
+func (l *Logger) WaitForDrain(timeout time.Duration) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	queue := l.queue
+	l.mu.Unlock()
+
+	if queue == nil {
+		return nil
+	}
+	return queue.waitForDrain(timeout)
+}
+
+// flushQueue waits for queued entries to be written, ignoring timeouts
+func (l *Logger) flushQueue(timeout time.Duration) {
+	_ = l.WaitForDrain(timeout)
+}
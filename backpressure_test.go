@@ -0,0 +1,152 @@
+package dy
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// blockingWriter blocks every write until release is closed
+type blockingWriter struct {
+	mu      sync.Mutex
+	buf     bytes.Buffer
+	release chan struct{}
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	<-w.release
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *blockingWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String()
+}
+
+func TestWithBackpressureDropsWhenFull(t *testing.T) {
+	w := &blockingWriter{release: make(chan struct{})}
+	var dropped int64
+	var lastDropped string
+
+	l := New(WithOutput(w), WithTimestamp(false)).WithBackpressure(2, func(entry *LogEntry) {
+		atomic.AddInt64(&dropped, 1)
+		lastDropped = entry.Message
+	})
+
+	start := time.Now()
+	for i := 0; i < 10; i++ {
+		l.Info("message %d", i)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Expected logging not to block on a slow writer, took %s", elapsed)
+	}
+
+	if atomic.LoadInt64(&dropped) == 0 {
+		t.Errorf("Expected some entries to be dropped when the queue is full")
+	}
+	if lastDropped != "message 9" {
+		t.Errorf("Expected the newest entry to be dropped, got: %q", lastDropped)
+	}
+
+	if err := l.WaitForDrain(10 * time.Millisecond); err == nil {
+		t.Errorf("Expected WaitForDrain to time out while the writer is blocked")
+	}
+
+	close(w.release)
+	if err := l.WaitForDrain(time.Second); err != nil {
+		t.Fatalf("Expected queue to drain, got: %v", err)
+	}
+
+	output := w.String()
+	if !strings.Contains(output, "[INFO] message 0") {
+		t.Errorf("Expected first message to be written, got: %s", output)
+	}
+}
+
+func TestWithBackpressureClose(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false)).WithBackpressure(100, nil)
+	child := l.WithContext("request_id", "abc")
+
+	child.Info("queued message")
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "queued message {request_id: abc}") {
+		t.Errorf("Expected queued entry to be written on close, got: %s", buf.String())
+	}
+
+	// Entries logged after close are written directly
+	child.Info("after close")
+	if !strings.Contains(buf.String(), "after close") {
+		t.Errorf("Expected entry after close to be written, got: %s", buf.String())
+	}
+}
+
+func TestWaitForDrainWithoutBackpressure(t *testing.T) {
+	l := New(WithOutput(&bytes.Buffer{}))
+	if err := l.WaitForDrain(time.Millisecond); err != nil {
+		t.Errorf("Expected nil without backpressure, got: %v", err)
+	}
+}
+
+// overlapWriter records whether two writes were ever in progress at once
+type overlapWriter struct {
+	inFlight int32
+	overlap  int32
+}
+
+func (w *overlapWriter) Write(p []byte) (int, error) {
+	if atomic.AddInt32(&w.inFlight, 1) > 1 {
+		atomic.StoreInt32(&w.overlap, 1)
+	}
+	time.Sleep(10 * time.Microsecond)
+	atomic.AddInt32(&w.inFlight, -1)
+	return len(p), nil
+}
+
+func TestWithBackpressureSharesWriteLock(t *testing.T) {
+	w := &overlapWriter{}
+	parent := New(WithOutput(w), WithTimestamp(false))
+	queued := parent.WithBackpressure(1000, nil)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			parent.Info("direct %d", i)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			queued.Info("queued %d", i)
+		}
+	}()
+	wg.Wait()
+
+	if err := queued.WaitForDrain(5 * time.Second); err != nil {
+		t.Fatalf("Expected queue to drain, got: %v", err)
+	}
+	if atomic.LoadInt32(&w.overlap) != 0 {
+		t.Errorf("Expected queued and direct writes to the same output to be serialized")
+	}
+}
+
+func TestWithBackpressureMinimumQueueLen(t *testing.T) {
+	for _, size := range []int{0, -5} {
+		l := New(WithOutput(&bytes.Buffer{})).WithBackpressure(size, nil)
+		if got := cap(l.queue.entries); got != 1 {
+			t.Errorf("WithBackpressure(%d) queue capacity = %d, want 1", size, got)
+		}
+		l.Close()
+	}
+}
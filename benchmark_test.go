@@ -1,6 +1,7 @@
 package dy
 
 import (
+	"fmt"
 	"io"
 	"testing"
 )
@@ -74,3 +75,143 @@ func BenchmarkConcurrentLogging(b *testing.B) {
 		}
 	})
 }
+
+func BenchmarkLoggerWithContext(b *testing.B) {
+	l := New(WithOutput(io.Discard)).WithFields(map[string]interface{}{
+		"request_id": "abc-123",
+		"user_id":    42,
+		"path":       "/api/users",
+	})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Info("Request handled")
+	}
+}
+
+func BenchmarkLoggerWithContextPool(b *testing.B) {
+	l := New(WithOutput(io.Discard)).WithContextPool(NewContextFieldPool(16)).WithFields(map[string]interface{}{
+		"request_id": "abc-123",
+		"user_id":    42,
+		"path":       "/api/users",
+	})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Info("Request handled")
+	}
+}
+
+func BenchmarkWithContextChain(b *testing.B) {
+	base := New(WithOutput(io.Discard))
+	for i := 0; i < 10; i++ {
+		base = base.WithContext(fmt.Sprintf("service_%d", i), i)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// Six derivations, as in a typical middleware chain
+		base.WithContext("request_id", "abc-123").
+			WithContext("method", "GET").
+			WithContext("path", "/api/users").
+			WithContext("user_id", 42).
+			WithContext("tenant", "acme").
+			WithContext("handler", "listUsers")
+	}
+}
+
+func BenchmarkLoggerContextCache(b *testing.B) {
+	fields := make(map[string]interface{}, 10)
+	for i := 0; i < 10; i++ {
+		fields[fmt.Sprintf("field_%d", i)] = fmt.Sprintf("value-%d", i)
+	}
+
+	for _, bm := range []struct {
+		name    string
+		useJSON bool
+		extra   interface{}
+	}{
+		{"Text", false, "cached"},
+		{"TextUncached", false, []string{"not", "cached"}},
+		{"JSON", true, "cached"},
+		{"JSONUncached", true, []string{"not", "cached"}},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			l := New(WithOutput(io.Discard), WithJSONFormat(bm.useJSON)).
+				WithFields(fields).
+				WithContext("extra", bm.extra)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				for j := 0; j < 1000; j++ {
+					l.Info("Request handled")
+				}
+			}
+		})
+	}
+}
+
+// benchRequest is a nested request body for the redaction benchmarks
+type benchRequest struct {
+	User struct {
+		Name        string
+		Credentials struct {
+			Password string
+			Token    string `json:"token"`
+		}
+	}
+	Tags []string
+}
+
+func BenchmarkLoggerRedaction(b *testing.B) {
+	var req benchRequest
+	req.User.Name = "alice"
+	req.User.Credentials.Password = "hunter2"
+	req.Tags = []string{"a", "b"}
+
+	for _, bm := range []struct {
+		name string
+		opts []Option
+	}{
+		{"Off", nil},
+		{"Flat", []Option{WithRedactKeys("password", "token")}},
+		{"Deep", []Option{WithRedactKeys("password", "token"), WithDeepRedaction(0)}},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			opts := append([]Option{WithOutput(io.Discard), WithJSONFormat(true)}, bm.opts...)
+			l := New(opts...).WithContext("req", req)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				l.Info("request received")
+			}
+		})
+	}
+}
+
+func BenchmarkLoggerCallerInfo(b *testing.B) {
+	for _, bm := range []struct {
+		name string
+		size int
+	}{
+		{"cached", defaultCallerCacheSize},
+		{"uncached", 0},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			SetCallerCacheSize(bm.size)
+			defer SetCallerCacheSize(defaultCallerCacheSize)
+			l := New(WithOutput(io.Discard), WithTimestamp(false), WithCallerInfo(true))
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				l.Info("This is a benchmark test message")
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package dy
+
+import (
+	"runtime"
+	"runtime/debug"
+	"sync"
+)
+
+var (
+	buildInfoOnce sync.Once
+	buildInfo     *debug.BuildInfo
+)
+
+// readBuildInfo returns the build information of the running binary, or nil
+// when it was built without module support. It is read once.
+func readBuildInfo() *debug.BuildInfo {
+	buildInfoOnce.Do(func() {
+		if info, ok := debug.ReadBuildInfo(); ok {
+			buildInfo = info
+		}
+	})
+	return buildInfo
+}
+
+// WithBuildInfo creates a new logger with the main module path, its version
+// and the VCS commit of the running binary as "build.module",
+// "build.version" and "build.commit" context fields. Values the binary was
+// built without are left out.
+func (l *Logger) WithBuildInfo() *Logger {
+	if l == nil {
+		return nil
+	}
+
+	fields := buildInfoFields(readBuildInfo())
+	if len(fields) == 0 {
+		return l
+	}
+	return l.WithFields(fields)
+}
+
+// WithGoVersion creates a new logger with the Go version the binary was
+// built with as a "build.go_version" context field
+func (l *Logger) WithGoVersion() *Logger {
+	if l == nil {
+		return nil
+	}
+
+	return l.WithContext("build.go_version", runtime.Version())
+}
+
+// buildInfoFields returns the context fields of WithBuildInfo for info
+func buildInfoFields(info *debug.BuildInfo) map[string]interface{} {
+	fields := make(map[string]interface{})
+	if info == nil {
+		return fields
+	}
+
+	if info.Main.Path != "" {
+		fields["build.module"] = info.Main.Path
+	}
+	if info.Main.Version != "" {
+		fields["build.version"] = info.Main.Version
+	}
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" && setting.Value != "" {
+			fields["build.commit"] = setting.Value
+		}
+	}
+	return fields
+}
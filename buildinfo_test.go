@@ -0,0 +1,61 @@
+package dy
+
+import (
+	"runtime"
+	"runtime/debug"
+	"testing"
+)
+
+func TestBuildInfoFields(t *testing.T) {
+	info := &debug.BuildInfo{
+		Main: debug.Module{Path: "github.com/acme/shop", Version: "v1.4.2"},
+		Settings: []debug.BuildSetting{
+			{Key: "vcs", Value: "git"},
+			{Key: "vcs.revision", Value: "3f2a9c1"},
+		},
+	}
+
+	fields := buildInfoFields(info)
+	for key, want := range map[string]string{
+		"build.module":  "github.com/acme/shop",
+		"build.version": "v1.4.2",
+		"build.commit":  "3f2a9c1",
+	} {
+		if got := fields[key]; got != want {
+			t.Errorf("Expected %s=%q, got %v", key, want, got)
+		}
+	}
+
+	// Values missing from the build are left out
+	fields = buildInfoFields(&debug.BuildInfo{Main: debug.Module{Path: "github.com/acme/shop"}})
+	if _, ok := fields["build.commit"]; ok || len(fields) != 1 {
+		t.Errorf("Expected only build.module, got %v", fields)
+	}
+	if fields := buildInfoFields(nil); len(fields) != 0 {
+		t.Errorf("Expected no fields without build info, got %v", fields)
+	}
+}
+
+func TestWithBuildInfo(t *testing.T) {
+	l := New(WithOutput(&syncBuffer{})).WithBuildInfo()
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		t.Skip("binary built without build info")
+	}
+	if info.Main.Path != "" {
+		if got, _ := l.ContextValue("build.module"); got != info.Main.Path {
+			t.Errorf("Expected build.module=%q, got %v", info.Main.Path, got)
+		}
+	}
+	if readBuildInfo() != readBuildInfo() {
+		t.Error("Expected the build info to be read once")
+	}
+}
+
+func TestWithGoVersion(t *testing.T) {
+	l := New(WithOutput(&syncBuffer{})).WithGoVersion()
+	if got, _ := l.ContextValue("build.go_version"); got != runtime.Version() {
+		t.Errorf("Expected build.go_version=%q, got %v", runtime.Version(), got)
+	}
+}
@@ -0,0 +1,98 @@
+package dy
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// BytesEncoding controls how []byte field values are rendered
+type BytesEncoding int
+
+const (
+	// BytesEncodingHex renders bytes as lowercase hex
+	BytesEncodingHex BytesEncoding = iota + 1
+	// BytesEncodingBase64 renders bytes as standard base64
+	BytesEncodingBase64
+	// BytesEncodingString renders bytes as a string
+	BytesEncodingString
+	// BytesEncodingSummary renders bytes as their length and a hash prefix,
+	// e.g. "<24 bytes, sha256:ab12cd34…>"
+	BytesEncodingSummary
+)
+
+// maxEncodedBytes is the number of bytes encoded before a value is truncated
+const maxEncodedBytes = 1024
+
+// WithBytesEncoding sets how []byte values in context fields and error
+// attributes are rendered in both output formats. Only the first 1024 bytes
+// of a value are encoded. By default bytes use encoding/json's base64 in
+// JSON and Go's %v formatting in text.
+func WithBytesEncoding(enc BytesEncoding) Option {
+	return func(l *Logger) {
+		l.encoding.bytes = enc
+	}
+}
+
+// bytesValue is a []byte field value with its own encoding
+type bytesValue struct {
+	data []byte
+	enc  BytesEncoding
+}
+
+// Hex returns a field whose bytes are rendered as hex, whatever the
+// logger's bytes encoding, for use with Logger.With
+func Hex(key string, b []byte) ContextField {
+	return ContextField{Key: key, Value: bytesValue{data: b, enc: BytesEncodingHex}}
+}
+
+// Base64 returns a field whose bytes are rendered as base64, for use with Logger.With
+func Base64(key string, b []byte) ContextField {
+	return ContextField{Key: key, Value: bytesValue{data: b, enc: BytesEncodingBase64}}
+}
+
+// BytesSummary returns a field whose bytes are rendered as their length and
+// a hash prefix, for use with Logger.With
+func BytesSummary(key string, b []byte) ContextField {
+	return ContextField{Key: key, Value: bytesValue{data: b, enc: BytesEncodingSummary}}
+}
+
+// String renders the bytes for text output
+func (v bytesValue) String() string {
+	return encodeBytes(v.data, v.enc)
+}
+
+// MarshalJSON renders the bytes as a JSON string
+func (v bytesValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(encodeBytes(v.data, v.enc))
+}
+
+// encodeBytes renders b with enc, truncating long values
+func encodeBytes(b []byte, enc BytesEncoding) string {
+	if enc == BytesEncodingSummary {
+		sum := sha256.Sum256(b)
+		return fmt.Sprintf("<%d bytes, sha256:%s…>", len(b), hex.EncodeToString(sum[:4]))
+	}
+
+	data := b
+	if len(data) > maxEncodedBytes {
+		data = data[:maxEncodedBytes]
+	}
+
+	var s string
+	switch enc {
+	case BytesEncodingHex:
+		s = hex.EncodeToString(data)
+	case BytesEncodingBase64:
+		s = base64.StdEncoding.EncodeToString(data)
+	default:
+		s = string(data)
+	}
+
+	if len(data) < len(b) {
+		s += fmt.Sprintf("…(%d bytes)", len(b))
+	}
+	return s
+}
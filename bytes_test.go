@@ -0,0 +1,69 @@
+package dy
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWithBytesEncoding(t *testing.T) {
+	data := []byte("key-01")
+	tests := []struct {
+		name string
+		enc  BytesEncoding
+		json string
+		text string
+	}{
+		{"hex", BytesEncodingHex, `"key":"6b65792d3031"`, "key: 6b65792d3031"},
+		{"base64", BytesEncodingBase64, `"key":"a2V5LTAx"`, "key: a2V5LTAx"},
+		{"string", BytesEncodingString, `"key":"key-01"`, "key: key-01"},
+		{"summary", BytesEncodingSummary, `"key":"\u003c6 bytes, sha256:`, "key: <6 bytes, sha256:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			l := New(WithOutput(&buf), WithTimestamp(false), WithJSONFormat(true), WithBytesEncoding(tt.enc)).
+				WithContext("key", data)
+
+			l.Info("bytes")
+			if !strings.Contains(buf.String(), tt.json) {
+				t.Errorf("Expected %s in JSON output, got: %s", tt.json, buf.String())
+			}
+
+			buf.Reset()
+			l.DisableJSONFormat()
+			l.Info("bytes")
+			if !strings.Contains(buf.String(), tt.text) {
+				t.Errorf("Expected %q in text output, got: %s", tt.text, buf.String())
+			}
+		})
+	}
+}
+
+func TestBytesFieldOverride(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false), WithJSONFormat(true), WithBytesEncoding(BytesEncodingBase64))
+
+	l.With(Hex("checksum", []byte{0xab, 0x12}), BytesSummary("payload", []byte("hello"))).Info("typed")
+
+	output := buf.String()
+	if !strings.Contains(output, `"checksum":"ab12"`) {
+		t.Errorf("Expected per-field hex encoding, got: %s", output)
+	}
+	if !strings.Contains(output, `"payload":"\u003c5 bytes, sha256:2cf24dba…\u003e"`) {
+		t.Errorf("Expected per-field summary, got: %s", output)
+	}
+}
+
+func TestBytesEncodingTruncates(t *testing.T) {
+	large := bytes.Repeat([]byte{0xff}, 1<<20)
+
+	got := encodeBytes(large, BytesEncodingHex)
+	if len(got) > 2*maxEncodedBytes+32 {
+		t.Errorf("Expected encoding to be truncated, got %d characters", len(got))
+	}
+	if !strings.HasSuffix(got, "…(1048576 bytes)") {
+		t.Errorf("Expected truncation marker with the full size, got suffix %q", got[len(got)-20:])
+	}
+}
@@ -0,0 +1,74 @@
+package dy
+
+import (
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// defaultCallerCacheSize is the number of call sites cached unless
+// SetCallerCacheSize sets another
+const defaultCallerCacheSize = 4096
+
+// callSiteNames are the resolved names of a program counter
+type callSiteNames struct {
+	function string // Full function name including the package path
+	short    string // Function name without the package path
+}
+
+// callerCache maps program counters to their resolved names. A program
+// counter always resolves to the same names, so entries never go stale.
+type callerCache struct {
+	mu      sync.RWMutex
+	size    int
+	entries map[uintptr]callSiteNames
+}
+
+var callers = &callerCache{size: defaultCallerCacheSize}
+
+// SetCallerCacheSize sets how many call sites have their function names
+// cached for caller info and tracing. The cache is cleared when full,
+// bounding its memory in binaries with many call sites. A size of 0 or less
+// disables caching. The default is 4096.
+func SetCallerCacheSize(size int) {
+	callers.mu.Lock()
+	defer callers.mu.Unlock()
+
+	callers.size = max(size, 0)
+	callers.entries = nil
+}
+
+// lookup returns the names of pc, resolving and caching them when needed
+func (c *callerCache) lookup(pc uintptr) callSiteNames {
+	c.mu.RLock()
+	names, ok := c.entries[pc]
+	c.mu.RUnlock()
+	if ok {
+		return names
+	}
+
+	names = resolveNames(pc)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.size > 0 {
+		if c.entries == nil || len(c.entries) >= c.size {
+			c.entries = make(map[uintptr]callSiteNames, min(c.size, 64))
+		}
+		c.entries[pc] = names
+	}
+	return names
+}
+
+// resolveNames resolves the names of pc
+func resolveNames(pc uintptr) callSiteNames {
+	var function string
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		function = fn.Name()
+	}
+	parts := strings.Split(function, ".")
+	return callSiteNames{
+		function: function,
+		short:    parts[len(parts)-1],
+	}
+}
@@ -0,0 +1,54 @@
+package dy
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestCallerCache(t *testing.T) {
+	defer SetCallerCacheSize(defaultCallerCacheSize)
+	SetCallerCacheSize(2)
+
+	pc, file, _, _ := runtime.Caller(0)
+	uncached := resolveNames(pc)
+	if uncached.function != "github.com/zakirkun/dy.TestCallerCache" || uncached.short != "TestCallerCache" {
+		t.Fatalf("Unexpected names %+v for %s", uncached, file)
+	}
+	if got := callers.lookup(pc); got != uncached {
+		t.Errorf("Expected %+v from the cache, got %+v", uncached, got)
+	}
+	if got := callers.lookup(pc); got != uncached || len(callers.entries) != 1 {
+		t.Errorf("Expected the cached names, got %+v with %d entries", got, len(callers.entries))
+	}
+
+	// The cache is cleared rather than growing past its size
+	for i := uintptr(1); i <= 3; i++ {
+		callers.lookup(pc + i)
+	}
+	if n := len(callers.entries); n > 2 {
+		t.Errorf("Expected at most 2 cached entries, got %d", n)
+	}
+
+	SetCallerCacheSize(0)
+	callers.lookup(pc)
+	if len(callers.entries) != 0 {
+		t.Errorf("Expected nothing cached when disabled, got %d entries", len(callers.entries))
+	}
+}
+
+func TestGetCallerCached(t *testing.T) {
+	defer SetCallerCacheSize(defaultCallerCacheSize)
+
+	for _, size := range []int{defaultCallerCacheSize, 0} {
+		SetCallerCacheSize(size)
+		for i := 0; i < 2; i++ {
+			caller := getCaller(1, nil)
+			if caller.File != "callercache_test.go" || caller.Function != "github.com/zakirkun/dy.TestGetCallerCached" {
+				t.Errorf("Unexpected caller %+v with cache size %d", caller, size)
+			}
+			if name := getFunctionName(1); name != "TestGetCallerCached" {
+				t.Errorf("Unexpected function name %q with cache size %d", name, size)
+			}
+		}
+	}
+}
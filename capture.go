@@ -0,0 +1,89 @@
+package dy
+
+import "fmt"
+
+// CaptureEntry builds the entry a call at level would log, including the
+// logger's context, caller and timestamp, and returns it without writing it.
+// Level filtering, sampling and filters are not applied.
+func (l *Logger) CaptureEntry(level Level, format string, args ...interface{}) *LogEntry {
+	if l == nil {
+		return nil
+	}
+
+	entry, fields := l.buildEntry(level, fmt.Sprintf(format, args...), 0)
+	l.setEntryContext(entry, fields)
+	l.pool.put(fields)
+	return entry
+}
+
+// CaptureErrorEntry builds an ErrorLevel entry with err attached as by
+// WithError, and returns it without writing it
+func (l *Logger) CaptureErrorEntry(err error, msg string) *LogEntry {
+	if l == nil {
+		return nil
+	}
+
+	child := l
+	if err != nil {
+		child = l.WithContext("error", extractErrorData(err, 3, l.errorStackConfig()))
+	}
+
+	entry, fields := child.buildEntry(ErrorLevel, msg, 0)
+	child.setEntryContext(entry, fields)
+	child.pool.put(fields)
+	return entry
+}
+
+// setEntryContext prepares fields as for writing and stores them as the
+// entry's context
+func (l *Logger) setEntryContext(entry *LogEntry, fields []ContextField) {
+	fields = l.prepareFields(fields)
+	if len(fields) > 0 {
+		entry.Context = make(map[string]interface{}, len(fields))
+		for _, field := range fields {
+			entry.Context[field.Key] = field.Value
+		}
+	}
+}
+
+// prepareFields expands error values, resolves LogValuer and similar values
+// and applies the logger's redaction, value size limits, field transformers,
+// key collision policy, value encodings and value length limit to the fields
+// of an entry
+func (l *Logger) prepareFields(fields []ContextField) []ContextField {
+	l.mu.Lock()
+	transforms := l.transforms
+	collisions := l.collisions
+	encoding := l.encoding
+	utc := l.utc
+	errorFields := l.errorFields
+	redaction := l.redaction
+	limits := l.limits
+	maxValueLen := l.maxValueLen
+	useJSON := l.jsonFormat
+	normalize := l.normalize
+	l.mu.Unlock()
+
+	fields = expandErrorFields(fields, errorFields, l.errorStackConfig())
+	fields = resolveFields(fields)
+	if redaction.enabled() {
+		fields = redaction.redactFields(fields)
+	}
+	if limits.enabled() {
+		fields = limits.limitFields(fields)
+	}
+	if len(transforms) > 0 {
+		fields = transformFields(fields, transforms)
+	}
+	if normalize.keyCase != KeyCaseNone {
+		fields = l.normalizeFields(fields, normalize)
+	}
+	fields = l.resolveCollisions(fields, collisions)
+	if encoding.enabled() {
+		fields = encoding.encodeFields(fields, utc)
+	}
+	if maxValueLen > 0 {
+		fields = truncateFields(fields, maxValueLen, useJSON)
+	}
+	return fields
+}
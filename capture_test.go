@@ -0,0 +1,47 @@
+package dy
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestCaptureEntry(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithCallerInfo(true), WithLevel(ErrorLevel)).WithContext("request_id", "abc")
+
+	entry := l.CaptureEntry(InfoLevel, "user %d", 7)
+
+	if buf.Len() != 0 {
+		t.Errorf("Expected nothing written, got: %s", buf.String())
+	}
+	if entry.Level != "INFO" || entry.Message != "user 7" {
+		t.Errorf("Unexpected entry: %+v", entry)
+	}
+	if entry.Context["request_id"] != "abc" {
+		t.Errorf("Expected logger context in entry, got %v", entry.Context)
+	}
+	if entry.Timestamp == "" {
+		t.Error("Expected a timestamp")
+	}
+	if entry.Caller == nil || entry.Caller.File != "capture_test.go" {
+		t.Errorf("Expected caller to point at the test, got %+v", entry.Caller)
+	}
+}
+
+func TestCaptureErrorEntry(t *testing.T) {
+	l := New(WithOutput(&bytes.Buffer{}))
+
+	entry := l.CaptureErrorEntry(errors.New("boom"), "request failed")
+
+	if entry.Level != "ERROR" || entry.Message != "request failed" {
+		t.Errorf("Unexpected entry: %+v", entry)
+	}
+	data, ok := entry.Context["error"].(ErrorData)
+	if !ok || data.Message != "boom" {
+		t.Fatalf("Expected error data in context, got %v", entry.Context)
+	}
+	if len(data.Stack) == 0 || data.Stack[0].Function != "github.com/zakirkun/dy.TestCaptureErrorEntry" {
+		t.Errorf("Expected stack to start at the caller, got %+v", data.Stack)
+	}
+}
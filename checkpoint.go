@@ -0,0 +1,58 @@
+package dy
+
+import (
+	"sync"
+	"time"
+)
+
+// checkpoints holds the times set by Checkpoint, shared with child loggers
+type checkpoints struct {
+	mu    sync.Mutex
+	times map[string]time.Time
+}
+
+// Checkpoint records the current time under label the first time it is
+// called, and on later calls logs the time elapsed since then at DebugLevel,
+// as in "checkpoint: start (elapsed: 1.23ms)". Checkpoints are shared with
+// the logger's children and safe for concurrent use.
+func (l *Logger) Checkpoint(label string) {
+	if l == nil {
+		return
+	}
+
+	now := time.Now()
+
+	l.checkpoints.mu.Lock()
+	start, ok := l.checkpoints.times[label]
+	if !ok {
+		l.checkpoints.times[label] = now
+	}
+	l.checkpoints.mu.Unlock()
+
+	if ok {
+		l.log(DebugLevel, "checkpoint: %s (elapsed: %s)", label, l.formatElapsed(now.Sub(start)))
+	}
+}
+
+// ResetCheckpoint clears the checkpoint label, so the next Checkpoint call
+// with it sets it again
+func (l *Logger) ResetCheckpoint(label string) {
+	if l == nil {
+		return
+	}
+
+	l.checkpoints.mu.Lock()
+	defer l.checkpoints.mu.Unlock()
+	delete(l.checkpoints.times, label)
+}
+
+// ResetAllCheckpoints clears every checkpoint
+func (l *Logger) ResetAllCheckpoints() {
+	if l == nil {
+		return
+	}
+
+	l.checkpoints.mu.Lock()
+	defer l.checkpoints.mu.Unlock()
+	clear(l.checkpoints.times)
+}
@@ -0,0 +1,67 @@
+package dy
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestCheckpoint(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false), WithLevel(DebugLevel))
+
+	l.Checkpoint("start")
+	if buf.Len() != 0 {
+		t.Errorf("Expected the first checkpoint to only be recorded, got: %s", buf.String())
+	}
+
+	l.WithContext("step", 2).Checkpoint("start")
+	pattern := regexp.MustCompile(`^\[DEBUG\] checkpoint: start \(elapsed: [0-9.]+[µn]?m?s\) \{step: 2\}\n$`)
+	if !pattern.MatchString(buf.String()) {
+		t.Errorf("Unexpected checkpoint output: %q", buf.String())
+	}
+}
+
+func TestResetCheckpoint(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false), WithLevel(DebugLevel))
+
+	l.Checkpoint("a")
+	l.Checkpoint("b")
+	l.ResetCheckpoint("a")
+	l.Checkpoint("a")
+	l.Checkpoint("b")
+	if got := buf.String(); strings.Contains(got, "checkpoint: a") || !strings.Contains(got, "checkpoint: b") {
+		t.Errorf("Expected only checkpoint b to be logged, got: %s", got)
+	}
+
+	buf.Reset()
+	l.ResetAllCheckpoints()
+	l.Checkpoint("b")
+	if buf.Len() != 0 {
+		t.Errorf("Expected all checkpoints to be cleared, got: %s", buf.String())
+	}
+}
+
+func TestCheckpointConcurrent(t *testing.T) {
+	var buf syncBuffer
+	l := New(WithOutput(&buf), WithTimestamp(false), WithLevel(DebugLevel))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				l.Checkpoint("shared")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := strings.Count(buf.String(), "checkpoint: shared"); got != 79 {
+		t.Errorf("Expected 79 checkpoint entries, got %d", got)
+	}
+}
@@ -0,0 +1,68 @@
+// Command dy-pretty renders JSON logs written by dy as colored text.
+//
+// Usage:
+//
+//	kubectl logs my-pod | dy-pretty --level warn --where request_id=abc
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/zakirkun/dy"
+)
+
+// whereFlags collects repeated --where key=value filters
+type whereFlags []string
+
+func (w *whereFlags) String() string {
+	return strings.Join(*w, ",")
+}
+
+func (w *whereFlags) Set(value string) error {
+	if !strings.Contains(value, "=") {
+		return fmt.Errorf("expected key=value, got %q", value)
+	}
+	*w = append(*w, value)
+	return nil
+}
+
+func main() {
+	level := flag.String("level", "debug", "minimum level to show")
+	noColor := flag.Bool("no-color", false, "disable colored levels")
+	var where whereFlags
+	flag.Var(&where, "where", "only show entries with context key=value (repeatable)")
+	flag.Parse()
+
+	opts := []dy.HumanizeOption{
+		dy.WithHumanizeLevel(dy.ParseLevel(*level)),
+		dy.WithHumanizeColor(!*noColor),
+	}
+	for _, filter := range where {
+		key, value, _ := strings.Cut(filter, "=")
+		opts = append(opts, dy.WithHumanizeWhere(key, value))
+	}
+
+	var input io.Reader = os.Stdin
+	if flag.NArg() > 0 {
+		files := make([]io.Reader, 0, flag.NArg())
+		for _, name := range flag.Args() {
+			f, err := os.Open(name)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "dy-pretty:", err)
+				os.Exit(1)
+			}
+			defer f.Close()
+			files = append(files, f)
+		}
+		input = io.MultiReader(files...)
+	}
+
+	if err := dy.Humanize(input, os.Stdout, opts...); err != nil {
+		fmt.Fprintln(os.Stderr, "dy-pretty:", err)
+		os.Exit(1)
+	}
+}
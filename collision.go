@@ -0,0 +1,100 @@
+package dy
+
+import "sync"
+
+// ReservedKeys are the top-level entry keys that context fields may not use.
+// How a colliding context key is handled is set with WithKeyCollisionPolicy.
+var ReservedKeys = []string{
+	"timestamp", "level", "message", "prefix", "nest_level",
+	"caller", "trace_type", "elapsed_time", "context", "labels",
+}
+
+// reservedKeys is ReservedKeys as a set
+var reservedKeys = func() map[string]bool {
+	keys := make(map[string]bool, len(ReservedKeys))
+	for _, key := range ReservedKeys {
+		keys[key] = true
+	}
+	return keys
+}()
+
+// KeyCollisionPolicy controls what happens to context fields whose key is one
+// of the ReservedKeys
+type KeyCollisionPolicy int
+
+const (
+	// KeyCollisionPrefix renames the field by adding the collision prefix
+	KeyCollisionPrefix KeyCollisionPolicy = iota
+	// KeyCollisionDrop leaves the field out and logs a warning once per key
+	KeyCollisionDrop
+	// KeyCollisionAllow keeps the field as is
+	KeyCollisionAllow
+)
+
+// defaultCollisionPrefix is prepended to colliding keys by KeyCollisionPrefix
+const defaultCollisionPrefix = "fields."
+
+// collisionConfig holds the key collision settings of a logger
+type collisionConfig struct {
+	policy KeyCollisionPolicy
+	prefix string
+}
+
+// WithKeyCollisionPolicy sets how context fields colliding with ReservedKeys
+// are handled in every output format. The default is KeyCollisionPrefix.
+func WithKeyCollisionPolicy(policy KeyCollisionPolicy) Option {
+	return func(l *Logger) {
+		l.collisions.policy = policy
+	}
+}
+
+// WithKeyCollisionPrefix sets the prefix added to colliding keys by
+// KeyCollisionPrefix. The default is "fields.".
+func WithKeyCollisionPrefix(prefix string) Option {
+	return func(l *Logger) {
+		l.collisions.prefix = prefix
+	}
+}
+
+// droppedKeys records the reserved keys a warning has been logged for
+var droppedKeys sync.Map
+
+// resolveCollisions applies the collision policy to fields, returning a copy
+// if any field had to change
+func (l *Logger) resolveCollisions(fields []ContextField, cfg collisionConfig) []ContextField {
+	if cfg.policy == KeyCollisionAllow {
+		return fields
+	}
+
+	var result []ContextField
+	for i, field := range fields {
+		if !reservedKeys[field.Key] {
+			if result != nil {
+				result = append(result, field)
+			}
+			continue
+		}
+
+		if result == nil {
+			result = append(make([]ContextField, 0, len(fields)), fields[:i]...)
+		}
+
+		if cfg.policy == KeyCollisionDrop {
+			if _, warned := droppedKeys.LoadOrStore(field.Key, true); !warned {
+				l.Warn("context field %q dropped: the key is reserved", field.Key)
+			}
+			continue
+		}
+
+		prefix := cfg.prefix
+		if prefix == "" {
+			prefix = defaultCollisionPrefix
+		}
+		result = append(result, ContextField{Key: prefix + field.Key, Value: field.Value})
+	}
+
+	if result == nil {
+		return fields
+	}
+	return result
+}
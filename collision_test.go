@@ -0,0 +1,55 @@
+package dy
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestKeyCollisionPrefix(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false), WithJSONFormat(true))
+
+	l.WithContext("level", "admin").WithContext("context", "nested").Info("collide")
+	output := buf.String()
+	if !strings.Contains(output, `"fields.level":"admin"`) || !strings.Contains(output, `"fields.context":"nested"`) {
+		t.Errorf("Expected colliding keys to be prefixed, got: %s", output)
+	}
+
+	buf.Reset()
+	l = New(WithOutput(&buf), WithTimestamp(false), WithKeyCollisionPrefix("ctx_"))
+	l.WithContext("message", "hi").Info("collide")
+	if !strings.Contains(buf.String(), "{ctx_message: hi}") {
+		t.Errorf("Expected custom prefix in text output, got: %s", buf.String())
+	}
+}
+
+func TestKeyCollisionDrop(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false), WithKeyCollisionPolicy(KeyCollisionDrop)).
+		WithContext("caller", "me").WithContext("user", "bob")
+
+	l.Info("first")
+	l.Info("second")
+
+	output := buf.String()
+	if strings.Contains(output, "caller: me") {
+		t.Errorf("Expected reserved key to be dropped, got: %s", output)
+	}
+	if got := strings.Count(output, `context field "caller" dropped`); got != 1 {
+		t.Errorf("Expected a single warning, got %d in: %s", got, output)
+	}
+	if strings.Count(output, "user: bob") != 3 {
+		t.Errorf("Expected other fields kept, got: %s", output)
+	}
+}
+
+func TestKeyCollisionAllow(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false), WithJSONFormat(true), WithKeyCollisionPolicy(KeyCollisionAllow))
+
+	l.WithContext("level", "admin").Info("collide")
+	if !strings.Contains(buf.String(), `"context":{"level":"admin"}`) {
+		t.Errorf("Expected colliding key kept as is, got: %s", buf.String())
+	}
+}
@@ -24,6 +24,7 @@ const (
 	BoldPurple = "\033[1;35m"
 	BoldCyan   = "\033[1;36m"
 	BoldWhite  = "\033[1;37m"
+	Inverse    = "\033[7m"
 )
 
 // ColorOption is a function that modifies a Logger to use colors
@@ -44,9 +45,14 @@ func getLevelColor(level Level) string {
 		return Yellow
 	case ErrorLevel:
 		return Red
+	case DPanicLevel:
+		return BoldPurple
 	case FatalLevel:
 		return BoldRed
 	default:
+		if info, ok := lookupLevel(level); ok && info.color != "" {
+			return info.color
+		}
 		return Reset
 	}
 }
@@ -0,0 +1,68 @@
+package dy
+
+// LoggerConfig is a serializable form of a logger's settings, suitable for
+// storing in JSON or YAML configuration files
+type LoggerConfig struct {
+	Level           string `json:"level" yaml:"level"`
+	Prefix          string `json:"prefix,omitempty" yaml:"prefix,omitempty"`
+	Timestamp       bool   `json:"timestamp" yaml:"timestamp"`
+	TimestampFormat string `json:"timestamp_format,omitempty" yaml:"timestamp_format,omitempty"`
+	JSONFormat      bool   `json:"json_format" yaml:"json_format"`
+	Color           bool   `json:"color" yaml:"color"`
+	IndentString    string `json:"indent_string,omitempty" yaml:"indent_string,omitempty"`
+	CallerInfo      bool   `json:"caller_info" yaml:"caller_info"`
+	Trace           bool   `json:"trace" yaml:"trace"`
+}
+
+// Export returns the logger's current settings as a LoggerConfig
+func (l *Logger) Export() LoggerConfig {
+	if l == nil {
+		return LoggerConfig{}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return LoggerConfig{
+		Level:           l.level.String(),
+		Prefix:          l.prefix,
+		Timestamp:       l.timestamp,
+		TimestampFormat: l.timeFormat,
+		JSONFormat:      l.jsonFormat,
+		Color:           l.colorEnabled,
+		IndentString:    l.indentString,
+		CallerInfo:      l.callerInfo,
+		Trace:           l.traceEnabled,
+	}
+}
+
+// NewFromConfig creates a logger from a LoggerConfig. An empty level defaults
+// to INFO and an empty timestamp format or indent string to the usual
+// defaults; an unknown level name is an error.
+func NewFromConfig(cfg LoggerConfig) (*Logger, error) {
+	level := InfoLevel
+	if cfg.Level != "" {
+		var err error
+		if level, err = parseLevelStrict(cfg.Level); err != nil {
+			return nil, err
+		}
+	}
+
+	options := []Option{
+		WithLevel(level),
+		WithPrefix(cfg.Prefix),
+		WithTimestamp(cfg.Timestamp),
+		WithJSONFormat(cfg.JSONFormat),
+		WithColor(cfg.Color),
+		WithCallerInfo(cfg.CallerInfo),
+		WithTrace(cfg.Trace),
+	}
+	if cfg.TimestampFormat != "" {
+		options = append(options, WithTimeFormat(cfg.TimestampFormat))
+	}
+	if cfg.IndentString != "" {
+		options = append(options, WithIndentString(cfg.IndentString))
+	}
+
+	return NewE(options...)
+}
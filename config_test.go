@@ -0,0 +1,82 @@
+package dy
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestExportAndNewFromConfig(t *testing.T) {
+	original := New(
+		WithLevel(WarnLevel),
+		WithPrefix("API"),
+		WithTimestamp(false),
+		WithTimeFormat(time.RFC3339),
+		WithJSONFormat(true),
+		WithColor(false),
+		WithIndentString("\t"),
+		WithCallerInfo(true),
+		WithTrace(true),
+	)
+
+	cfg := original.Export()
+	expected := LoggerConfig{
+		Level:           "WARN",
+		Prefix:          "API",
+		Timestamp:       false,
+		TimestampFormat: time.RFC3339,
+		JSONFormat:      true,
+		Color:           false,
+		IndentString:    "\t",
+		CallerInfo:      true,
+		Trace:           true,
+	}
+	if cfg != expected {
+		t.Fatalf("Export() = %+v, want %+v", cfg, expected)
+	}
+
+	// Round trip through JSON as a config file would
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("Failed to marshal config: %v", err)
+	}
+	var decoded LoggerConfig
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal config: %v", err)
+	}
+
+	restored, err := NewFromConfig(decoded)
+	if err != nil {
+		t.Fatalf("NewFromConfig returned error: %v", err)
+	}
+	if got := restored.Export(); got != expected {
+		t.Errorf("Restored config = %+v, want %+v", got, expected)
+	}
+
+	var buf bytes.Buffer
+	restored.out = &buf
+	restored.Info("filtered")
+	restored.Warn("kept")
+	if !bytes.Contains(buf.Bytes(), []byte(`"prefix":"API"`)) || bytes.Contains(buf.Bytes(), []byte("filtered")) {
+		t.Errorf("Expected restored logger to behave like the original, got: %s", buf.String())
+	}
+}
+
+func TestNewFromConfigDefaults(t *testing.T) {
+	l, err := NewFromConfig(LoggerConfig{})
+	if err != nil {
+		t.Fatalf("NewFromConfig returned error: %v", err)
+	}
+
+	cfg := l.Export()
+	if cfg.Level != "INFO" || cfg.TimestampFormat != defaultTimeFormat || cfg.IndentString != "  " {
+		t.Errorf("Expected defaults for empty config, got: %+v", cfg)
+	}
+}
+
+func TestNewFromConfigUnknownLevel(t *testing.T) {
+	if _, err := NewFromConfig(LoggerConfig{Level: "verbose"}); err == nil {
+		t.Errorf("Expected error for unknown level")
+	}
+}
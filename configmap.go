@@ -0,0 +1,65 @@
+package dy
+
+import (
+	"path"
+	"strings"
+)
+
+// WithConfigMap creates a new logger with each entry of cm as a context
+// field, for logging the effective configuration at startup. Values are
+// redacted when the last dot-separated segment of their key is a sensitive
+// name (see WithSensitiveParams), e.g. "db.password", or when the key
+// matches one of the exclude patterns, e.g. "*.dsn" or "auth.*". Patterns use
+// path.Match syntax and are matched case-insensitively. The logger is
+// returned unchanged when cm is empty.
+func (l *Logger) WithConfigMap(cm map[string]string, exclude ...string) *Logger {
+	if l == nil {
+		return nil
+	}
+	if len(cm) == 0 {
+		return l
+	}
+
+	l.mu.Lock()
+	sensitive := l.sensitive
+	l.mu.Unlock()
+	if sensitive == nil {
+		sensitive = defaultSensitiveParams
+	}
+
+	fields := make(map[string]interface{}, len(cm))
+	for key, value := range cm {
+		if sensitiveConfigKey(key, sensitive) || matchesAny(exclude, key) {
+			fields[key] = redactedValue
+			continue
+		}
+		fields[key] = value
+	}
+	return l.WithFields(fields)
+}
+
+// sensitiveConfigKey reports whether the last segment of key is sensitive
+func sensitiveConfigKey(key string, sensitive map[string]bool) bool {
+	key = strings.ToLower(key)
+	if i := strings.LastIndex(key, "."); i >= 0 {
+		key = key[i+1:]
+	}
+	return sensitive[key]
+}
+
+// matchesAny reports whether key matches one of patterns, ignoring case. A
+// malformed pattern only matches the key it spells.
+func matchesAny(patterns []string, key string) bool {
+	key = strings.ToLower(key)
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(pattern)
+		matched, err := path.Match(pattern, key)
+		if err != nil {
+			matched = pattern == key
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package dy
+
+import "testing"
+
+func TestWithConfigMap(t *testing.T) {
+	l := New(WithOutput(&syncBuffer{})).WithConfigMap(map[string]string{
+		"server.port":      "8080",
+		"db.password":      "hunter2",
+		"db.dsn":           "postgres://app@db/shop",
+		"Auth.ClientToken": "abc",
+		"feature.beta":     "true",
+	}, "*.dsn", "auth.*")
+
+	for key, want := range map[string]string{
+		"server.port":      "8080",
+		"feature.beta":     "true",
+		"db.password":      redactedValue, // Sensitive name
+		"db.dsn":           redactedValue, // Excluded pattern
+		"Auth.ClientToken": redactedValue, // Patterns ignore case
+	} {
+		if got, _ := l.ContextValue(key); got != want {
+			t.Errorf("Expected %s=%q, got %v", key, want, got)
+		}
+	}
+}
+
+func TestWithConfigMapSensitiveParams(t *testing.T) {
+	l := New(WithOutput(&syncBuffer{}), WithSensitiveParams("region")).
+		WithConfigMap(map[string]string{"aws.region": "eu-west-1", "password": "hunter2"})
+
+	if got, _ := l.ContextValue("aws.region"); got != redactedValue {
+		t.Errorf("Expected the configured sensitive name to be redacted, got %v", got)
+	}
+	if got, _ := l.ContextValue("password"); got != "hunter2" {
+		t.Errorf("Expected WithSensitiveParams to replace the defaults, got %v", got)
+	}
+}
+
+func TestWithConfigMapEmpty(t *testing.T) {
+	l := New(WithOutput(&syncBuffer{}))
+	if got := l.WithConfigMap(nil); got != l {
+		t.Error("Expected an empty config map to return the logger unchanged")
+	}
+}
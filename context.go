@@ -1,33 +1,99 @@
 package dy
 
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"runtime"
+	"sync/atomic"
+)
+
 // ContextField represents a key-value pair in the logging context
 type ContextField struct {
 	Key   string
 	Value interface{}
 }
 
-// LogContext contains all contextual fields for a logger instance
+// LogContext contains all contextual fields for a logger instance. Derived
+// contexts share the backing array of their parent's fields and only copy it
+// when a sibling has already extended it, so deriving a context is cheap.
+// Fields must not be appended to directly; use Add.
 type LogContext struct {
 	Fields []ContextField
+	used   *atomic.Int64 // Length of Fields' backing array claimed by contexts sharing it
 }
 
 // Add adds a new field to the context
 func (c *LogContext) Add(key string, value interface{}) {
-	c.Fields = append(c.Fields, ContextField{Key: key, Value: value})
+	c.append(ContextField{Key: key, Value: value})
+}
+
+// append adds fields, writing them in place when no other context sharing the
+// backing array has claimed the space after c's fields, and copying otherwise
+func (c *LogContext) append(fields ...ContextField) {
+	n := len(c.Fields)
+	if c.used != nil && n+len(fields) <= cap(c.Fields) && c.used.CompareAndSwap(int64(n), int64(n+len(fields))) {
+		c.Fields = append(c.Fields, fields...)
+		return
+	}
+
+	grown := make([]ContextField, n, max(2*(n+len(fields)), 4))
+	copy(grown, c.Fields)
+	c.Fields = append(grown, fields...)
+	c.used = &atomic.Int64{}
+	c.used.Store(int64(len(c.Fields)))
 }
 
-// Clone creates a copy of the context
+// Clone creates a copy of the context. The copy shares the fields' backing
+// array until either context adds a field.
 func (c *LogContext) Clone() *LogContext {
 	if c == nil {
 		return &LogContext{}
 	}
 
-	clone := &LogContext{
-		Fields: make([]ContextField, len(c.Fields)),
+	if c.used == nil {
+		// The array is not tracked, so the first Add must copy it
+		return &LogContext{Fields: c.Fields[:len(c.Fields):len(c.Fields)]}
+	}
+	return &LogContext{Fields: c.Fields, used: c.used}
+}
+
+// fieldsFor returns the fields to emit for an entry at the given level,
+// resolving values that depend on the level
+func (c *LogContext) fieldsFor(level Level) []ContextField {
+	return c.appendFieldsFor(nil, level)
+}
+
+// appendFieldsFor is like fieldsFor but appends the fields to dst
+func (c *LogContext) appendFieldsFor(dst []ContextField, level Level) []ContextField {
+	if c == nil || len(c.Fields) == 0 {
+		return dst
+	}
+
+	fields := dst
+	if fields == nil {
+		fields = make([]ContextField, 0, len(c.Fields))
+	}
+	for _, field := range c.Fields {
+		if sv, ok := field.Value.(secureValue); ok {
+			if !sv.allowed(level) {
+				continue
+			}
+			field.Value = sv.value
+		}
+		fields = append(fields, field)
 	}
+	return fields
+}
 
-	copy(clone.Fields, c.Fields)
-	return clone
+// hasField reports whether fields contains key
+func hasField(fields []ContextField, key string) bool {
+	for _, field := range fields {
+		if field.Key == key {
+			return true
+		}
+	}
+	return false
 }
 
 // Remove removes a field from the context by key
@@ -38,21 +104,23 @@ func (c *LogContext) Remove(key string) {
 
 	for i, field := range c.Fields {
 		if field.Key == key {
-			// Remove the item by swapping with the last element and slicing
-			c.Fields[i] = c.Fields[len(c.Fields)-1]
-			c.Fields = c.Fields[:len(c.Fields)-1]
+			// The array may be shared, so remove the item from a copy by
+			// swapping with the last element and slicing
+			fields := make([]ContextField, len(c.Fields))
+			copy(fields, c.Fields)
+			fields[i] = fields[len(fields)-1]
+			c.Fields = fields[:len(fields)-1]
+			c.used = &atomic.Int64{}
+			c.used.Store(int64(len(c.Fields)))
 			return
 		}
 	}
 }
 
-// WithContext creates a new logger with additional context fields
-func (l *Logger) WithContext(key string, value interface{}) *Logger {
-	l.mu.Lock()
-	defer l.mu.Unlock()
-
-	// Create a new logger that shares the same configuration
-	child := &Logger{
+// newChild returns a logger sharing l's configuration but without a context.
+// The caller must hold l.mu.
+func (l *Logger) newChild() *Logger {
+	return &Logger{
 		out:          l.out,
 		level:        l.level,
 		prefix:       l.prefix,
@@ -64,8 +132,83 @@ func (l *Logger) WithContext(key string, value interface{}) *Logger {
 		callerInfo:   l.callerInfo,
 		colorEnabled: l.colorEnabled,
 		closer:       l.closer,
+		stack:        l.stack,
+		frozen:       l.frozen,
+		queue:        l.queue,
+		writeMu:      l.writeMu,
+		ring:         l.ring,
+		hooks:        l.hooks,
+		validator:    l.validator,
+		validation:   l.validation,
+		development:  l.development,
+		sampler:      l.sampler,
+		timeFormat:   l.timeFormat,
+		utc:          l.utc,
+		epoch:        l.epoch,
+		encoding:     l.encoding,
+		sensitive:    l.sensitive,
+		heartbeat:    l.heartbeat,
+		filters:      l.filters,
+		filtered:     l.filtered,
+		patterns:     l.patterns,
+		suppressed:   l.suppressed,
+		dynamic:      l.dynamic,
+		required:     l.required,
+		formats:      l.formats,
+		labels:       l.labels,
+		keys:         l.keys,
+		normalize:    l.normalize,
+		highlights:   l.highlights,
+		transforms:   l.transforms,
+		callerSkip:   l.callerSkip,
+		callSite:     l.callSite,
+		traceSampler: l.traceSampler,
+		collisions:   l.collisions,
+		errorFields:  l.errorFields,
+		redaction:    l.redaction,
+		limits:       l.limits,
+		traceIDGen:   l.traceIDGen,
+		traceIDKey:   l.traceIDKey,
+		maxValueLen:  l.maxValueLen,
+		checkpoints:  l.checkpoints,
+		jwtMapping:   l.jwtMapping,
+		ctxCache:     &contextCache{},
+		traceCond:    l.traceCond,
+		sqlArgs:      l.sqlArgs,
+		accumulator:  l.accumulator,
+		tees:         l.tees,
+		pool:         l.pool,
+		flagsExclude: l.flagsExclude,
+
+		autoStack:       l.autoStack,
+		autoStackLevel:  l.autoStackLevel,
+		callerFullPath:  l.callerFullPath,
+		errorStackLevel: l.errorStackLevel,
+	}
+}
+
+// WithContext creates a new logger with additional context fields
+func (l *Logger) WithContext(key string, value interface{}) *Logger {
+	if l == nil {
+		return nil
+	}
+
+	l.checkKey(key)
+	if !l.validateField(key, value) {
+		return l
 	}
 
+	l.mu.Lock()
+
+	// Frozen loggers keep their context as is
+	if l.frozen {
+		l.mu.Unlock()
+		return l
+	}
+
+	// Create a new logger that shares the same configuration
+	child := l.newChild()
+
 	// Clone the context if it exists, or create a new one
 	child.context = l.context.Clone()
 	if child.context == nil {
@@ -74,64 +217,114 @@ func (l *Logger) WithContext(key string, value interface{}) *Logger {
 
 	// Add the new field to the context
 	child.context.Add(key, value)
+	child.accumulator.record(ContextField{Key: key, Value: value})
+	l.mu.Unlock()
 
+	child.tee(ContextField{Key: key, Value: value})
 	return child
 }
 
 // WithFields creates a new logger with multiple additional context fields
 func (l *Logger) WithFields(fields map[string]interface{}) *Logger {
+	if l == nil {
+		return nil
+	}
+
+	for key := range fields {
+		l.checkKey(key)
+	}
+	fields = l.validateFields(fields)
+
 	l.mu.Lock()
-	defer l.mu.Unlock()
 
-	// Create a new logger that shares the same configuration
-	child := &Logger{
-		out:          l.out,
-		level:        l.level,
-		prefix:       l.prefix,
-		timestamp:    l.timestamp,
-		nestingLevel: l.nestingLevel,
-		traceEnabled: l.traceEnabled,
-		indentString: l.indentString,
-		jsonFormat:   l.jsonFormat,
-		callerInfo:   l.callerInfo,
-		colorEnabled: l.colorEnabled,
-		closer:       l.closer,
+	// Frozen loggers keep their context as is
+	if l.frozen {
+		l.mu.Unlock()
+		return l
 	}
 
+	// Create a new logger that shares the same configuration
+	child := l.newChild()
+
 	// Clone the context if it exists, or create a new one
 	child.context = l.context.Clone()
 	if child.context == nil {
 		child.context = &LogContext{}
 	}
 
-	// Add all the new fields to the context
+	// Add all the new fields to the context, keeping their order for tees
+	var added []ContextField
+	if len(child.tees) > 0 {
+		added = make([]ContextField, 0, len(fields))
+	}
 	for k, v := range fields {
 		child.context.Add(k, v)
+		child.accumulator.record(ContextField{Key: k, Value: v})
+		if added != nil {
+			added = append(added, ContextField{Key: k, Value: v})
+		}
+	}
+	l.mu.Unlock()
+
+	child.tee(added...)
+	return child
+}
+
+// With creates a new logger with the given context fields, such as those
+// built by Hex or Base64
+func (l *Logger) With(fields ...ContextField) *Logger {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	validator := l.validator
+	mode := l.validation
+	l.mu.Unlock()
+
+	valid := make([]ContextField, 0, len(fields))
+	for _, field := range fields {
+		l.checkKey(field.Key)
+		if l.applyValidator(validator, mode, field.Key, field.Value) {
+			valid = append(valid, field)
+		}
 	}
 
+	l.mu.Lock()
+
+	// Frozen loggers keep their context as is
+	if l.frozen {
+		l.mu.Unlock()
+		return l
+	}
+
+	child := l.newChild()
+	child.context = l.context.Clone()
+	child.context.append(valid...)
+	child.accumulator.record(valid...)
+	l.mu.Unlock()
+
+	child.tee(valid...)
 	return child
 }
 
 // WithoutContext creates a new logger without the specified context key
 func (l *Logger) WithoutContext(key string) *Logger {
+	if l == nil {
+		return nil
+	}
+
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
-	// Create a new logger that shares the same configuration
-	child := &Logger{
-		out:          l.out,
-		level:        l.level,
-		prefix:       l.prefix,
-		timestamp:    l.timestamp,
-		nestingLevel: l.nestingLevel,
-		traceEnabled: l.traceEnabled,
-		indentString: l.indentString,
-		jsonFormat:   l.jsonFormat,
-		callerInfo:   l.callerInfo,
-		colorEnabled: l.colorEnabled,
-		closer:       l.closer,
+	// Frozen loggers keep their context as is
+	if l.frozen {
+		return l
 	}
 
+	// Create a new logger that shares the same configuration
+	child := l.newChild()
+
 	// Clone the context if it exists
 	child.context = l.context.Clone()
 	if child.context != nil {
@@ -140,3 +333,216 @@ func (l *Logger) WithoutContext(key string) *Logger {
 
 	return child
 }
+
+// WithContextImmutable creates a new logger whose context can no longer be
+// changed. WithContext, WithFields, WithoutContext and WithError called on the
+// returned logger return it unchanged, which makes it suitable for audit or
+// compliance loggers that must keep a fixed set of fields.
+func (l *Logger) WithContextImmutable() *Logger {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	child := l.newChild()
+	child.context = l.context.Clone()
+	child.frozen = true
+
+	return child
+}
+
+// IsFrozen reports whether the logger's context is immutable
+func (l *Logger) IsFrozen() bool {
+	if l == nil {
+		return false
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.frozen
+}
+
+// WithContextJSON creates a new logger with a context field holding the JSON
+// encoding of v. The value is marshaled immediately, so later changes to v are
+// not reflected in the log output.
+func (l *Logger) WithContextJSON(key string, v interface{}) (*Logger, error) {
+	if l == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return l, fmt.Errorf("failed to marshal context field %q: %w", key, err)
+	}
+	return l.WithContext(key, json.RawMessage(data)), nil
+}
+
+// MustWithContextJSON is like WithContextJSON but panics if v cannot be marshaled
+func (l *Logger) MustWithContextJSON(key string, v interface{}) *Logger {
+	child, err := l.WithContextJSON(key, v)
+	if err != nil {
+		panic(err)
+	}
+	return child
+}
+
+// WithCachedJSON creates a new logger with a context field holding the JSON
+// encoding of v. v is marshaled once, here, and the cached encoding is reused
+// by every entry the new logger and its children write, so a request object
+// attached once costs a single json.Marshal however often it is logged. If v
+// cannot be marshaled the field holds a description of the error instead.
+func (l *Logger) WithCachedJSON(key string, v interface{}) *Logger {
+	child, err := l.WithContextJSON(key, v)
+	if err != nil {
+		return l.WithContext(key, fmt.Sprintf("<%v>", err))
+	}
+	return child
+}
+
+// secureValue is a context value emitted only at specific levels
+type secureValue struct {
+	value  interface{}
+	levels []Level
+}
+
+// allowed reports whether the value may be emitted at level
+func (v secureValue) allowed(level Level) bool {
+	for _, l := range v.levels {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
+
+// WithContextSecure creates a new logger with a context field that is only
+// emitted for entries whose level is one of allowedLevels. At any other level
+// the field is left out, so sensitive details can show up in debug logs
+// without leaking into production-level output.
+func (l *Logger) WithContextSecure(key string, value interface{}, allowedLevels ...Level) *Logger {
+	return l.WithContext(key, secureValue{value: value, levels: allowedLevels})
+}
+
+// callerFunction returns the full name of the function skip frames above its caller
+func callerFunction(skip int) string {
+	pc, _, _, ok := runtime.Caller(skip + 1)
+	if !ok {
+		return "unknown"
+	}
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return "unknown"
+	}
+	return fn.Name()
+}
+
+// WithCallerPackage creates a new logger with the name of the calling
+// function's package (e.g. "db" for github.com/acme/svc/internal/db) as a
+// "package" context field. The caller is captured when this method is called.
+func (l *Logger) WithCallerPackage() *Logger {
+	name := callerFunction(1)
+	return l.WithContext("package", path.Base(packagePath(name)))
+}
+
+// WithCallerFuncName creates a new logger with the calling function's name,
+// without its package path (e.g. "(*Pool).Acquire"), as a "function" context
+// field. The caller is captured when this method is called.
+func (l *Logger) WithCallerFuncName() *Logger {
+	name := callerFunction(1)
+	if pkg := packagePath(name); len(name) > len(pkg) {
+		name = name[len(pkg)+1:]
+	}
+	return l.WithContext("function", name)
+}
+
+// fieldValidator checks a context field before it is added
+type fieldValidator func(key string, value interface{}) error
+
+// ValidationMode controls what happens when a context field fails validation
+type ValidationMode int
+
+const (
+	// ValidationModeWarn logs a warning and keeps the field as is
+	ValidationModeWarn ValidationMode = iota
+	// ValidationModeDrop logs a warning and leaves the field out
+	ValidationModeDrop
+	// ValidationModePanic panics with the validation error
+	ValidationModePanic
+)
+
+// WithValidationMode sets how context fields rejected by a validator are handled
+func WithValidationMode(mode ValidationMode) Option {
+	return func(l *Logger) {
+		l.validation = mode
+	}
+}
+
+// WithContextValidator creates a new logger that checks every field added
+// with WithContext or WithFields using fn. When fn returns an error a warning
+// about the schema violation is logged and the field is kept, dropped or
+// causes a panic depending on the logger's ValidationMode.
+func (l *Logger) WithContextValidator(fn func(key string, value interface{}) error) *Logger {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	child := l.newChild()
+	child.context = l.context.Clone()
+	child.validator = fn
+
+	return child
+}
+
+// validateField runs the validator on a field and reports whether it should be added
+func (l *Logger) validateField(key string, value interface{}) bool {
+	l.mu.Lock()
+	validator := l.validator
+	mode := l.validation
+	l.mu.Unlock()
+
+	return l.applyValidator(validator, mode, key, value)
+}
+
+// validateFields runs the validator on every field and returns the ones that should be added
+func (l *Logger) validateFields(fields map[string]interface{}) map[string]interface{} {
+	l.mu.Lock()
+	validator := l.validator
+	mode := l.validation
+	l.mu.Unlock()
+
+	if validator == nil {
+		return fields
+	}
+
+	valid := make(map[string]interface{}, len(fields))
+	for k, v := range fields {
+		if l.applyValidator(validator, mode, k, v) {
+			valid[k] = v
+		}
+	}
+	return valid
+}
+
+// applyValidator validates a field and handles a failure according to mode
+func (l *Logger) applyValidator(validator fieldValidator, mode ValidationMode, key string, value interface{}) bool {
+	if validator == nil {
+		return true
+	}
+
+	err := validator(key, value)
+	if err == nil {
+		return true
+	}
+
+	if mode == ValidationModePanic {
+		panic(fmt.Sprintf("context field %q failed validation: %v", key, err))
+	}
+
+	l.Warn("context field %q failed validation: %v", key, err)
+	return mode != ValidationModeDrop
+}
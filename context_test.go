@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"sync"
 	"testing"
 )
 
@@ -193,3 +194,285 @@ func TestContextIsIndependent(t *testing.T) {
 		t.Errorf("Second logger should not contain first logger's context")
 	}
 }
+
+func TestContextSharedBackingArray(t *testing.T) {
+	parent := New(WithOutput(&bytes.Buffer{})).WithContext("a", 1).WithContext("b", 2)
+
+	// The first child extends the parent's array in place, the second copies it
+	first := parent.WithContext("c", "first")
+	second := parent.WithContext("c", "second")
+	grandchild := first.WithContext("d", 4)
+	removed := first.WithoutContext("a")
+
+	check := func(name string, l *Logger, want string) {
+		t.Helper()
+		var got []string
+		for _, field := range l.context.Fields {
+			got = append(got, fmt.Sprintf("%s=%v", field.Key, field.Value))
+		}
+		if strings.Join(got, " ") != want {
+			t.Errorf("%s context = %v, want %s", name, got, want)
+		}
+	}
+	check("parent", parent, "a=1 b=2")
+	check("first", first, "a=1 b=2 c=first")
+	check("second", second, "a=1 b=2 c=second")
+	check("grandchild", grandchild, "a=1 b=2 c=first d=4")
+	check("removed", removed, "c=first b=2")
+}
+
+func TestContextConcurrentDerivation(t *testing.T) {
+	parent := New(WithOutput(&bytes.Buffer{})).WithContext("service", "api")
+
+	var wg sync.WaitGroup
+	children := make([]*Logger, 16)
+	for i := range children {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			children[i] = parent.WithContext("worker", i).WithContext("step", i*10)
+		}(i)
+	}
+	wg.Wait()
+
+	for i, child := range children {
+		fields := child.context.Fields
+		if len(fields) != 3 || fields[1].Value != i || fields[2].Value != i*10 {
+			t.Errorf("Child %d has context %v", i, fields)
+		}
+	}
+}
+
+func TestWithContextImmutable(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false))
+
+	audit := l.WithContext("audit", true).WithContextImmutable()
+	if !audit.IsFrozen() {
+		t.Fatalf("Expected immutable logger to be frozen")
+	}
+	if l.IsFrozen() {
+		t.Errorf("Expected parent logger to stay mutable")
+	}
+
+	if got := audit.WithContext("user_id", 42); got != audit {
+		t.Errorf("Expected WithContext on frozen logger to return the same logger")
+	}
+	if got := audit.WithFields(map[string]interface{}{"a": 1}); got != audit {
+		t.Errorf("Expected WithFields on frozen logger to return the same logger")
+	}
+	if got := audit.WithoutContext("audit"); got != audit {
+		t.Errorf("Expected WithoutContext on frozen logger to return the same logger")
+	}
+	if got := audit.WithError(fmt.Errorf("boom")); got != audit {
+		t.Errorf("Expected WithError on frozen logger to return the same logger")
+	}
+
+	audit.WithContext("user_id", 42).Info("Access granted")
+
+	output := buf.String()
+	if !strings.Contains(output, "audit: true") {
+		t.Errorf("Expected frozen context in output, got: %s", output)
+	}
+	if strings.Contains(output, "user_id") {
+		t.Errorf("Expected no added context on frozen logger, got: %s", output)
+	}
+}
+
+func TestWithContextJSON(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false))
+
+	type request struct {
+		Method string `json:"method"`
+		Path   string `json:"path"`
+	}
+	req := &request{Method: "GET", Path: "/users"}
+
+	reqLogger, err := l.WithContextJSON("request", req)
+	if err != nil {
+		t.Fatalf("WithContextJSON returned error: %v", err)
+	}
+
+	// Later changes must not affect the snapshot
+	req.Path = "/admin"
+	reqLogger.Info("Request received")
+
+	output := buf.String()
+	if !strings.Contains(output, `request: {"method":"GET","path":"/users"}`) {
+		t.Errorf("Expected JSON snapshot in text output, got: %s", output)
+	}
+
+	// JSON mode embeds the raw value
+	buf.Reset()
+	reqLogger.EnableJSONFormat()
+	reqLogger.Info("Request received")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v", err)
+	}
+	snapshot, ok := entry["context"].(map[string]interface{})["request"].(map[string]interface{})
+	if !ok || snapshot["path"] != "/users" {
+		t.Errorf("Expected embedded JSON object in context, got: %v", entry["context"])
+	}
+}
+
+func TestWithContextJSONError(t *testing.T) {
+	l := New(WithOutput(&bytes.Buffer{}))
+
+	if _, err := l.WithContextJSON("bad", make(chan int)); err == nil {
+		t.Errorf("Expected error for unmarshalable value")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected MustWithContextJSON to panic")
+		}
+	}()
+	l.MustWithContextJSON("bad", make(chan int))
+}
+
+// countingMarshaler counts how often it is marshaled
+type countingMarshaler struct {
+	calls *int
+}
+
+func (m countingMarshaler) MarshalJSON() ([]byte, error) {
+	*m.calls++
+	return []byte(`{"id":1}`), nil
+}
+
+func TestWithCachedJSON(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false), WithJSONFormat(true))
+
+	var calls int
+	reqLogger := l.WithCachedJSON("request", countingMarshaler{calls: &calls})
+	reqLogger.Info("started")
+	reqLogger.Info("validated")
+	reqLogger.WithContext("status", 200).Info("completed")
+
+	if calls != 1 {
+		t.Errorf("Expected a single marshal, got %d", calls)
+	}
+	if got := strings.Count(buf.String(), `"request":{"id":1}`); got != 3 {
+		t.Errorf("Expected cached value in every entry, got %d in: %s", got, buf.String())
+	}
+
+	buf.Reset()
+	l.WithCachedJSON("bad", make(chan int)).Info("unmarshalable")
+	if !strings.Contains(buf.String(), `"bad":"\u003cfailed to marshal context field`) {
+		t.Errorf("Expected marshal error in field, got: %s", buf.String())
+	}
+}
+
+func TestWithContextSecure(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false), WithLevel(DebugLevel)).
+		WithContextSecure("internal_id", "db-7", DebugLevel).
+		WithContext("request_id", "abc")
+
+	l.Debug("Looking up record")
+	if !strings.Contains(buf.String(), "internal_id: db-7") {
+		t.Errorf("Expected secure field at allowed level, got: %s", buf.String())
+	}
+
+	buf.Reset()
+	l.Info("Record found")
+	output := buf.String()
+	if strings.Contains(output, "internal_id") {
+		t.Errorf("Expected secure field to be omitted at other levels, got: %s", output)
+	}
+	if !strings.Contains(output, "request_id: abc") {
+		t.Errorf("Expected regular fields to be kept, got: %s", output)
+	}
+
+	buf.Reset()
+	l.EnableJSONFormat()
+	l.Warn("Slow lookup")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v", err)
+	}
+	if _, ok := entry["context"].(map[string]interface{})["internal_id"]; ok {
+		t.Errorf("Expected secure field to be omitted from JSON, got: %v", entry["context"])
+	}
+}
+
+type callerPackageTester struct{}
+
+func (callerPackageTester) log(l *Logger) {
+	l.WithCallerPackage().WithCallerFuncName().Info("from method")
+}
+
+func TestWithCallerPackage(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false))
+
+	callerPackageTester{}.log(l)
+
+	output := buf.String()
+	if !strings.Contains(output, "package: dy") {
+		t.Errorf("Expected package name in output, got: %s", output)
+	}
+	if !strings.Contains(output, "function: callerPackageTester.log") {
+		t.Errorf("Expected function name without package path, got: %s", output)
+	}
+}
+
+func positiveIDValidator(key string, value interface{}) error {
+	if key != "user_id" {
+		return nil
+	}
+	if id, ok := value.(int); !ok || id <= 0 {
+		return fmt.Errorf("must be a positive integer, got %v", value)
+	}
+	return nil
+}
+
+func TestWithContextValidator(t *testing.T) {
+	tests := []struct {
+		name      string
+		mode      ValidationMode
+		wantField bool
+	}{
+		{"warn", ValidationModeWarn, true},
+		{"drop", ValidationModeDrop, false},
+	}
+
+	for _, test := range tests {
+		var buf bytes.Buffer
+		l := New(WithOutput(&buf), WithTimestamp(false), WithValidationMode(test.mode)).
+			WithContextValidator(positiveIDValidator)
+
+		l.WithContext("user_id", -1).Info("lookup")
+		l.WithFields(map[string]interface{}{"user_id": "abc", "region": "eu"}).Info("batch")
+
+		output := buf.String()
+		if !strings.Contains(output, `[WARN] context field "user_id" failed validation: must be a positive integer, got -1`) {
+			t.Errorf("%s: expected validation warning, got: %s", test.name, output)
+		}
+		if got := strings.Contains(output, "lookup {user_id: -1}"); got != test.wantField {
+			t.Errorf("%s: field kept = %v, want %v, got: %s", test.name, got, test.wantField, output)
+		}
+		if !strings.Contains(output, "region: eu") {
+			t.Errorf("%s: expected valid field from WithFields to be kept, got: %s", test.name, output)
+		}
+	}
+}
+
+func TestWithContextValidatorPanic(t *testing.T) {
+	l := New(WithOutput(&bytes.Buffer{}), WithValidationMode(ValidationModePanic)).
+		WithContextValidator(positiveIDValidator)
+
+	l.WithContext("user_id", 7) // valid, must not panic
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected panic in ValidationModePanic")
+		}
+	}()
+	l.WithContext("user_id", 0)
+}
@@ -3,7 +3,10 @@ package dy
 import (
 	"errors"
 	"fmt"
+	"reflect"
+	"regexp"
 	"runtime"
+	"runtime/debug"
 	"strings"
 )
 
@@ -15,28 +18,128 @@ type ErrorData struct {
 	Cause      *ErrorData             `json:"cause,omitempty"`
 	Attributes map[string]interface{} `json:"attributes,omitempty"`
 	Code       string                 `json:"code,omitempty"`
+	Matches    []string               `json:"matches,omitempty"` // Registered sentinels the error matches
+	GroupKey   string                 `json:"group_key,omitempty"`
 }
 
 // StackFrame represents a single frame in the error stack trace
 type StackFrame struct {
-	Function string `json:"function"`
-	File     string `json:"file"`
-	Line     int    `json:"line"`
+	Function      string   `json:"function"`
+	File          string   `json:"file"`
+	Line          int      `json:"line"`
+	Source        string   `json:"source,omitempty"`         // Source line, see WithSourceSnippets
+	SourceContext []string `json:"source_context,omitempty"` // Numbered lines around Source
 }
 
-// WithError creates a new logger with detailed error information in context
+// WithError creates a new logger with detailed error information in context.
+// A nil error is a no-op and returns the same logger.
 func (l *Logger) WithError(err error) *Logger {
+	if l == nil {
+		return nil
+	}
+
 	if err == nil {
 		return l
 	}
 
 	// Create the error data structure
-	errData := extractErrorData(err, 3) // Skip 3 frames to get to the actual caller
+	errData := extractErrorData(err, 3, l.errorStackConfig()) // Skip 3 frames to get to the actual caller
 
 	// Create a new logger with the error data in context
 	return l.WithContext("error", errData)
 }
 
+// WithErrorNoStack is like WithError but does not capture a stack trace,
+// keeping the message, type, code, attributes and causes at a lower cost
+func (l *Logger) WithErrorNoStack(err error) *Logger {
+	if l == nil {
+		return nil
+	}
+
+	if err == nil {
+		return l
+	}
+
+	stack := l.errorStackConfig()
+	stack.noErrorStacks = true
+	return l.WithContext("error", extractErrorData(err, 0, stack))
+}
+
+// WithErrorCaptureStack controls whether WithError captures a stack trace.
+// It is enabled by default.
+func WithErrorCaptureStack(enable bool) Option {
+	return func(l *Logger) {
+		l.stack.noErrorStacks = !enable
+	}
+}
+
+// errorStackConfig returns the stack settings used for error stacks
+func (l *Logger) errorStackConfig() stackConfig {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stack := l.stack
+	if l.jsonFormat && !stack.snippetsInJSON {
+		stack.snippets = 0
+	}
+	return stack
+}
+
+// StackTrace is a captured stack attached to a log entry. It is emitted as an
+// array of frames in JSON and as a single compact line in text output.
+type StackTrace []StackFrame
+
+// String renders the stack as "function (file:line) <- ..." with the innermost frame first
+func (st StackTrace) String() string {
+	parts := make([]string, len(st))
+	for i, frame := range st {
+		parts[i] = fmt.Sprintf("%s (%s:%d)", frame.Function, frame.File, frame.Line)
+	}
+	return strings.Join(parts, " <- ")
+}
+
+// WithAutoStack creates a new logger that attaches a stack trace as a "stack"
+// context field to every entry at or above minLevel, without requiring an
+// error. The number of frames is capped by WithMaxStackFrames.
+func (l *Logger) WithAutoStack(minLevel Level) *Logger {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	child := l.newChild()
+	child.context = l.context.Clone()
+	child.autoStack = true
+	child.autoStackLevel = minLevel
+
+	return child
+}
+
+// WithStackTraceLevel attaches a stack trace as a "stack" context field to
+// every entry at or above level, like WithAutoStack
+func WithStackTraceLevel(level Level) Option {
+	return func(l *Logger) {
+		l.autoStack = true
+		l.autoStackLevel = level
+	}
+}
+
+// WithStack creates a new logger with the current stack attached as a "stack"
+// context field, e.g. to see who called a deprecated function. The stack is
+// captured here rather than when an entry is logged, and honours the stack
+// frame options. Entries from the new logger do not get a second stack from
+// WithAutoStack.
+func (l *Logger) WithStack() *Logger {
+	if l == nil {
+		return nil
+	}
+
+	stack := StackTrace(captureStack(2, l.errorStackConfig())) // skip WithStack
+	return l.WithContext("stack", stack)
+}
+
 // WithErrorCode adds an error code to a logger with error
 func (l *Logger) WithErrorCode(code string) *Logger {
 	if l == nil {
@@ -78,12 +181,122 @@ func (l *Logger) WithErrorCode(code string) *Logger {
 	return l.WithContext("error_code", code)
 }
 
+// withoutErrorStacks returns fields with the stack removed from any error data
+func withoutErrorStacks(fields []ContextField) []ContextField {
+	for i, field := range fields {
+		if data, ok := field.Value.(ErrorData); ok && len(data.Stack) > 0 {
+			data.Stack = nil
+			fields[i].Value = data
+		}
+	}
+	return fields
+}
+
+// errorContextKeys are the context keys used to carry error information
+var errorContextKeys = map[string]bool{
+	"error":      true,
+	"errors":     true,
+	"error_code": true,
+}
+
+// ClearError creates a new logger without any error information attached by
+// WithError or WithErrorCode. Request-scoped loggers can use it so an error
+// from an earlier attempt does not end up in a later success log.
+func (l *Logger) ClearError() *Logger {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Frozen loggers keep their context as is
+	if l.frozen {
+		return l
+	}
+
+	child := l.newChild()
+	child.context = &LogContext{}
+	if l.context != nil {
+		for _, field := range l.context.Fields {
+			if !errorContextKeys[field.Key] {
+				child.context.append(field)
+			}
+		}
+	}
+
+	return child
+}
+
+// defaultMaxCauseDepth is the number of causes kept in an error chain by default
+const defaultMaxCauseDepth = 32
+
+// WithMaxCauseDepth sets how many levels of wrapped causes are extracted from
+// a logged error. Deeper chains end with a "cause chain truncated" cause, as
+// do chains that loop back on themselves.
+func WithMaxCauseDepth(depth int) Option {
+	return func(l *Logger) {
+		l.stack.maxCauseDepth = depth
+	}
+}
+
+// errorIdentity identifies an error value when detecting cycles
+type errorIdentity struct {
+	typ reflect.Type
+	ptr uintptr
+}
+
+// identify returns the identity of pointer-based errors
+func identify(err error) (errorIdentity, bool) {
+	v := reflect.ValueOf(err)
+	if v.Kind() != reflect.Ptr {
+		return errorIdentity{}, false
+	}
+	return errorIdentity{typ: v.Type(), ptr: v.Pointer()}, true
+}
+
+// chainTerminates reports whether unwrapping err reaches nil within maxDepth
+// steps without revisiting an error
+func chainTerminates(err error, maxDepth int) bool {
+	visited := make(map[errorIdentity]bool)
+	for depth := 0; err != nil; depth++ {
+		if depth > maxDepth {
+			return false
+		}
+		if id, ok := identify(err); ok {
+			if visited[id] {
+				return false
+			}
+			visited[id] = true
+		}
+		err = errors.Unwrap(err)
+	}
+	return true
+}
+
 // extractErrorData extracts structured data from an error
-func extractErrorData(err error, skip int) ErrorData {
+func extractErrorData(err error, skip int, stack stackConfig) ErrorData {
 	if err == nil {
 		return ErrorData{}
 	}
 
+	maxDepth := stack.maxCauseDepth
+	if maxDepth <= 0 {
+		maxDepth = defaultMaxCauseDepth
+	}
+
+	// Errors that never finish unwrapping would hang errors.Is
+	safe := chainTerminates(err, maxDepth)
+	return extractErrorChain(err, skip+1, stack, maxDepth, safe, make(map[errorIdentity]bool)) // skip extractErrorData
+}
+
+// extractErrorChain extracts err and up to depth of its causes, stopping at
+// any error already in visited
+func extractErrorChain(err error, skip int, stack stackConfig, depth int, safe bool, visited map[errorIdentity]bool) ErrorData {
+	if id, ok := identify(err); ok {
+		visited[id] = true
+	}
+
 	// Create the base error data
 	errData := ErrorData{
 		Message:    err.Error(),
@@ -92,29 +305,210 @@ func extractErrorData(err error, skip int) ErrorData {
 	}
 
 	// Capture stack trace if enabled
-	errData.Stack = captureStack(skip)
+	if !stack.noErrorStacks {
+		errData.Stack = captureStack(skip, stack)
+	}
 
 	// Handle wrapped errors (from Go 1.13+)
-	var cause error
-	if errors.Unwrap(err) != nil {
-		cause = errors.Unwrap(err)
-		causeData := extractErrorData(cause, 0) // Don't skip frames for cause
-		errData.Cause = &causeData
+	if cause := errors.Unwrap(err); cause != nil {
+		id, ok := identify(cause)
+		if depth <= 0 || (ok && visited[id]) {
+			errData.Cause = &ErrorData{Message: "cause chain truncated"}
+		} else {
+			causeData := extractErrorChain(cause, 0, stack, depth-1, safe, visited) // Don't skip frames for cause
+			errData.Cause = &causeData
+		}
 	}
 
 	// Extract additional attributes from custom error types
 	extractErrorAttributes(&errData, err)
 
+	// Classify the error against registered sentinels
+	if safe {
+		errData.Matches = matchSentinels(err)
+	}
+
 	return errData
 }
 
-// captureStack captures the current stack trace
-func captureStack(skip int) []StackFrame {
-	const depth = 32
-	var pcs [depth]uintptr
+// defaultMaxStackFrames is the number of frames kept in a stack trace by default
+const defaultMaxStackFrames = 16
+
+// stackConfig controls which frames captureStack keeps
+type stackConfig struct {
+	filter       func(frame StackFrame) bool // Returns false to drop a frame
+	skipPrefixes []string                    // Package or file prefixes to drop
+	maxFrames    int                         // Maximum frames kept, 0 for no limit
+	paths        pathConfig                  // How frame file paths are rendered
+
+	snippets       int  // Number of top frames that get a source snippet
+	snippetContext int  // Lines of context around each snippet
+	snippetsInJSON bool // Allow snippets when the logger writes JSON
+
+	maxCauseDepth int  // Maximum wrapped causes extracted from an error, 0 for the default
+	noErrorStacks bool // Don't capture stacks when extracting error data
+}
+
+// WithStackFrameFilter sets a function deciding which stack frames are kept.
+// The filter is called for every frame that survives the built-in runtime and
+// standard library filtering; returning false drops the frame.
+func WithStackFrameFilter(filter func(frame StackFrame) bool) Option {
+	return func(l *Logger) {
+		l.stack.filter = filter
+	}
+}
+
+// WithStackSkipPrefixes drops stack frames whose function or file starts with
+// any of the given prefixes, e.g. "github.com/acme/pkg/middleware"
+func WithStackSkipPrefixes(prefixes ...string) Option {
+	return func(l *Logger) {
+		l.stack.skipPrefixes = append(l.stack.skipPrefixes, prefixes...)
+	}
+}
+
+// WithCallerRegex creates a new logger whose error stacks keep only frames
+// with a file path matching pattern. It replaces any WithStackFrameFilter and
+// returns an error if the pattern does not compile.
+func (l *Logger) WithCallerRegex(pattern string) (*Logger, error) {
+	return l.WithCallerRegexAll(pattern)
+}
+
+// WithCallerRegexAll is like WithCallerRegex but keeps frames whose file path
+// matches every pattern
+func (l *Logger) WithCallerRegexAll(patterns ...string) (*Logger, error) {
+	if l == nil {
+		return nil, nil
+	}
+
+	regexps, err := compilePatterns(patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	return l.withStackFilter(func(frame StackFrame) bool {
+		for _, re := range regexps {
+			if !re.MatchString(frame.File) {
+				return false
+			}
+		}
+		return true
+	}), nil
+}
+
+// WithCallerRegexAny is like WithCallerRegex but keeps frames whose file path
+// matches at least one pattern
+func (l *Logger) WithCallerRegexAny(patterns ...string) (*Logger, error) {
+	if l == nil {
+		return nil, nil
+	}
+
+	regexps, err := compilePatterns(patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	return l.withStackFilter(func(frame StackFrame) bool {
+		for _, re := range regexps {
+			if re.MatchString(frame.File) {
+				return true
+			}
+		}
+		return false
+	}), nil
+}
+
+// compilePatterns compiles each pattern, failing on the first invalid one
+func compilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	regexps := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid caller pattern %q: %w", pattern, err)
+		}
+		regexps = append(regexps, re)
+	}
+	return regexps, nil
+}
+
+// withStackFilter creates a new logger using filter for error stack frames
+func (l *Logger) withStackFilter(filter func(frame StackFrame) bool) *Logger {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	child := l.newChild()
+	child.context = l.context.Clone()
+	child.stack.filter = filter
+	return child
+}
+
+// WithMaxStackFrames sets the maximum number of frames kept in a stack trace.
+// A value of 0 or less keeps every frame.
+func WithMaxStackFrames(n int) Option {
+	return func(l *Logger) {
+		if n < 0 {
+			n = 0
+		}
+		l.stack.maxFrames = n
+	}
+}
+
+// isStdlibFrame reports whether the frame belongs to the runtime or the
+// standard library. Unlike file paths, package paths also work for binaries
+// built with -trimpath.
+func isStdlibFrame(frame runtime.Frame) bool {
+	return isStdlibPackage(packagePath(frame.Function), readBuildInfo())
+}
+
+// isStdlibPackage reports whether pkg is a standard library package: its
+// first path element has no dot, and it is neither package main nor part of
+// a module the binary was built from, such as a main module declared as
+// "module myapp"
+func isStdlibPackage(pkg string, info *debug.BuildInfo) bool {
+	if pkg == "" || pkg == "main" {
+		return false
+	}
+	if first, _, _ := strings.Cut(pkg, "/"); strings.Contains(first, ".") {
+		return false
+	}
+	if info == nil {
+		return true
+	}
 
-	// +2 to skip captureStack and extractErrorData
-	n := runtime.Callers(skip+2, pcs[:])
+	modules := append([]*debug.Module{&info.Main}, info.Deps...)
+	for _, m := range modules {
+		if m.Path != "" && (pkg == m.Path || strings.HasPrefix(pkg, m.Path+"/")) {
+			return false
+		}
+	}
+	return true
+}
+
+// keep reports whether a frame should be included in the captured stack
+func (c stackConfig) keep(frame StackFrame) bool {
+	for _, prefix := range c.skipPrefixes {
+		if strings.HasPrefix(frame.Function, prefix) || strings.HasPrefix(frame.File, prefix) {
+			return false
+		}
+	}
+	if c.filter != nil {
+		return c.filter(frame)
+	}
+	return true
+}
+
+// captureStack captures the current stack trace
+func captureStack(skip int, config stackConfig) []StackFrame {
+	// Grow the buffer until it holds the whole stack
+	pcs := make([]uintptr, 32)
+	var n int
+	for {
+		// +1 to skip captureStack itself; runtime.Callers counts itself as 0
+		n = runtime.Callers(skip+1, pcs)
+		if n < len(pcs) {
+			break
+		}
+		pcs = make([]uintptr, len(pcs)*2)
+	}
 	frames := runtime.CallersFrames(pcs[:n])
 
 	stack := make([]StackFrame, 0, n)
@@ -123,15 +517,24 @@ func captureStack(skip int) []StackFrame {
 		frame, more := frames.Next()
 
 		// Skip runtime and standard library frames
-		if !strings.Contains(frame.File, "runtime/") && !strings.HasPrefix(frame.File, "/usr/local/go/") {
-			stack = append(stack, StackFrame{
+		if !isStdlibFrame(frame) {
+			sf := StackFrame{
 				Function: frame.Function,
-				File:     frame.File,
+				File:     config.paths.trim(frame.File, frame.Function),
 				Line:     frame.Line,
-			})
+			}
+			if config.keep(sf) {
+				if len(stack) < config.snippets {
+					if snippet, ok := readSnippet(frame.File, frame.Line, config.snippetContext); ok {
+						sf.Source = snippet.line
+						sf.SourceContext = snippet.context
+					}
+				}
+				stack = append(stack, sf)
+			}
 		}
 
-		if !more || len(stack) >= 16 { // Limit to 16 frames
+		if !more || (config.maxFrames > 0 && len(stack) >= config.maxFrames) {
 			break
 		}
 	}
@@ -184,6 +587,8 @@ func extractErrorAttributes(data *ErrorData, err error) {
 			data.Attributes[k] = v
 		}
 	}
+
+	data.GroupKey = groupKey(err, data.Code)
 }
 
 // Define some error interfaces for users to implement
@@ -200,11 +605,18 @@ type ErrorWithFields interface {
 	Fields() map[string]interface{}
 }
 
+// ErrorWithGroupKey is an interface for errors that choose their own grouping key
+type ErrorWithGroupKey interface {
+	error
+	GroupKey() string
+}
+
 // SimpleError is a basic implementation of error with code and fields
 type SimpleError struct {
 	msg    string
 	code   string
 	fields map[string]interface{}
+	cause  error
 }
 
 // NewError creates a new error with code and optional fields
@@ -216,6 +628,17 @@ func NewError(message string, code string, fields map[string]interface{}) *Simpl
 	}
 }
 
+// NewErrorf creates a new error with code and a formatted message. As with
+// fmt.Errorf, an error formatted with %w becomes the error's cause.
+func NewErrorf(code string, format string, args ...interface{}) *SimpleError {
+	err := fmt.Errorf(format, args...)
+	return &SimpleError{
+		msg:   err.Error(),
+		code:  code,
+		cause: errors.Unwrap(err),
+	}
+}
+
 // Error implements the error interface
 func (e *SimpleError) Error() string {
 	return e.msg
@@ -231,17 +654,38 @@ func (e *SimpleError) Fields() map[string]interface{} {
 	return e.fields
 }
 
+// Unwrap returns the wrapped error, if any
+func (e *SimpleError) Unwrap() error {
+	return e.cause
+}
+
+// WithFields adds context fields to the error and returns it for chaining
+func (e *SimpleError) WithFields(fields map[string]interface{}) *SimpleError {
+	if e.fields == nil {
+		e.fields = make(map[string]interface{}, len(fields))
+	}
+	for k, v := range fields {
+		e.fields[k] = v
+	}
+	return e
+}
+
 // WrapError wraps an existing error with additional context
 func WrapError(err error, message string, code string, fields map[string]interface{}) error {
 	if err == nil {
 		return nil
 	}
 
-	wrapped := &SimpleError{
+	return &SimpleError{
 		msg:    fmt.Sprintf("%s: %s", message, err.Error()),
 		code:   code,
 		fields: fields,
+		cause:  err,
 	}
+}
 
-	return fmt.Errorf("%w", wrapped)
+// WrapErrorf is like WrapError with a formatted message and no fields; use
+// SimpleError.WithFields on the result to attach some
+func WrapErrorf(err error, code string, format string, args ...interface{}) error {
+	return WrapError(err, fmt.Sprintf(format, args...), code, nil)
 }
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"runtime"
+	"runtime/debug"
 	"strings"
 	"testing"
 )
@@ -284,3 +286,435 @@ func TestWrapError(t *testing.T) {
 		t.Errorf("Expected unwrapped error to be original, got: %s", unwrapped.Error())
 	}
 }
+
+func TestWrapErrorf(t *testing.T) {
+	originalErr := errors.New("connection reset")
+	wrapped := WrapErrorf(originalErr, "FETCH_FAILED", "fetch %s after %d attempts", "/users", 3)
+
+	if got, want := wrapped.Error(), "fetch /users after 3 attempts: connection reset"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if coder, ok := wrapped.(ErrorWithCode); !ok || coder.Code() != "FETCH_FAILED" {
+		t.Errorf("Expected code FETCH_FAILED, got %v", wrapped)
+	}
+	if errors.Unwrap(wrapped) != originalErr || !errors.Is(wrapped, originalErr) {
+		t.Errorf("Expected unwrap chain to reach the original error")
+	}
+
+	var simple *SimpleError
+	if !errors.As(wrapped, &simple) {
+		t.Fatal("Expected a *SimpleError")
+	}
+	simple.WithFields(map[string]interface{}{"path": "/users"}).WithFields(map[string]interface{}{"attempts": 3})
+	if fields := simple.Fields(); fields["path"] != "/users" || fields["attempts"] != 3 {
+		t.Errorf("Expected chained fields, got %v", fields)
+	}
+
+	if WrapErrorf(nil, "CODE", "nothing") != nil {
+		t.Error("Expected nil when wrapping a nil error")
+	}
+}
+
+func TestNewErrorf(t *testing.T) {
+	err := NewErrorf("NOT_FOUND", "user %d not found", 42)
+	if err.Error() != "user 42 not found" || err.Code() != "NOT_FOUND" || err.Unwrap() != nil {
+		t.Errorf("Unexpected error: %q code=%q cause=%v", err.Error(), err.Code(), err.Unwrap())
+	}
+
+	cause := errors.New("no rows")
+	err = NewErrorf("NOT_FOUND", "load user: %w", cause)
+	if err.Error() != "load user: no rows" || !errors.Is(err, cause) {
+		t.Errorf("Expected %%w to set the cause, got %q", err.Error())
+	}
+}
+
+// errorStack returns the stack attached to the logger's error context
+func errorStack(t *testing.T, l *Logger) []StackFrame {
+	t.Helper()
+	for _, field := range l.context.Fields {
+		if data, ok := field.Value.(ErrorData); ok && field.Key == "error" {
+			return data.Stack
+		}
+	}
+	t.Fatalf("Expected error data in context")
+	return nil
+}
+
+func TestStackSkipsRuntimeFrames(t *testing.T) {
+	l := New(WithOutput(&bytes.Buffer{}), WithMaxStackFrames(0))
+
+	stack := errorStack(t, l.WithError(errors.New("boom")))
+	if len(stack) == 0 {
+		t.Fatalf("Expected at least one stack frame")
+	}
+	if !strings.HasSuffix(stack[0].Function, "TestStackSkipsRuntimeFrames") {
+		t.Errorf("Expected first frame to be the caller, got: %s", stack[0].Function)
+	}
+	for _, frame := range stack {
+		if strings.HasPrefix(frame.Function, "runtime.") || strings.HasPrefix(frame.Function, "testing.") {
+			t.Errorf("Expected runtime and standard library frames to be skipped, got: %s", frame.Function)
+		}
+	}
+}
+
+func TestStackFrameFilter(t *testing.T) {
+	l := New(
+		WithOutput(&bytes.Buffer{}),
+		WithStackFrameFilter(func(frame StackFrame) bool {
+			return !strings.HasSuffix(frame.Function, "TestStackFrameFilter")
+		}),
+	)
+
+	for _, frame := range errorStack(t, l.WithError(errors.New("boom"))) {
+		if strings.HasSuffix(frame.Function, "TestStackFrameFilter") {
+			t.Errorf("Expected filtered frame to be dropped, got: %s", frame.Function)
+		}
+	}
+}
+
+func TestStackSkipPrefixes(t *testing.T) {
+	l := New(WithOutput(&bytes.Buffer{}), WithStackSkipPrefixes("github.com/zakirkun/dy."))
+
+	if stack := errorStack(t, l.WithError(errors.New("boom"))); len(stack) != 0 {
+		t.Errorf("Expected all package frames to be skipped, got: %v", stack)
+	}
+}
+
+func TestWithCallerRegex(t *testing.T) {
+	l := New(WithOutput(&bytes.Buffer{}))
+
+	filtered, err := l.WithCallerRegex(`correlation_test\.go$`)
+	if err != nil {
+		t.Fatalf("WithCallerRegex returned error: %v", err)
+	}
+	stack := errorStack(t, filtered.WithError(errors.New("boom")))
+	if len(stack) == 0 {
+		t.Fatal("Expected matching frames to be kept")
+	}
+	for _, frame := range stack {
+		if !strings.HasSuffix(frame.File, "correlation_test.go") {
+			t.Errorf("Expected only matching frames, got: %s", frame.File)
+		}
+	}
+
+	none, _ := l.WithCallerRegex(`^no-such-file$`)
+	if stack := errorStack(t, none.WithError(errors.New("boom"))); len(stack) != 0 {
+		t.Errorf("Expected no frames when nothing matches, got: %v", stack)
+	}
+
+	if _, err := l.WithCallerRegex(`(`); err == nil {
+		t.Error("Expected an error for an invalid pattern")
+	}
+}
+
+func TestWithCallerRegexAllAny(t *testing.T) {
+	l := New(WithOutput(&bytes.Buffer{}))
+
+	all, err := l.WithCallerRegexAll(`_test\.go$`, `^correlation`)
+	if err != nil {
+		t.Fatalf("WithCallerRegexAll returned error: %v", err)
+	}
+	if stack := errorStack(t, all.WithError(errors.New("boom"))); len(stack) == 0 {
+		t.Error("Expected frames matching all patterns")
+	}
+
+	all, _ = l.WithCallerRegexAll(`_test\.go$`, `^no-such-file`)
+	if stack := errorStack(t, all.WithError(errors.New("boom"))); len(stack) != 0 {
+		t.Errorf("Expected no frames when one pattern fails, got: %v", stack)
+	}
+
+	any, err := l.WithCallerRegexAny(`^no-such-file`, `_test\.go$`)
+	if err != nil {
+		t.Fatalf("WithCallerRegexAny returned error: %v", err)
+	}
+	if stack := errorStack(t, any.WithError(errors.New("boom"))); len(stack) == 0 {
+		t.Error("Expected frames matching any pattern")
+	}
+
+	if _, err := l.WithCallerRegexAny(`ok`, `[`); err == nil {
+		t.Error("Expected an error for an invalid pattern")
+	}
+}
+
+func TestMaxStackFrames(t *testing.T) {
+	l := New(WithOutput(&bytes.Buffer{}), WithMaxStackFrames(1))
+
+	var stack []StackFrame
+	func() {
+		func() {
+			stack = errorStack(t, l.WithError(errors.New("boom")))
+		}()
+	}()
+
+	if len(stack) != 1 {
+		t.Errorf("Expected exactly 1 stack frame, got %d", len(stack))
+	}
+}
+
+func TestClearError(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false))
+
+	if got := l.WithError(nil); got != l {
+		t.Errorf("Expected WithError(nil) to return the same logger")
+	}
+
+	failed := l.WithContext("request_id", "abc").
+		WithError(errors.New("attempt failed")).
+		WithContext("error_code", "RETRY")
+	cleared := failed.ClearError()
+
+	cleared.Info("Request succeeded")
+	output := buf.String()
+	if strings.Contains(output, "attempt failed") || strings.Contains(output, "RETRY") {
+		t.Errorf("Expected error fields to be cleared, got: %s", output)
+	}
+	if !strings.Contains(output, "request_id: abc") {
+		t.Errorf("Expected other context to be kept, got: %s", output)
+	}
+
+	// JSON output should not carry any error keys either
+	buf.Reset()
+	cleared.EnableJSONFormat()
+	cleared.Info("Request succeeded")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v", err)
+	}
+	context, _ := entry["context"].(map[string]interface{})
+	for _, key := range []string{"error", "errors", "error_code"} {
+		if _, ok := context[key]; ok {
+			t.Errorf("Expected %q to be cleared from JSON context, got: %v", key, context)
+		}
+	}
+	if context["request_id"] != "abc" {
+		t.Errorf("Expected request_id in JSON context, got: %v", context)
+	}
+
+	// The original logger keeps its error
+	buf.Reset()
+	failed.Error("Request failed")
+	if !strings.Contains(buf.String(), "attempt failed") {
+		t.Errorf("Expected original logger to keep its error, got: %s", buf.String())
+	}
+}
+
+func TestWithAutoStack(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false), WithMaxStackFrames(2)).WithAutoStack(ErrorLevel)
+
+	l.Warn("below threshold")
+	if strings.Contains(buf.String(), "stack:") {
+		t.Errorf("Expected no stack below minLevel, got: %s", buf.String())
+	}
+
+	buf.Reset()
+	l.Error("at threshold")
+	output := buf.String()
+	if !strings.Contains(output, "stack: github.com/zakirkun/dy.TestWithAutoStack (correlation_test.go:") {
+		t.Errorf("Expected compact stack in text output, got: %s", output)
+	}
+	if strings.Count(output, "\n") != 1 {
+		t.Errorf("Expected stack on a single line, got: %s", output)
+	}
+
+	buf.Reset()
+	l.EnableJSONFormat()
+	l.Error("json")
+
+	var entry struct {
+		Context struct {
+			Stack []StackFrame `json:"stack"`
+		} `json:"context"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v", err)
+	}
+	if len(entry.Context.Stack) == 0 || len(entry.Context.Stack) > 2 {
+		t.Fatalf("Expected 1-2 stack frames in JSON, got: %v", entry.Context.Stack)
+	}
+	if !strings.HasSuffix(entry.Context.Stack[0].Function, "TestWithAutoStack") {
+		t.Errorf("Expected first frame to be the caller, got: %s", entry.Context.Stack[0].Function)
+	}
+}
+
+// deprecatedHelper logs a warning with the stack of its caller
+func deprecatedHelper(l *Logger) *Logger {
+	return l.WithStack()
+}
+
+func TestWithStack(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false), WithJSONFormat(true), WithStackTraceLevel(ErrorLevel))
+
+	withStack := deprecatedHelper(l)
+	withStack.Error("deprecated call")
+
+	var entry struct {
+		Context struct {
+			Stack []StackFrame `json:"stack"`
+		} `json:"context"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v", err)
+	}
+	if len(entry.Context.Stack) == 0 || !strings.HasSuffix(entry.Context.Stack[0].Function, "deprecatedHelper") {
+		t.Fatalf("Expected stack captured at the WithStack call site, got: %v", entry.Context.Stack)
+	}
+	if strings.Count(buf.String(), `"stack"`) != 1 {
+		t.Errorf("Expected a single stack field, got: %s", buf.String())
+	}
+}
+
+func TestWithStackTraceLevel(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false), WithStackTraceLevel(WarnLevel))
+
+	l.Info("below")
+	l.Warn("at level")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || strings.Contains(lines[0], "stack:") {
+		t.Fatalf("Expected no stack below the level, got: %s", buf.String())
+	}
+	if !strings.Contains(lines[1], "stack: github.com/zakirkun/dy.TestWithStackTraceLevel (correlation_test.go:") {
+		t.Errorf("Expected stack at the level, got: %s", lines[1])
+	}
+}
+
+// selfWrappingError unwraps to itself, as some buggy wrappers do
+type selfWrappingError struct{}
+
+func (e *selfWrappingError) Error() string { return "self" }
+func (e *selfWrappingError) Unwrap() error { return e }
+
+// chainError wraps the next error in a long chain
+type chainError struct {
+	next error
+}
+
+func (e chainError) Error() string { return "link" }
+func (e chainError) Unwrap() error { return e.next }
+
+func TestCyclicErrorChain(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithJSONFormat(true))
+	RegisterSentinels(errors.New("unrelated sentinel"))
+
+	l.WithError(&selfWrappingError{}).Error("cyclic")
+
+	var entry struct {
+		Context struct {
+			Error ErrorData `json:"error"`
+		} `json:"context"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v", err)
+	}
+	cause := entry.Context.Error.Cause
+	if cause == nil || cause.Message != "cause chain truncated" || cause.Cause != nil {
+		t.Errorf("Expected a truncated cause, got %+v", cause)
+	}
+}
+
+func TestMaxCauseDepth(t *testing.T) {
+	var err error = errors.New("root")
+	for i := 0; i < 10; i++ {
+		err = chainError{next: err}
+	}
+
+	l := New(WithOutput(&bytes.Buffer{}), WithMaxCauseDepth(3))
+	data := extractErrorData(err, 0, l.stack)
+
+	depth := 0
+	for cause := data.Cause; cause != nil; cause = cause.Cause {
+		depth++
+		if cause.Cause == nil && cause.Message != "cause chain truncated" {
+			t.Errorf("Expected chain to end with a truncation marker, got %q", cause.Message)
+		}
+	}
+	if depth != 4 {
+		t.Errorf("Expected 3 causes plus the marker, got %d", depth)
+	}
+}
+
+func TestWithErrorNoStack(t *testing.T) {
+	l := New(WithOutput(&bytes.Buffer{}))
+	err := WrapError(errors.New("disk full"), "save failed", "SAVE_FAILED", map[string]interface{}{"path": "/tmp/x"})
+
+	var errData ErrorData
+	for _, field := range l.WithErrorNoStack(err).context.Fields {
+		if data, ok := field.Value.(ErrorData); ok && field.Key == "error" {
+			errData = data
+		}
+	}
+	if errData.Message == "" {
+		t.Fatal("Expected error in context")
+	}
+	if len(errData.Stack) != 0 {
+		t.Errorf("Expected no stack, got %v", errData.Stack)
+	}
+	if errData.Code != "SAVE_FAILED" || errData.Attributes["path"] != "/tmp/x" {
+		t.Errorf("Expected code and attributes, got %+v", errData)
+	}
+	if errData.Cause == nil || errData.Cause.Message != "disk full" {
+		t.Errorf("Expected cause, got %+v", errData.Cause)
+	}
+}
+
+func TestWithErrorCaptureStack(t *testing.T) {
+	l := New(WithOutput(&bytes.Buffer{}), WithErrorCaptureStack(false))
+	if stack := errorStack(t, l.WithError(errors.New("boom"))); len(stack) != 0 {
+		t.Errorf("Expected no stack when capture is disabled, got %v", stack)
+	}
+
+	l = New(WithOutput(&bytes.Buffer{}), WithErrorCaptureStack(true))
+	if stack := errorStack(t, l.WithError(errors.New("boom"))); len(stack) == 0 {
+		t.Error("Expected a stack when capture is enabled")
+	}
+}
+
+func TestIsStdlibPackage(t *testing.T) {
+	info := &debug.BuildInfo{
+		Main: debug.Module{Path: "myapp"},
+		Deps: []*debug.Module{{Path: "internaltools"}},
+	}
+
+	tests := []struct {
+		pkg    string
+		stdlib bool
+	}{
+		{"runtime", true},
+		{"testing", true},
+		{"net/http", true},
+		{"main", false},
+		{"github.com/zakirkun/dy", false},
+		{"myapp", false},
+		{"myapp/handlers", false},
+		{"internaltools/retry", false},
+		{"myapplication", true},
+		{"", false},
+	}
+
+	for _, test := range tests {
+		if got := isStdlibPackage(test.pkg, info); got != test.stdlib {
+			t.Errorf("isStdlibPackage(%q) = %v, want %v", test.pkg, got, test.stdlib)
+		}
+	}
+}
+
+func TestIsStdlibFrame(t *testing.T) {
+	for function, stdlib := range map[string]bool{
+		"runtime.goexit":                             true,
+		"net/http.(*Server).Serve":                   true,
+		"main.handler.func1":                         false,
+		"github.com/zakirkun/dy.(*Logger).WithError": false,
+	} {
+		// The file is ignored, as it does not reveal the GOROOT in -trimpath builds
+		frame := runtime.Frame{Function: function, File: "net/http/server.go"}
+		if got := isStdlibFrame(frame); got != stdlib {
+			t.Errorf("isStdlibFrame(%q) = %v, want %v", function, got, stdlib)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package dy
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// contextCache holds a logger's context fields as prepared and rendered for
+// output, so that entries from the same logger only prepare and render the
+// fields they add themselves. A logger's context never changes once it is
+// created, so the cache is only keyed by what can differ between entries.
+// Each logger has its own cache; child loggers start with an empty one.
+type contextCache struct {
+	mu          sync.Mutex
+	entries     map[contextCacheKey]*renderedContext
+	uncacheable bool // The context holds values that may change between entries
+}
+
+// contextCacheKey identifies a rendering of a context. Secure values and
+// error stacks depend on the level, and the output format can be switched
+// with EnableJSONFormat.
+type contextCacheKey struct {
+	level   Level
+	useJSON bool
+}
+
+// renderedContext is a context prepared for one level and output format
+type renderedContext struct {
+	fields    []ContextField         // Prepared fields, must not be modified
+	values    map[string]interface{} // Fields by key, cloned into each entry
+	parts     []string               // Rendered text parts, in text format only
+	errorData *ErrorData             // Error data rendered apart in text format
+}
+
+// renderedContext returns the cached rendering of the context fields for
+// level, preparing and caching it on first use. It returns nil when the
+// context holds values, such as maps or values implementing LogValuer, that
+// must be evaluated for every entry.
+func (l *Logger) renderedContext(level Level, useJSON bool, fields []ContextField) *renderedContext {
+	cache := l.ctxCache
+	if cache == nil || len(fields) == 0 {
+		return nil
+	}
+	key := contextCacheKey{level: level, useJSON: useJSON}
+
+	cache.mu.Lock()
+	rc, ok := cache.entries[key]
+	uncacheable := cache.uncacheable
+	cache.mu.Unlock()
+	if ok || uncacheable {
+		return rc
+	}
+
+	for _, field := range fields {
+		if !immutableValue(field.Value) {
+			cache.mu.Lock()
+			cache.uncacheable = true
+			cache.mu.Unlock()
+			return nil
+		}
+	}
+
+	// Prepare without holding the cache lock, as preparing may log warnings
+	// through this logger. fields may come from a ContextFieldPool, so the
+	// cache keeps a copy.
+	prepared := l.prepareFields(slices.Clone(fields))
+	rc = &renderedContext{
+		fields: prepared,
+		values: make(map[string]interface{}, len(prepared)),
+	}
+	for _, field := range prepared {
+		rc.values[field.Key] = field.Value
+	}
+	if !useJSON {
+		rc.parts, rc.errorData = textContextParts(nil, prepared)
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if existing, ok := cache.entries[key]; ok {
+		return existing
+	}
+	if cache.entries == nil {
+		cache.entries = make(map[contextCacheKey]*renderedContext)
+	}
+	cache.entries[key] = rc
+	return rc
+}
+
+// prepareEntryFields prepares the fields of an entry and sets its context.
+// The first contextLen fields are the logger's own context and come from the
+// cache when possible. In text format the rendered parts and error data are
+// returned too.
+func (l *Logger) prepareEntryFields(level Level, useJSON bool, entry *LogEntry, fields []ContextField, contextLen int) ([]ContextField, []string, *ErrorData) {
+	rc := l.renderedContext(level, useJSON, fields[:contextLen])
+	if rc == nil {
+		fields = l.prepareFields(fields)
+		if len(fields) > 0 {
+			entry.Context = make(map[string]interface{}, len(fields))
+			for _, field := range fields {
+				entry.Context[field.Key] = field.Value
+			}
+		}
+		if useJSON {
+			return fields, nil, nil
+		}
+		parts, errorData := textContextParts(nil, fields)
+		return fields, parts, errorData
+	}
+
+	extra := fields[contextLen:]
+	if len(extra) == 0 {
+		entry.Context = maps.Clone(rc.values)
+		return rc.fields, rc.parts, rc.errorData
+	}
+
+	extra = l.prepareFields(extra)
+	prepared := make([]ContextField, 0, len(rc.fields)+len(extra))
+	prepared = append(append(prepared, rc.fields...), extra...)
+
+	entry.Context = make(map[string]interface{}, len(prepared))
+	maps.Copy(entry.Context, rc.values)
+	for _, field := range extra {
+		entry.Context[field.Key] = field.Value
+	}
+	if useJSON {
+		return prepared, nil, nil
+	}
+
+	parts := make([]string, len(rc.parts), len(rc.parts)+len(extra))
+	copy(parts, rc.parts)
+	parts, errorData := textContextParts(parts, extra)
+	if errorData == nil {
+		errorData = rc.errorData
+	}
+	return prepared, parts, errorData
+}
+
+// immutableValue reports whether v renders the same way every time: scalars,
+// strings, times, stack traces and error data with such attributes
+func immutableValue(v interface{}) bool {
+	switch value := v.(type) {
+	case nil, string, bool, int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64, float32, float64,
+		time.Time, time.Duration, StackTrace:
+		return true
+	case json.RawMessage:
+		// Raw JSON is treated as immutable, as WithContextJSON and
+		// WithCachedJSON create it from their own encoding
+		return true
+	case ErrorData:
+		return immutableErrorData(value)
+	}
+	return false
+}
+
+// immutableErrorData reports whether data and its causes only hold immutable
+// attribute values
+func immutableErrorData(data ErrorData) bool {
+	for _, v := range data.Attributes {
+		if !immutableValue(v) {
+			return false
+		}
+	}
+	return data.Cause == nil || immutableErrorData(*data.Cause)
+}
@@ -0,0 +1,98 @@
+package dy
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestContextCacheReused(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false)).WithFields(map[string]interface{}{"request_id": "abc"})
+
+	l.Info("first")
+	l.Info("second")
+
+	if got := len(l.ctxCache.entries); got != 1 {
+		t.Errorf("Expected one cached rendering, got %d", got)
+	}
+	want := "[INFO] first {request_id: abc}\n[INFO] second {request_id: abc}\n"
+	if buf.String() != want {
+		t.Errorf("Output = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestContextCacheMutableValues(t *testing.T) {
+	var buf bytes.Buffer
+	tags := map[string]int{"a": 1}
+	l := New(WithOutput(&buf), WithTimestamp(false)).WithContext("tags", tags)
+
+	l.Info("first")
+	tags["b"] = 2
+	l.Info("second")
+
+	if !strings.Contains(buf.String(), "[INFO] second {tags: map[a:1 b:2]}") {
+		t.Errorf("Expected mutable values to be rendered for every entry, got: %s", buf.String())
+	}
+	if !l.ctxCache.uncacheable {
+		t.Error("Expected the context to be marked uncacheable")
+	}
+}
+
+func TestContextCachePerLevelAndFormat(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false), WithLevel(DebugLevel)).
+		WithContextSecure("token", "s3cret", DebugLevel)
+
+	l.Debug("debug")
+	l.Info("info")
+	l.EnableJSONFormat()
+	l.Debug("json")
+
+	output := buf.String()
+	for _, want := range []string{
+		"[DEBUG] debug {token: s3cret}\n",
+		"[INFO] info\n",
+		`"message":"json","context":{"token":"s3cret"}`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output, got: %s", want, output)
+		}
+	}
+}
+
+func TestContextCacheWithEntryFields(t *testing.T) {
+	var buf bytes.Buffer
+	pool := NewContextFieldPool(8)
+	l := New(WithOutput(&buf), WithTimestamp(false)).
+		WithContextPool(pool).
+		WithContext("request_id", "abc").
+		WithAutoStack(ErrorLevel)
+
+	l.Error("failed")
+	l.Info("ok")
+	l.Error("failed again")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 entries, got: %s", buf.String())
+	}
+	for _, i := range []int{0, 2} {
+		if !strings.Contains(lines[i], "{request_id: abc, stack: ") {
+			t.Errorf("Expected cached context followed by the entry's stack, got: %s", lines[i])
+		}
+	}
+	if lines[1] != "[INFO] ok {request_id: abc}" {
+		t.Errorf("Unexpected entry without stack: %q", lines[1])
+	}
+}
+
+func TestContextCacheNotShared(t *testing.T) {
+	l := New(WithOutput(&bytes.Buffer{})).WithContext("a", 1)
+	l.Info("parent")
+
+	child := l.WithContext("b", 2)
+	if child.ctxCache == l.ctxCache || len(child.ctxCache.entries) != 0 {
+		t.Error("Expected child loggers to start with an empty cache")
+	}
+}
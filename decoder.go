@@ -0,0 +1,172 @@
+package dy
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// timestampLayouts are the layouts tried when parsing entry timestamps
+var timestampLayouts = []string{
+	defaultTimeFormat,
+	time.RFC3339Nano,
+	time.RFC3339,
+}
+
+// maxDecodeLineSize is the longest line an EntryDecoder decodes; longer lines
+// are reported as malformed
+const maxDecodeLineSize = 1024 * 1024
+
+// MalformedLineError is returned by EntryDecoder.Next for a line that is not
+// a valid JSON log entry. Decoding can continue with the next call to Next.
+type MalformedLineError struct {
+	Line int    // 1-based line number
+	Raw  string // The line as read
+	Err  error  // The underlying parse error
+}
+
+// Error implements the error interface
+func (e *MalformedLineError) Error() string {
+	return fmt.Sprintf("malformed log entry on line %d: %v", e.Line, e.Err)
+}
+
+// Unwrap returns the underlying parse error
+func (e *MalformedLineError) Unwrap() error {
+	return e.Err
+}
+
+// EntryDecoder reads newline-delimited JSON log entries as written by a
+// logger with JSON output enabled
+type EntryDecoder struct {
+	reader *bufio.Reader
+	buf    []byte
+	line   int
+}
+
+// NewEntryDecoder creates a decoder reading entries from r
+func NewEntryDecoder(r io.Reader) *EntryDecoder {
+	return &EntryDecoder{reader: bufio.NewReaderSize(r, 64*1024)}
+}
+
+// readLine reads the next line without its line ending. Lines longer than
+// maxDecodeLineSize are truncated, the rest being discarded, and reported
+// with tooLong set.
+func (d *EntryDecoder) readLine() (line []byte, tooLong bool, err error) {
+	d.buf = d.buf[:0]
+	for {
+		chunk, isPrefix, err := d.reader.ReadLine()
+		if err != nil {
+			return nil, false, err
+		}
+
+		if room := maxDecodeLineSize - len(d.buf); len(chunk) > room {
+			chunk = chunk[:room]
+			tooLong = true
+		}
+		d.buf = append(d.buf, chunk...)
+
+		if !isPrefix {
+			return d.buf, tooLong, nil
+		}
+	}
+}
+
+// Next returns the next entry, or io.EOF when the input is exhausted. Unknown
+// fields are ignored, Time is set from the timestamp when it uses a known
+// layout or is an epoch number, and an "error" context field is decoded into
+// ErrorData. Blank lines are skipped; for any other line that is not a JSON
+// entry, including lines longer than 1 MiB, Next returns a *MalformedLineError
+// and the caller may keep calling Next.
+func (d *EntryDecoder) Next() (*LogEntry, error) {
+	for {
+		raw, tooLong, err := d.readLine()
+		if err != nil {
+			return nil, err
+		}
+		d.line++
+
+		if tooLong {
+			return nil, &MalformedLineError{Line: d.line, Raw: string(raw), Err: bufio.ErrTooLong}
+		}
+		if len(bytes.TrimSpace(raw)) == 0 {
+			continue
+		}
+
+		entry, err := decodeEntry(raw)
+		if err != nil {
+			return nil, &MalformedLineError{Line: d.line, Raw: string(raw), Err: err}
+		}
+		return entry, nil
+	}
+}
+
+// decodeEntry parses a single JSON log entry
+func decodeEntry(data []byte) (*LogEntry, error) {
+	var shadow struct {
+		LogEntry
+		Timestamp json.RawMessage            `json:"timestamp,omitempty"`
+		Context   map[string]json.RawMessage `json:"context,omitempty"`
+	}
+	if err := json.Unmarshal(data, &shadow); err != nil {
+		return nil, err
+	}
+	if shadow.Level == "" && shadow.Message == "" {
+		return nil, fmt.Errorf("missing level and message")
+	}
+
+	entry := shadow.LogEntry
+
+	// Timestamps are either formatted strings or epoch numbers
+	if len(shadow.Timestamp) > 0 && shadow.Timestamp[0] == '"' {
+		if err := json.Unmarshal(shadow.Timestamp, &entry.Timestamp); err != nil {
+			return nil, fmt.Errorf("invalid timestamp: %w", err)
+		}
+		entry.Time = parseTimestamp(entry.Timestamp)
+	} else if len(shadow.Timestamp) > 0 {
+		t, ok := parseEpoch(string(shadow.Timestamp))
+		if !ok {
+			return nil, fmt.Errorf("invalid timestamp %s", shadow.Timestamp)
+		}
+		entry.Timestamp = string(shadow.Timestamp)
+		entry.Time = t
+		entry.epochTimestamp = true
+	}
+
+	if len(shadow.Context) > 0 {
+		entry.Context = make(map[string]interface{}, len(shadow.Context))
+		for key, value := range shadow.Context {
+			if key == "error" {
+				var errData ErrorData
+				if err := json.Unmarshal(value, &errData); err == nil && errData.Message != "" {
+					entry.Context[key] = errData
+					continue
+				}
+			}
+
+			var v interface{}
+			if err := json.Unmarshal(value, &v); err != nil {
+				return nil, fmt.Errorf("invalid context field %q: %w", key, err)
+			}
+			entry.Context[key] = v
+		}
+	}
+
+	return &entry, nil
+}
+
+// parseTimestamp parses a timestamp written with one of the known layouts,
+// returning the zero time if none matches
+func parseTimestamp(s string) time.Time {
+	if s == "" {
+		return time.Time{}
+	}
+	for _, layout := range timestampLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,110 @@
+package dy
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEntryDecoderRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithJSONFormat(true))
+
+	before := time.Now().Add(-time.Second)
+	l.WithContext("request_id", "abc-123").Info("first")
+	l.WithError(NewError("boom", "E_BOOM", nil)).Error("second")
+
+	d := NewEntryDecoder(&buf)
+
+	entry, err := d.Next()
+	if err != nil {
+		t.Fatalf("Next returned error: %v", err)
+	}
+	if entry.Level != "INFO" || entry.Message != "first" {
+		t.Errorf("Unexpected entry: %+v", entry)
+	}
+	if entry.Context["request_id"] != "abc-123" {
+		t.Errorf("Expected request_id in context, got %v", entry.Context)
+	}
+	if entry.Time.Before(before) {
+		t.Errorf("Expected parsed timestamp, got %v", entry.Time)
+	}
+
+	entry, err = d.Next()
+	if err != nil {
+		t.Fatalf("Next returned error: %v", err)
+	}
+	errData, ok := entry.Context["error"].(ErrorData)
+	if !ok {
+		t.Fatalf("Expected error decoded into ErrorData, got %T", entry.Context["error"])
+	}
+	if errData.Message != "boom" || errData.Code != "E_BOOM" {
+		t.Errorf("Unexpected error data: %+v", errData)
+	}
+
+	if _, err := d.Next(); err != io.EOF {
+		t.Errorf("Expected io.EOF, got %v", err)
+	}
+}
+
+func TestEntryDecoderMalformedLine(t *testing.T) {
+	input := strings.Join([]string{
+		`{"timestamp":"2024-01-02T03:04:05Z","level":"INFO","message":"ok"}`,
+		`not json`,
+		``,
+		`{"level":"WARN","message":"still ok","extra":true}`,
+	}, "\n")
+	d := NewEntryDecoder(strings.NewReader(input))
+
+	entry, err := d.Next()
+	if err != nil || entry.Message != "ok" {
+		t.Fatalf("Expected first entry, got %v, %v", entry, err)
+	}
+	if want := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC); !entry.Time.Equal(want) {
+		t.Errorf("Time = %v, want %v", entry.Time, want)
+	}
+
+	_, err = d.Next()
+	var malformed *MalformedLineError
+	if !errors.As(err, &malformed) {
+		t.Fatalf("Expected MalformedLineError, got %v", err)
+	}
+	if malformed.Line != 2 || malformed.Raw != "not json" {
+		t.Errorf("Unexpected malformed line report: %+v", malformed)
+	}
+
+	entry, err = d.Next()
+	if err != nil || entry.Message != "still ok" {
+		t.Errorf("Expected decoding to continue after a malformed line, got %v, %v", entry, err)
+	}
+}
+
+func TestEntryDecoderLineTooLong(t *testing.T) {
+	long := `{"level":"INFO","message":"` + strings.Repeat("x", maxDecodeLineSize) + `"}`
+	input := long + "\n" + `{"level":"WARN","message":"after"}` + "\n"
+	d := NewEntryDecoder(strings.NewReader(input))
+
+	_, err := d.Next()
+	var malformed *MalformedLineError
+	if !errors.As(err, &malformed) {
+		t.Fatalf("Expected MalformedLineError, got %v", err)
+	}
+	if malformed.Line != 1 || !errors.Is(err, bufio.ErrTooLong) {
+		t.Errorf("Unexpected malformed line report: line %d, %v", malformed.Line, malformed.Err)
+	}
+	if len(malformed.Raw) != maxDecodeLineSize {
+		t.Errorf("Expected the reported line to be truncated, got %d bytes", len(malformed.Raw))
+	}
+
+	entry, err := d.Next()
+	if err != nil || entry.Message != "after" {
+		t.Fatalf("Expected decoding to continue after an overly long line, got %v, %v", entry, err)
+	}
+	if _, err := d.Next(); err != io.EOF {
+		t.Errorf("Expected io.EOF, got %v", err)
+	}
+}
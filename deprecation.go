@@ -0,0 +1,31 @@
+package dy
+
+import "sync"
+
+// warnedFeatures records the features WarnDeprecated has already reported
+var warnedFeatures sync.Map
+
+// WarnDeprecated logs a WARN entry announcing that feature is deprecated in
+// favour of replacement, with deprecated_feature, replacement and
+// type: deprecation fields. Each feature is reported only once per process,
+// however many loggers or call sites use it.
+func (l *Logger) WarnDeprecated(feature, replacement string) {
+	if l == nil {
+		return
+	}
+
+	if _, warned := warnedFeatures.LoadOrStore(feature, true); warned {
+		return
+	}
+
+	l.WithFields(map[string]interface{}{
+		"deprecated_feature": feature,
+		"replacement":        replacement,
+		"type":               "deprecation",
+	}).log(WarnLevel, "%s is deprecated, use %s instead", feature, replacement)
+}
+
+// WarnDeprecated logs a deprecation warning once per feature using the default logger
+func WarnDeprecated(feature, replacement string) {
+	DefaultLogger.WarnDeprecated(feature, replacement)
+}
@@ -0,0 +1,40 @@
+package dy
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWarnDeprecated(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false), WithCallerInfo(true))
+	other := New(WithOutput(&buf), WithTimestamp(false))
+
+	l.WarnDeprecated("config.timeout_ms", "config.timeout")
+	l.WarnDeprecated("config.timeout_ms", "config.timeout")
+	other.WarnDeprecated("config.timeout_ms", "config.timeout")
+
+	output := buf.String()
+	if got := strings.Count(output, "\n"); got != 1 {
+		t.Fatalf("Expected a single warning per feature, got %d: %s", got, output)
+	}
+	for _, want := range []string{
+		"[WARN]",
+		"[deprecation_test.go:",
+		"config.timeout_ms is deprecated, use config.timeout instead",
+		"deprecated_feature: config.timeout_ms",
+		"replacement: config.timeout",
+		"type: deprecation",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output, got: %s", want, output)
+		}
+	}
+
+	buf.Reset()
+	l.WarnDeprecated("api.v1", "api.v2")
+	if !strings.Contains(buf.String(), "api.v1 is deprecated") {
+		t.Errorf("Expected a warning for a different feature, got: %s", buf.String())
+	}
+}
@@ -0,0 +1,74 @@
+package dy
+
+import "fmt"
+
+// dynamicField is a context field whose value is computed for each entry
+type dynamicField struct {
+	key      string
+	fn       func() interface{}
+	minLevel Level
+	hasMin   bool
+}
+
+// WithDynamicField creates a new logger with a field whose value is computed
+// by fn each time an entry at or above the logger's level is written, such
+// as the current memory usage or number of requests in flight. Child loggers
+// inherit it, and adding a provider for the same key replaces it. A
+// panicking provider is recovered and logged as an error placeholder.
+func (l *Logger) WithDynamicField(key string, fn func() interface{}) *Logger {
+	return l.withDynamicField(dynamicField{key: key, fn: fn})
+}
+
+// WithDynamicFieldMinLevel is like WithDynamicField but only calls fn for
+// entries at or above minLevel, for providers too expensive to run for every
+// entry
+func (l *Logger) WithDynamicFieldMinLevel(key string, minLevel Level, fn func() interface{}) *Logger {
+	return l.withDynamicField(dynamicField{key: key, fn: fn, minLevel: minLevel, hasMin: true})
+}
+
+// withDynamicField creates a new logger with field added
+func (l *Logger) withDynamicField(field dynamicField) *Logger {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	child := l.newChild()
+	child.context = l.context.Clone()
+	if field.fn == nil {
+		return child
+	}
+
+	dynamic := make([]dynamicField, 0, len(l.dynamic)+1)
+	for _, existing := range l.dynamic {
+		if existing.key != field.key {
+			dynamic = append(dynamic, existing)
+		}
+	}
+	child.dynamic = append(dynamic, field)
+	return child
+}
+
+// appendDynamicFields appends the values of the providers applying to level
+// to fields
+func appendDynamicFields(fields []ContextField, dynamic []dynamicField, level Level) []ContextField {
+	for _, field := range dynamic {
+		if field.hasMin && level.Severity() < field.minLevel.Severity() {
+			continue
+		}
+		fields = append(fields, ContextField{Key: field.key, Value: field.value()})
+	}
+	return fields
+}
+
+// value calls the provider, returning a placeholder if it panics
+func (field dynamicField) value() (v interface{}) {
+	defer func() {
+		if r := recover(); r != nil {
+			v = fmt.Sprintf("[ERROR: field provider panicked: %v]", r)
+		}
+	}()
+	return field.fn()
+}
@@ -0,0 +1,79 @@
+package dy
+
+import (
+	"bytes"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestWithDynamicField(t *testing.T) {
+	var buf bytes.Buffer
+	var inFlight, calls atomic.Int64
+	l := New(WithOutput(&buf), WithTimestamp(false)).WithDynamicField("in_flight", func() interface{} {
+		calls.Add(1)
+		return inFlight.Load()
+	})
+
+	inFlight.Store(3)
+	l.Info("first")
+	inFlight.Store(7)
+	l.WithContext("child", true).Info("second")
+	l.Debug("below the level")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got: %s", buf.String())
+	}
+	if !strings.Contains(lines[0], "in_flight: 3") || !strings.Contains(lines[1], "in_flight: 7") {
+		t.Errorf("Expected the value computed per entry, got: %s", buf.String())
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("Expected the provider called once per written entry, got %d", got)
+	}
+}
+
+func TestWithDynamicFieldReplace(t *testing.T) {
+	var buf bytes.Buffer
+	base := New(WithOutput(&buf), WithTimestamp(false)).
+		WithDynamicField("color", func() interface{} { return "blue" })
+	base.WithDynamicField("color", func() interface{} { return "green" }).Info("child")
+	base.Info("parent")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || strings.Count(lines[0], "color:") != 1 || !strings.Contains(lines[0], "color: green") {
+		t.Errorf("Expected the child's provider to replace the parent's, got: %s", buf.String())
+	}
+	if !strings.Contains(lines[1], "color: blue") {
+		t.Errorf("Expected the parent's provider unchanged, got: %s", lines[1])
+	}
+}
+
+func TestWithDynamicFieldMinLevel(t *testing.T) {
+	var buf bytes.Buffer
+	var calls int
+	l := New(WithOutput(&buf), WithTimestamp(false)).WithDynamicFieldMinLevel("heap", WarnLevel, func() interface{} {
+		calls++
+		return "12MB"
+	})
+
+	l.Info("info")
+	l.Warn("warn")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if calls != 1 || len(lines) != 2 || strings.Contains(lines[0], "heap") || !strings.Contains(lines[1], "heap: 12MB") {
+		t.Errorf("Expected the provider only called for WARN, got %d calls: %s", calls, buf.String())
+	}
+}
+
+func TestWithDynamicFieldPanic(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false)).WithDynamicField("broken", func() interface{} {
+		panic("no data")
+	})
+
+	l.Info("still logged")
+	if !strings.Contains(buf.String(), "still logged") || !strings.Contains(buf.String(), "broken: [ERROR: field provider panicked: no data]") {
+		t.Errorf("Expected the panic recovered as a placeholder, got: %s", buf.String())
+	}
+}
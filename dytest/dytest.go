@@ -0,0 +1,146 @@
+// Package dytest provides helpers for using dy loggers in tests.
+package dytest
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/zakirkun/dy"
+)
+
+// testWriter forwards complete lines to t.Logf
+type testWriter struct {
+	mu   sync.Mutex
+	t    testing.TB
+	buf  bytes.Buffer
+	done bool // Set once the test has finished
+}
+
+// Write buffers p and logs every complete line
+func (w *testWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf.Write(p)
+	for {
+		line, err := w.buf.ReadString('\n')
+		if err != nil {
+			// Keep the partial line for the next write
+			w.buf.Reset()
+			w.buf.WriteString(line)
+			break
+		}
+		w.logf("%s", strings.TrimSuffix(line, "\n"))
+	}
+	return len(p), nil
+}
+
+// flush logs any partial line left in the buffer
+func (w *testWriter) flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.buf.Len() > 0 {
+		w.logf("%s", w.buf.String())
+		w.buf.Reset()
+	}
+}
+
+// finish marks the test as finished so later lines are dropped
+func (w *testWriter) finish() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.done = true
+}
+
+// logf calls t.Logf unless the test has finished. The caller must hold w.mu.
+func (w *testWriter) logf(format string, args ...interface{}) {
+	if w.done {
+		fmt.Fprintf(os.Stderr, "dytest: dropped log line after %s finished: %s\n", w.t.Name(), fmt.Sprintf(format, args...))
+		return
+	}
+
+	// t.Logf panics if the test finished without us noticing, e.g. when a
+	// goroutine logs between the end of the test and its cleanup
+	defer func() {
+		if r := recover(); r != nil {
+			w.done = true
+			fmt.Fprintf(os.Stderr, "dytest: dropped log line after %s finished: %s\n", w.t.Name(), fmt.Sprintf(format, args...))
+		}
+	}()
+	w.t.Helper()
+	w.t.Logf(format, args...)
+}
+
+// errorf fails the test unless it has finished
+func (w *testWriter) errorf(format string, args ...interface{}) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.done {
+		return
+	}
+	defer func() {
+		if recover() != nil {
+			w.done = true
+		}
+	}()
+	w.t.Errorf(format, args...)
+}
+
+// NewTestLogger creates a logger whose output goes through t.Logf, so it is
+// captured per test and only shown for failing or verbose runs. Color is
+// disabled and the logger is closed when the test finishes. Lines logged by
+// goroutines that outlive the test are dropped with a note on stderr.
+//
+// Example usage:
+//
+//	func TestHandler(t *testing.T) {
+//	    logger := dytest.NewTestLogger(t)
+//	    handler := NewHandler(logger)
+//	    ...
+//	}
+func NewTestLogger(t testing.TB, opts ...dy.Option) *dy.Logger {
+	return newTestLogger(t, false, opts)
+}
+
+// NewStrictTestLogger is like NewTestLogger but also fails the test when an
+// ErrorLevel, DPanicLevel or FatalLevel entry is logged. Custom levels never
+// fail the test.
+func NewStrictTestLogger(t testing.TB, opts ...dy.Option) *dy.Logger {
+	return newTestLogger(t, true, opts)
+}
+
+// newTestLogger creates a test logger, failing the test on error logs if failing is set
+func newTestLogger(t testing.TB, failing bool, opts []dy.Option) *dy.Logger {
+	w := &testWriter{t: t}
+
+	hook := func(entry *dy.LogEntry) {
+		if !failing {
+			return
+		}
+		switch dy.ParseLevel(entry.Level) {
+		case dy.ErrorLevel, dy.DPanicLevel, dy.FatalLevel:
+			w.errorf("dytest: unexpected %s log: %s", entry.Level, entry.Message)
+		}
+	}
+
+	defaults := []dy.Option{
+		dy.WithOutput(w),
+		dy.WithColor(false),
+		dy.WithEntryHook(hook),
+	}
+	logger := dy.New(append(defaults, opts...)...)
+
+	t.Cleanup(func() {
+		logger.Close()
+		w.flush()
+		w.finish()
+	})
+
+	return logger
+}
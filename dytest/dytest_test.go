@@ -0,0 +1,117 @@
+package dytest
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/zakirkun/dy"
+)
+
+// fakeTB records calls made by NewTestLogger
+type fakeTB struct {
+	testing.TB
+	mu       sync.Mutex
+	logs     []string
+	errors   []string
+	cleanups []func()
+}
+
+func (f *fakeTB) Helper()      {}
+func (f *fakeTB) Name() string { return "TestFake" }
+
+func (f *fakeTB) Logf(format string, args ...interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.logs = append(f.logs, fmt.Sprintf(format, args...))
+}
+
+func (f *fakeTB) Errorf(format string, args ...interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+func (f *fakeTB) Cleanup(fn func()) {
+	f.cleanups = append(f.cleanups, fn)
+}
+
+func (f *fakeTB) finish() {
+	for i := len(f.cleanups) - 1; i >= 0; i-- {
+		f.cleanups[i]()
+	}
+}
+
+func TestNewTestLogger(t *testing.T) {
+	tb := &fakeTB{}
+	logger := NewTestLogger(tb, dy.WithTimestamp(false))
+
+	logger.WithContext("user_id", 42).Info("signed in")
+	logger.Error("not fatal without NewStrictTestLogger")
+
+	if len(tb.logs) != 2 || tb.logs[0] != "[INFO] signed in {user_id: 42}" {
+		t.Errorf("Expected lines forwarded to Logf, got: %q", tb.logs)
+	}
+	if len(tb.errors) != 0 {
+		t.Errorf("Expected no test failures, got: %q", tb.errors)
+	}
+	if len(tb.cleanups) != 1 {
+		t.Errorf("Expected a cleanup to be registered, got %d", len(tb.cleanups))
+	}
+}
+
+func TestNewTestLoggerPartialLines(t *testing.T) {
+	tb := &fakeTB{}
+	w := &testWriter{t: tb}
+
+	w.Write([]byte("first li"))
+	w.Write([]byte("ne\nsecond"))
+	if len(tb.logs) != 1 || tb.logs[0] != "first line" {
+		t.Errorf("Expected only the complete line, got: %q", tb.logs)
+	}
+
+	w.flush()
+	if len(tb.logs) != 2 || tb.logs[1] != "second" {
+		t.Errorf("Expected partial line on flush, got: %q", tb.logs)
+	}
+}
+
+func TestNewStrictTestLogger(t *testing.T) {
+	tb := &fakeTB{}
+	logger := NewStrictTestLogger(tb, dy.WithTimestamp(false))
+
+	logger.Warn("fine")
+	logger.Error("database unreachable")
+
+	if len(tb.errors) != 1 || !strings.Contains(tb.errors[0], "database unreachable") {
+		t.Errorf("Expected test to be failed on error log, got: %q", tb.errors)
+	}
+}
+
+func TestNewStrictTestLoggerCustomLevel(t *testing.T) {
+	const alertLevel dy.Level = 1000 // Ranks above FatalLevel
+	if err := dy.RegisterLevel(alertLevel, "DYTEST_ALERT", ""); err != nil {
+		t.Fatalf("RegisterLevel returned error: %v", err)
+	}
+
+	tb := &fakeTB{}
+	logger := NewStrictTestLogger(tb, dy.WithTimestamp(false))
+	logger.Log(alertLevel, "paged on-call")
+
+	if len(tb.errors) != 0 {
+		t.Errorf("Expected custom level not to fail the test, got: %q", tb.errors)
+	}
+}
+
+func TestNewTestLoggerAfterFinish(t *testing.T) {
+	tb := &fakeTB{}
+	logger := NewStrictTestLogger(tb, dy.WithTimestamp(false))
+	tb.finish()
+
+	// Logging after the test finished must not reach the TB
+	logger.Error("late goroutine")
+	if len(tb.logs) != 0 || len(tb.errors) != 0 {
+		t.Errorf("Expected late log to be dropped, got logs %q errors %q", tb.logs, tb.errors)
+	}
+}
@@ -0,0 +1,76 @@
+package dy
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Emit writes a pre-built entry, for example one decoded from another
+// process's JSON output, through the logger's formatting and output pipeline.
+// The entry keeps its timestamp, prefix, caller and other attributes, and its
+// context is merged with the logger's, the entry's values winning for keys in
+// both. Level, filters and sampling apply as for any other entry, with
+// WithSampling keying on the message, and hooks and the ring buffer see it
+// once written. Replayed fatal entries do not exit the process. entry itself
+// is not modified. An error is returned for a nil entry or an unknown level.
+func (l *Logger) Emit(entry *LogEntry) error {
+	if l == nil {
+		return nil
+	}
+
+	if entry == nil {
+		return errors.New("cannot emit a nil log entry")
+	}
+	level := ParseLevel(entry.Level)
+	if !strings.EqualFold(level.String(), entry.Level) {
+		return fmt.Errorf("cannot emit log entry with unknown level %q", entry.Level)
+	}
+
+	// Sampling keys on the message since a replayed entry has no format
+	if level.Severity() < l.level.Severity() && l.ring == nil {
+		return nil
+	}
+	if !l.sampler.allow(level, entry.Message) {
+		return nil
+	}
+
+	l.mu.Lock()
+	fields := l.context.appendFieldsFor(l.pool.get(), level)
+	l.mu.Unlock()
+	contextLen := len(fields)
+
+	// Entry fields replace context fields under the same key, in which case
+	// the logger's cached context no longer applies
+	if len(entry.Context) > 0 {
+		kept := fields[:0]
+		for _, field := range fields {
+			if _, ok := entry.Context[field.Key]; !ok {
+				kept = append(kept, field)
+			}
+		}
+		if len(kept) < contextLen {
+			contextLen = 0
+		}
+		fields = kept
+
+		keys := make([]string, 0, len(entry.Context))
+		for key := range entry.Context {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fields = append(fields, ContextField{Key: key, Value: entry.Context[key]})
+		}
+	}
+
+	replay := *entry
+	replay.Context = nil
+	replay.Level = level.String()
+	replay.contextLen = contextLen
+	l.output(level, &replay, fields)
+
+	l.pool.put(fields)
+	return nil
+}
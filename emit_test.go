@@ -0,0 +1,117 @@
+package dy
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEmit(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false)).WithFields(map[string]interface{}{
+		"service": "sidecar",
+		"region":  "local",
+	})
+
+	var seen *LogEntry
+	l.hooks = append(l.hooks, func(entry *LogEntry) { seen = entry })
+
+	entry := &LogEntry{
+		Timestamp: "2024-01-02T03:04:05Z",
+		Level:     "warn",
+		Message:   "disk almost full",
+		Context:   map[string]interface{}{"region": "eu-west-1", "used": 0.93},
+	}
+	if err := l.Emit(entry); err != nil {
+		t.Fatalf("Emit failed: %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{"2024-01-02T03:04:05Z", "[WARN]", "disk almost full", "service: sidecar", "region: eu-west-1", "used: 0.93"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output, got: %s", want, output)
+		}
+	}
+	if strings.Contains(output, "region: local") {
+		t.Errorf("Expected the entry's value to replace the logger's, got: %s", output)
+	}
+
+	if seen == nil || seen.Context["service"] != "sidecar" {
+		t.Errorf("Expected hooks to see the merged entry, got %+v", seen)
+	}
+	if len(entry.Context) != 2 || entry.Level != "warn" {
+		t.Errorf("Expected the emitted entry to be left unchanged, got %+v", entry)
+	}
+}
+
+func TestEmitDecodedJSON(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithJSONFormat(true)).WithContext("forwarded", true)
+
+	var entry LogEntry
+	line := `{"timestamp":"2024-01-02T03:04:05Z","level":"ERROR","message":"payment failed","caller":{"function":"main.pay","file":"pay.go","line":12},"context":{"order_id":"o-1"}}`
+	if err := json.Unmarshal([]byte(line), &entry); err != nil {
+		t.Fatalf("Failed to decode entry: %v", err)
+	}
+	if err := l.Emit(&entry); err != nil {
+		t.Fatalf("Emit failed: %v", err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("Failed to decode output %q: %v", buf.String(), err)
+	}
+	ctx, _ := got["context"].(map[string]interface{})
+	if got["timestamp"] != "2024-01-02T03:04:05Z" || got["message"] != "payment failed" || ctx["order_id"] != "o-1" || ctx["forwarded"] != true {
+		t.Errorf("Expected the replayed entry with merged context, got: %s", buf.String())
+	}
+	if caller, _ := got["caller"].(map[string]interface{}); caller["function"] != "main.pay" {
+		t.Errorf("Expected the original caller, got: %s", buf.String())
+	}
+}
+
+func TestEmitLevelFilter(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithLevel(WarnLevel))
+
+	if err := l.Emit(&LogEntry{Level: "INFO", Message: "filtered"}); err != nil {
+		t.Fatalf("Emit failed: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Expected entries below the level to be dropped, got: %s", buf.String())
+	}
+
+	// Replayed fatal entries are written without exiting
+	if err := l.Emit(&LogEntry{Level: "FATAL", Message: "replayed"}); err != nil {
+		t.Fatalf("Emit failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "replayed") {
+		t.Errorf("Expected the fatal entry to be written, got: %s", buf.String())
+	}
+}
+
+func TestEmitSampling(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false), WithSampling(2, 0, time.Minute))
+
+	for i := 0; i < 5; i++ {
+		if err := l.Emit(&LogEntry{Level: "INFO", Message: "replayed"}); err != nil {
+			t.Fatalf("Emit failed: %v", err)
+		}
+	}
+	if got := strings.Count(buf.String(), "replayed"); got != 2 {
+		t.Errorf("Expected sampling to keep the first 2 entries, got %d", got)
+	}
+}
+
+func TestEmitErrors(t *testing.T) {
+	l := New(WithOutput(&bytes.Buffer{}))
+	if err := l.Emit(nil); err == nil {
+		t.Error("Expected an error for a nil entry")
+	}
+	if err := l.Emit(&LogEntry{Level: "LOUD", Message: "x"}); err == nil {
+		t.Error("Expected an error for an unknown level")
+	}
+}
@@ -0,0 +1,138 @@
+package dy
+
+import (
+	"strconv"
+	"time"
+)
+
+// DurationEncoding controls how time.Duration field values are rendered
+type DurationEncoding int
+
+const (
+	// DurationEncodingString renders durations like "1.5s"
+	DurationEncodingString DurationEncoding = iota + 1
+	// DurationEncodingSeconds renders durations as fractional seconds
+	DurationEncodingSeconds
+	// DurationEncodingMillis renders durations as fractional milliseconds
+	DurationEncodingMillis
+	// DurationEncodingNanos renders durations as integer nanoseconds
+	DurationEncodingNanos
+)
+
+// TimeEncodingEpoch is a WithTimeEncoding layout rendering times as
+// milliseconds since the Unix epoch
+const TimeEncodingEpoch = "epoch"
+
+// valueEncoding holds the encodings applied to field values
+type valueEncoding struct {
+	durations  DurationEncoding // 0 keeps the default rendering
+	timeLayout string           // Empty keeps the default rendering
+	bytes      BytesEncoding    // 0 keeps the default rendering
+}
+
+// WithDurationEncoding sets how time.Duration values in context fields, error
+// attributes and trace elapsed times are rendered in both output formats. By
+// default durations are written as nanoseconds in JSON and as strings in text.
+func WithDurationEncoding(enc DurationEncoding) Option {
+	return func(l *Logger) {
+		l.encoding.durations = enc
+	}
+}
+
+// WithTimeEncoding sets the layout used for time.Time values in context
+// fields and error attributes, or TimeEncodingEpoch for epoch milliseconds.
+// Times follow WithUTC. By default times use their standard encoding.
+func WithTimeEncoding(layout string) Option {
+	return func(l *Logger) {
+		l.encoding.timeLayout = layout
+	}
+}
+
+// enabled reports whether any value encoding is configured
+func (e valueEncoding) enabled() bool {
+	return e.durations != 0 || e.timeLayout != "" || e.bytes != 0
+}
+
+// encodeFields returns a copy of fields with time, duration and bytes values encoded
+func (e valueEncoding) encodeFields(fields []ContextField, utc bool) []ContextField {
+	result := make([]ContextField, len(fields))
+	for i, field := range fields {
+		result[i] = ContextField{Key: field.Key, Value: e.encodeValue(field.Value, utc)}
+	}
+	return result
+}
+
+// encodeValue encodes a single value, descending into error attributes
+func (e valueEncoding) encodeValue(v interface{}, utc bool) interface{} {
+	switch value := v.(type) {
+	case time.Duration:
+		return e.encodeDuration(value)
+	case time.Time:
+		if e.timeLayout == "" {
+			return value
+		}
+		if e.timeLayout == TimeEncodingEpoch {
+			return value.UnixMilli()
+		}
+		if utc {
+			value = value.UTC()
+		}
+		return value.Format(e.timeLayout)
+	case []byte:
+		if e.bytes != 0 {
+			return encodeBytes(value, e.bytes)
+		}
+	case ErrorData:
+		return e.encodeErrorData(value, utc)
+	}
+	return v
+}
+
+// encodeDuration renders d according to the duration encoding
+func (e valueEncoding) encodeDuration(d time.Duration) interface{} {
+	switch e.durations {
+	case DurationEncodingString:
+		return d.String()
+	case DurationEncodingSeconds:
+		return d.Seconds()
+	case DurationEncodingMillis:
+		return float64(d) / float64(time.Millisecond)
+	case DurationEncodingNanos:
+		return int64(d)
+	}
+	return d
+}
+
+// encodeErrorData returns a copy of data with encoded attributes, including
+// those of its causes
+func (e valueEncoding) encodeErrorData(data ErrorData, utc bool) ErrorData {
+	if len(data.Attributes) > 0 {
+		attrs := make(map[string]interface{}, len(data.Attributes))
+		for k, v := range data.Attributes {
+			attrs[k] = e.encodeValue(v, utc)
+		}
+		data.Attributes = attrs
+	}
+	if data.Cause != nil {
+		cause := e.encodeErrorData(*data.Cause, utc)
+		data.Cause = &cause
+	}
+	return data
+}
+
+// formatElapsed renders a trace elapsed time according to the duration encoding
+func (l *Logger) formatElapsed(d time.Duration) string {
+	l.mu.Lock()
+	enc := l.encoding
+	l.mu.Unlock()
+
+	switch v := enc.encodeDuration(d).(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case string:
+		return v
+	}
+	return d.String()
+}
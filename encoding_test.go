@@ -0,0 +1,87 @@
+package dy
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithDurationEncoding(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		json string
+		text string
+	}{
+		{"default", nil, `"took":1500000000`, "took: 1.5s"},
+		{"string", []Option{WithDurationEncoding(DurationEncodingString)}, `"took":"1.5s"`, "took: 1.5s"},
+		{"seconds", []Option{WithDurationEncoding(DurationEncodingSeconds)}, `"took":1.5`, "took: 1.5"},
+		{"millis", []Option{WithDurationEncoding(DurationEncodingMillis)}, `"took":1500`, "took: 1500"},
+		{"nanos", []Option{WithDurationEncoding(DurationEncodingNanos)}, `"took":1500000000`, "took: 1500000000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			opts := append([]Option{WithOutput(&buf), WithTimestamp(false), WithJSONFormat(true)}, tt.opts...)
+			l := New(opts...).WithContext("took", 1500*time.Millisecond)
+
+			l.Info("done")
+			if !strings.Contains(buf.String(), tt.json) {
+				t.Errorf("Expected %s in JSON output, got: %s", tt.json, buf.String())
+			}
+
+			buf.Reset()
+			l.DisableJSONFormat()
+			l.Info("done")
+			if !strings.Contains(buf.String(), tt.text) {
+				t.Errorf("Expected %q in text output, got: %s", tt.text, buf.String())
+			}
+		})
+	}
+}
+
+func TestWithTimeEncoding(t *testing.T) {
+	at := time.Date(2025, 1, 15, 14, 6, 40, 0, time.FixedZone("CET", 3600))
+
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false), WithJSONFormat(true), WithTimeEncoding(time.Kitchen), WithUTC(true))
+	l.WithContext("at", at).Info("layout")
+	if !strings.Contains(buf.String(), `"at":"1:06PM"`) {
+		t.Errorf("Expected time in configured layout and UTC, got: %s", buf.String())
+	}
+
+	buf.Reset()
+	l = New(WithOutput(&buf), WithTimestamp(false), WithJSONFormat(true), WithTimeEncoding(TimeEncodingEpoch))
+	l.WithContext("at", at).Info("epoch")
+	if !strings.Contains(buf.String(), `"at":1736946400000`) {
+		t.Errorf("Expected epoch milliseconds, got: %s", buf.String())
+	}
+
+	buf.Reset()
+	l = New(WithOutput(&buf), WithTimestamp(false), WithJSONFormat(true))
+	l.WithContext("at", at).Info("default")
+	if !strings.Contains(buf.String(), `"at":"2025-01-15T14:06:40+01:00"`) {
+		t.Errorf("Expected default RFC3339 encoding, got: %s", buf.String())
+	}
+}
+
+func TestEncodingErrorAttributesAndTrace(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false), WithJSONFormat(true), WithLevel(DebugLevel),
+		WithTrace(true), WithDurationEncoding(DurationEncodingMillis))
+
+	err := NewError("slow", "SLOW", map[string]interface{}{"timeout": 250 * time.Millisecond})
+	l.WithError(err).Error("failed")
+	if !strings.Contains(buf.String(), `"timeout":250`) {
+		t.Errorf("Expected encoded duration in error attributes, got: %s", buf.String())
+	}
+
+	buf.Reset()
+	l.TraceFunction()()
+	if !regexp.MustCompile(`"elapsed_time":"\d+(\.\d+)?"`).MatchString(buf.String()) {
+		t.Errorf("Expected elapsed time in milliseconds, got: %s", buf.String())
+	}
+}
@@ -0,0 +1,86 @@
+package dy
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// EpochUnit is the unit of numeric timestamps, see WithEpochTimestamps
+type EpochUnit int
+
+const (
+	// EpochSeconds writes timestamps as seconds since the Unix epoch
+	EpochSeconds EpochUnit = iota + 1
+	// EpochMillis writes timestamps as milliseconds since the Unix epoch
+	EpochMillis
+	// EpochNanos writes timestamps as nanoseconds since the Unix epoch
+	EpochNanos
+)
+
+// WithEpochTimestamps makes JSON output write the timestamp as a number of
+// units since the Unix epoch, e.g. "timestamp":1736950000123 for EpochMillis.
+// Text output keeps the formatted timestamp, and WithUTC has no effect on
+// epoch values.
+func WithEpochTimestamps(unit EpochUnit) Option {
+	return func(l *Logger) {
+		l.epoch = unit
+	}
+}
+
+// stamp sets the entry's timestamp for time t
+func (l *Logger) stamp(entry *LogEntry, t time.Time) {
+	l.mu.Lock()
+	unit := l.epoch
+	useJSON := l.jsonFormat
+	l.mu.Unlock()
+
+	if unit == 0 || !useJSON {
+		entry.Timestamp = l.formatTime(t)
+		return
+	}
+
+	var n int64
+	switch unit {
+	case EpochSeconds:
+		n = t.Unix()
+	case EpochMillis:
+		n = t.UnixMilli()
+	default:
+		n = t.UnixNano()
+	}
+	entry.Timestamp = strconv.FormatInt(n, 10)
+	entry.epochTimestamp = true
+}
+
+// MarshalJSON encodes the entry, writing epoch timestamps as numbers
+func (e LogEntry) MarshalJSON() ([]byte, error) {
+	type plain LogEntry
+	if !e.epochTimestamp {
+		return json.Marshal(plain(e))
+	}
+
+	return json.Marshal(struct {
+		Timestamp json.Number `json:"timestamp,omitempty"`
+		plain
+	}{json.Number(e.Timestamp), plain(e)})
+}
+
+// parseEpoch parses a numeric timestamp, inferring its unit from its magnitude
+func parseEpoch(s string) (time.Time, bool) {
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	switch {
+	case n < 1e11:
+		return time.Unix(n, 0), true
+	case n < 1e14:
+		return time.UnixMilli(n), true
+	case n < 1e17:
+		return time.UnixMicro(n), true
+	default:
+		return time.Unix(0, n), true
+	}
+}
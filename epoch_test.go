@@ -0,0 +1,67 @@
+package dy
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithEpochTimestamps(t *testing.T) {
+	tests := []struct {
+		unit    EpochUnit
+		pattern string
+	}{
+		{EpochSeconds, `^\{"timestamp":\d{10},"level"`},
+		{EpochMillis, `^\{"timestamp":\d{13},"level"`},
+		{EpochNanos, `^\{"timestamp":\d{19},"level"`},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		l := New(WithOutput(&buf), WithJSONFormat(true), WithUTC(true), WithEpochTimestamps(tt.unit))
+		before := time.Now().Add(-time.Second)
+		l.Info("epoch")
+
+		if !regexp.MustCompile(tt.pattern).Match(buf.Bytes()) {
+			t.Errorf("Unit %d: expected numeric timestamp, got: %s", tt.unit, buf.String())
+		}
+
+		entry, err := NewEntryDecoder(&buf).Next()
+		if err != nil {
+			t.Fatalf("Unit %d: failed to decode entry: %v", tt.unit, err)
+		}
+		if entry.Time.Before(before) || entry.Time.After(time.Now()) {
+			t.Errorf("Unit %d: decoded time %v out of range", tt.unit, entry.Time)
+		}
+	}
+}
+
+func TestEpochTimestampsTextMode(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithEpochTimestamps(EpochMillis))
+	l.Info("text")
+
+	if !regexp.MustCompile(`^\d{4}-\d{2}-\d{2} `).MatchString(buf.String()) {
+		t.Errorf("Expected formatted timestamp in text mode, got: %s", buf.String())
+	}
+}
+
+func TestHumanizeEpochTimestamps(t *testing.T) {
+	input := `{"timestamp":1736950000123,"level":"INFO","message":"epoch"}` + "\n" +
+		`{"timestamp":"2025-01-15 14:06:40.123","level":"INFO","message":"string"}`
+
+	var out bytes.Buffer
+	if err := Humanize(strings.NewReader(input), &out); err != nil {
+		t.Fatalf("Humanize returned error: %v", err)
+	}
+
+	want := time.UnixMilli(1736950000123).Format(defaultTimeFormat) + " [INFO] epoch\n"
+	if !strings.HasPrefix(out.String(), want) {
+		t.Errorf("Expected epoch rendered as formatted time, got: %q", out.String())
+	}
+	if !strings.Contains(out.String(), "2025-01-15 14:06:40.123 [INFO] string") {
+		t.Errorf("Expected string timestamp passed through, got: %q", out.String())
+	}
+}
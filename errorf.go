@@ -0,0 +1,62 @@
+package dy
+
+import "fmt"
+
+// WriteAndReturn creates an error with fmt.Errorf, logs its message at level
+// with the error attached as by WithError, and returns it:
+//
+//	if len(id) == 0 {
+//	    return l.WriteAndReturn(WarnLevel, "empty id for %s", name)
+//	}
+//
+// The error is returned even when the entry is not logged.
+func (l *Logger) WriteAndReturn(level Level, format string, args ...interface{}) error {
+	err := fmt.Errorf(format, args...)
+	l.logReturned(level, err)
+	return err
+}
+
+// Errorf is WriteAndReturn at ErrorLevel
+func (l *Logger) Errorf(format string, args ...interface{}) error {
+	err := fmt.Errorf(format, args...)
+	l.logReturned(ErrorLevel, err)
+	return err
+}
+
+// Warnf is WriteAndReturn at WarnLevel
+func (l *Logger) Warnf(format string, args ...interface{}) error {
+	err := fmt.Errorf(format, args...)
+	l.logReturned(WarnLevel, err)
+	return err
+}
+
+// NewError creates a SimpleError with code and a formatted message as
+// NewErrorf does, logs it at ErrorLevel and returns it
+func (l *Logger) NewError(code string, format string, args ...interface{}) *SimpleError {
+	err := NewErrorf(code, format, args...)
+	l.logReturned(ErrorLevel, err)
+	return err
+}
+
+// logReturned logs the message of err at level with err attached. It must be
+// called directly by the exported method, so stacks and caller info report
+// that method's caller.
+func (l *Logger) logReturned(level Level, err error) {
+	if l == nil {
+		return
+	}
+
+	if level.Severity() < l.level.Severity() && l.ring == nil {
+		return
+	}
+	msg := err.Error()
+	if !l.sampler.allow(level, msg) {
+		return
+	}
+
+	errData := extractErrorData(err, 4, l.errorStackConfig()) // skip logReturned and the exported method
+	child := l.WithContext("error", errData)
+	entry, fields := child.buildEntry(level, msg, 1) // skip the exported method
+	child.emit(level, entry, fields)
+	child.pool.put(fields)
+}
@@ -0,0 +1,69 @@
+package dy
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestLoggerErrorf(t *testing.T) {
+	var seen *LogEntry
+	l := New(WithOutput(io.Discard), WithCallerInfo(true), WithEntryHook(func(e *LogEntry) { seen = e }))
+
+	err := l.Errorf("open %s: %w", "config.yaml", io.EOF)
+	if err == nil || err.Error() != "open config.yaml: EOF" || !errors.Is(err, io.EOF) {
+		t.Fatalf("Expected the fmt.Errorf error, got %v", err)
+	}
+	if seen == nil || seen.Level != "ERROR" || seen.Message != err.Error() {
+		t.Fatalf("Expected the error logged at ERROR, got %+v", seen)
+	}
+	if !strings.HasSuffix(seen.Caller.File, "errorf_test.go") || !strings.Contains(seen.Caller.Function, "TestLoggerErrorf") {
+		t.Errorf("Expected the caller to be the test, got %+v", seen.Caller)
+	}
+
+	data, ok := seen.Context["error"].(ErrorData)
+	if !ok || data.Message != err.Error() {
+		t.Fatalf("Expected the error attached, got %v", seen.Context["error"])
+	}
+	if len(data.Stack) == 0 || !strings.Contains(data.Stack[0].Function, "TestLoggerErrorf") {
+		t.Errorf("Expected the stack to start at the test, got %+v", data.Stack)
+	}
+}
+
+func TestLoggerWarnf(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false), WithLevel(ErrorLevel))
+
+	// Entries below the level are not logged, the error is still returned
+	if err := l.Warnf("slow query: %dms", 1200); err == nil || err.Error() != "slow query: 1200ms" {
+		t.Errorf("Expected the error returned, got %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Expected nothing logged below the level, got: %s", buf.String())
+	}
+
+	l.SetLevel(WarnLevel)
+	l.Warnf("slow query: %dms", 1300)
+	if !strings.Contains(buf.String(), "[WARN") || !strings.Contains(buf.String(), "slow query: 1300ms") {
+		t.Errorf("Expected the warning logged, got: %s", buf.String())
+	}
+}
+
+func TestLoggerNewError(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithJSONFormat(true))
+
+	err := l.NewError("DB_TIMEOUT", "query %s timed out", "users")
+	if err.Code() != "DB_TIMEOUT" || err.Error() != "query users timed out" {
+		t.Errorf("Expected the SimpleError returned, got %v (%s)", err, err.Code())
+	}
+	if !strings.Contains(buf.String(), `"code":"DB_TIMEOUT"`) {
+		t.Errorf("Expected the error code logged, got: %s", buf.String())
+	}
+
+	if got := l.WriteAndReturn(InfoLevel, "cache miss for %q", "k"); got.Error() != `cache miss for "k"` {
+		t.Errorf("Expected the formatted error, got %v", got)
+	}
+}
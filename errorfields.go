@@ -0,0 +1,53 @@
+package dy
+
+// errorFieldConfig controls how error values in context fields are rendered
+type errorFieldConfig struct {
+	disabled bool // Leave error values to their default encoding
+	stacks   bool // Capture a stack trace for each expanded error
+}
+
+// WithErrorFieldExpansion sets whether context field values implementing
+// error are expanded into structured error data, with their type, code,
+// attributes and causes, instead of being written as a bare message. Expansion
+// is enabled by default.
+func WithErrorFieldExpansion(enable bool) Option {
+	return func(l *Logger) {
+		l.errorFields.disabled = !enable
+	}
+}
+
+// WithErrorFieldStacks sets whether expanded error fields carry a stack
+// trace. The stack is captured where the entry is written. Stacks are left
+// out by default.
+func WithErrorFieldStacks(enable bool) Option {
+	return func(l *Logger) {
+		l.errorFields.stacks = enable
+	}
+}
+
+// expandErrorFields returns fields with error values replaced by ErrorData.
+// fields is returned unchanged when it holds no error values.
+func expandErrorFields(fields []ContextField, config errorFieldConfig, stack stackConfig) []ContextField {
+	if config.disabled {
+		return fields
+	}
+
+	var result []ContextField
+	for i, field := range fields {
+		err, ok := field.Value.(error)
+		if !ok || err == nil {
+			continue
+		}
+		if result == nil {
+			result = make([]ContextField, len(fields))
+			copy(result, fields)
+		}
+		stack.noErrorStacks = !config.stacks
+		result[i].Value = extractErrorData(err, 2, stack) // skip expandErrorFields and prepareFields
+	}
+
+	if result == nil {
+		return fields
+	}
+	return result
+}
@@ -0,0 +1,76 @@
+package dy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestErrorFieldExpansionJSON(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithJSONFormat(true), WithTimestamp(false))
+
+	l.WithContext("cause", NewError("upstream failed", "X", nil)).Info("request failed")
+
+	var entry struct {
+		Context map[string]ErrorData `json:"context"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to parse output %q: %v", buf.String(), err)
+	}
+	cause := entry.Context["cause"]
+	if cause.Message != "upstream failed" || cause.Type != "*dy.SimpleError" || cause.Code != "X" {
+		t.Errorf("Unexpected expanded error: %+v", cause)
+	}
+	if len(cause.Stack) != 0 {
+		t.Errorf("Expected no stack by default, got %d frames", len(cause.Stack))
+	}
+}
+
+func TestErrorFieldExpansionText(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false))
+
+	l.WithContext("cause", NewError("upstream failed", "X", nil)).Info("request failed")
+
+	if got := buf.String(); !strings.Contains(got, "{cause: upstream failed (code=X)}") {
+		t.Errorf("Expected compact error in text output, got: %q", got)
+	}
+}
+
+func TestErrorFieldExpansionDisabled(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithJSONFormat(true), WithTimestamp(false), WithErrorFieldExpansion(false))
+
+	l.WithContext("cause", errors.New("boom")).Info("request failed")
+
+	if !strings.Contains(buf.String(), `"cause":{}`) {
+		t.Errorf("Expected default encoding of the error value, got: %s", buf.String())
+	}
+}
+
+func TestErrorFieldStacks(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithJSONFormat(true), WithTimestamp(false), WithErrorFieldStacks(true))
+
+	l.WithContext("cause", errors.New("boom")).Info("request failed")
+
+	var entry struct {
+		Context map[string]ErrorData `json:"context"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to parse output %q: %v", buf.String(), err)
+	}
+	if len(entry.Context["cause"].Stack) == 0 {
+		t.Error("Expected a stack trace on the expanded error")
+	}
+}
+
+func TestErrorFieldExpansionKeepsOtherFields(t *testing.T) {
+	fields := []ContextField{{Key: "user", Value: "alice"}}
+	if got := expandErrorFields(fields, errorFieldConfig{}, stackConfig{}); &got[0] != &fields[0] {
+		t.Error("Expected fields without errors to be returned unchanged")
+	}
+}
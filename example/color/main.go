@@ -30,6 +30,18 @@ func main() {
 
 	noColorLog.Info("This message has no colors")
 
+	// Highlight one request so it stands out among the others
+	reqLog := logger.New(
+		logger.WithHighlight("request_id", "abc-123", logger.BoldCyan),
+	)
+	reqLog.WithContext("request_id", "xyz-789").Info("Handling another request")
+	reqLog.WithContext("request_id", "abc-123").Info("Handling the request being followed")
+
+	// Highlights can also be added at runtime, e.g. while debugging an incident
+	reqLog.Highlight("user", "alice")
+	reqLog.WithContext("user", "alice").Warn("Payment retried (inverse video)")
+	reqLog.Unhighlight("user", "alice")
+
 	// Example with trace function and colors
 	log.EnableTrace()
 	defer log.TraceFunction("with colors")()
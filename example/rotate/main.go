@@ -56,11 +56,8 @@ func main() {
 
 	// Force a rotation to demonstrate
 	fmt.Println("Forcing log rotation...")
-	// Direct access to the RotateWriter from the logger's output
-	if rw, ok := log.GetOutput().(*logger.RotateWriter); ok {
-		rw.ForceRotate()
-	} else {
-		fmt.Println("Could not access RotateWriter")
+	if err := log.Rotate(); err != nil {
+		fmt.Printf("Could not rotate: %v\n", err)
 	}
 
 	// Log some more after rotation
@@ -85,4 +82,24 @@ func main() {
 	}
 
 	fmt.Println("\nLog rotation demo completed successfully!")
+
+	teeDemo(logsDir)
+}
+
+// teeDemo writes every entry both to a rotated JSON file and, as text, to
+// stdout, as a container would for `kubectl logs` and a node-level collector
+func teeDemo(logsDir string) {
+	log := logger.New(
+		logger.WithRotateWriterTee(filepath.Join(logsDir, "tee.log"), os.Stdout,
+			logger.WithMaxSize(5),        // 5MB max size
+			logger.WithMaxBackups(3),     // Keep 3 backups
+			logger.WithConsoleText(true), // Text on stdout, JSON in the file
+		),
+		logger.WithJSONFormat(true),
+		logger.WithTimestamp(true),
+	)
+	defer log.Close() // Closes the file, stdout stays open
+
+	log.WithContext("pod", "web-7d9f").Info("Logging to stdout and a rotated file")
+	log.Warn("The file holds the same entries in JSON")
 }
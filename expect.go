@@ -0,0 +1,166 @@
+package dy
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Expectation describes a log entry a test expects to find in a logger's ring
+// buffer. It is created by Logger.Expect and checked with Assert or AssertNot.
+type Expectation struct {
+	logger  *Logger
+	level   Level
+	message string
+	fields  []ContextField
+	code    string
+}
+
+// Expect returns an expectation for an entry at level written by l. The
+// logger must record entries in a ring buffer, see WithInMemoryRingBuffer.
+// Narrow the expectation with its With methods and check it once the code
+// under test has run:
+//
+//	log.Expect(dy.ErrorLevel).WithMessage("payment failed").WithErrorCode("DECLINED").Assert(t)
+func (l *Logger) Expect(level Level) *Expectation {
+	return &Expectation{logger: l, level: level}
+}
+
+// WithMessage requires the entry's message to contain the given text
+func (e *Expectation) WithMessage(contains string) *Expectation {
+	e.message = contains
+	return e
+}
+
+// WithField requires the entry to have a context field key equal to value
+func (e *Expectation) WithField(key string, value interface{}) *Expectation {
+	e.fields = append(e.fields, ContextField{Key: key, Value: value})
+	return e
+}
+
+// WithLevel replaces the level the entry must have
+func (e *Expectation) WithLevel(level Level) *Expectation {
+	e.level = level
+	return e
+}
+
+// WithErrorCode requires the entry to carry an error, as added by WithError
+// or an error context field, with the given code
+func (e *Expectation) WithErrorCode(code string) *Expectation {
+	e.code = code
+	return e
+}
+
+// Assert reports a test error unless a matching entry was recorded
+func (e *Expectation) Assert(t testing.TB) {
+	t.Helper()
+
+	entries, ok := e.entries(t)
+	if !ok {
+		return
+	}
+	for _, entry := range entries {
+		if e.matches(entry) {
+			return
+		}
+	}
+	t.Errorf("expected log entry %s, found none among %d entries", e, len(entries))
+}
+
+// AssertNot reports a test error if a matching entry was recorded
+func (e *Expectation) AssertNot(t testing.TB) {
+	t.Helper()
+
+	entries, ok := e.entries(t)
+	if !ok {
+		return
+	}
+	for _, entry := range entries {
+		if e.matches(entry) {
+			t.Errorf("unexpected log entry %s: %q", e, entry.Message)
+			return
+		}
+	}
+}
+
+// entries returns the entries recorded by the logger's ring buffer
+func (e *Expectation) entries(t testing.TB) ([]*LogEntry, bool) {
+	t.Helper()
+
+	if e.logger == nil {
+		t.Errorf("cannot check log expectations: logger is nil")
+		return nil, false
+	}
+	e.logger.mu.Lock()
+	ring := e.logger.ring
+	e.logger.mu.Unlock()
+
+	if ring == nil {
+		t.Errorf("cannot check log expectations: logger has no ring buffer")
+		return nil, false
+	}
+	return ring.Entries(), true
+}
+
+// matches reports whether entry satisfies the expectation
+func (e *Expectation) matches(entry *LogEntry) bool {
+	if entry.Level != e.level.String() {
+		return false
+	}
+	if !strings.Contains(entry.Message, e.message) {
+		return false
+	}
+	for _, field := range e.fields {
+		value, ok := entry.Context[field.Key]
+		if !ok || !valuesEqual(value, field.Value) {
+			return false
+		}
+	}
+	if e.code != "" && !hasErrorCode(entry, e.code) {
+		return false
+	}
+	return true
+}
+
+// String describes the expectation for failure messages
+func (e *Expectation) String() string {
+	parts := []string{"level=" + e.level.String()}
+	if e.message != "" {
+		parts = append(parts, fmt.Sprintf("message~%q", e.message))
+	}
+	for _, field := range e.fields {
+		parts = append(parts, fmt.Sprintf("%s=%v", field.Key, field.Value))
+	}
+	if e.code != "" {
+		parts = append(parts, "code="+e.code)
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+// valuesEqual compares a recorded field value with an expected one, falling
+// back to their printed forms so that e.g. an int matches an int64
+func valuesEqual(got, want interface{}) bool {
+	if reflect.DeepEqual(got, want) {
+		return true
+	}
+	return fmt.Sprint(got) == fmt.Sprint(want)
+}
+
+// hasErrorCode reports whether any error data in entry, or one of its causes,
+// has the given code
+func hasErrorCode(entry *LogEntry, code string) bool {
+	for _, value := range entry.Context {
+		data, ok := value.(ErrorData)
+		for ok {
+			if data.Code == code {
+				return true
+			}
+			if data.Cause == nil {
+				break
+			}
+			data = *data.Cause
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package dy
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+// recordingTB records the errors reported by an expectation
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestExpectAssert(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf)).WithInMemoryRingBuffer(NewRingBuffer(10))
+
+	l.WithContext("order", 42).WithError(NewError("payment failed", "DECLINED", nil)).Error("checkout failed")
+	l.Info("done")
+
+	l.Expect(ErrorLevel).WithMessage("checkout").WithField("order", 42).WithErrorCode("DECLINED").Assert(t)
+	l.Expect(InfoLevel).WithMessage("done").Assert(t)
+	l.Expect(WarnLevel).AssertNot(t)
+	l.Expect(InfoLevel).WithLevel(ErrorLevel).WithErrorCode("OTHER").AssertNot(t)
+
+	rec := &recordingTB{}
+	l.Expect(ErrorLevel).WithField("order", 7).Assert(rec)
+	if len(rec.errors) != 1 {
+		t.Errorf("Expected Assert to report a missing entry, got %v", rec.errors)
+	}
+
+	rec = &recordingTB{}
+	l.Expect(InfoLevel).AssertNot(rec)
+	if len(rec.errors) != 1 {
+		t.Errorf("Expected AssertNot to report an unexpected entry, got %v", rec.errors)
+	}
+}
+
+func TestExpectWithoutRingBuffer(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf))
+	l.Info("hello")
+
+	rec := &recordingTB{}
+	l.Expect(InfoLevel).Assert(rec)
+	if len(rec.errors) != 1 {
+		t.Errorf("Expected an error for a logger without ring buffer, got %v", rec.errors)
+	}
+}
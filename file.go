@@ -0,0 +1,46 @@
+package dy
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// WriteToFile creates a new logger that writes to filename instead of the
+// parent's output, keeping the parent's level, format and context. The file
+// is opened for appending and its directory is created if needed. Closing the
+// returned logger closes the file without affecting the parent.
+//
+// Example usage:
+//
+//	audit, err := logger.WriteToFile("logs/audit.log")
+//	if err != nil {
+//	    return err
+//	}
+//	defer audit.Close()
+func (l *Logger) WriteToFile(filename string) (*Logger, error) {
+	if l == nil {
+		return nil, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	file, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	child := l.newChild()
+	child.context = l.context.Clone()
+	child.out = file
+	child.writeMu = &sync.Mutex{}
+	child.closer = file.Close
+
+	return child, nil
+}
@@ -0,0 +1,42 @@
+package dy
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteToFile(t *testing.T) {
+	var buf bytes.Buffer
+	parent := New(WithOutput(&buf), WithTimestamp(false), WithLevel(WarnLevel)).
+		WithContext("service", "api")
+
+	filename := filepath.Join(t.TempDir(), "nested", "audit.log")
+	audit, err := parent.WriteToFile(filename)
+	if err != nil {
+		t.Fatalf("WriteToFile returned error: %v", err)
+	}
+
+	audit.Info("filtered by inherited level")
+	audit.Warn("user deleted")
+
+	if err := audit.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	if got := string(data); got != "[WARN] user deleted {service: api}\n" {
+		t.Errorf("Unexpected file contents: %q", got)
+	}
+
+	// The parent keeps writing to its own output after the child is closed
+	parent.Warn("still here")
+	if !strings.Contains(buf.String(), "still here") || strings.Contains(buf.String(), "user deleted") {
+		t.Errorf("Expected parent output to be unaffected, got: %s", buf.String())
+	}
+}
@@ -0,0 +1,64 @@
+package dy
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// entryFilter decides whether an entry is written
+type entryFilter func(e *LogEntry) bool
+
+// WithFilter adds a filter evaluated for every entry once its context has
+// been merged, before it is recorded, formatted or passed to hooks. Returning
+// false drops the entry. Filters run in registration order and an entry is
+// kept only if all of them pass; see FilteredCount.
+func WithFilter(filter func(e *LogEntry) bool) Option {
+	return func(l *Logger) {
+		l.filters = append(l.filters, filter)
+	}
+}
+
+// FilteredCount returns the number of entries dropped by filters. The count is
+// shared with child loggers.
+func (l *Logger) FilteredCount() uint64 {
+	if l == nil {
+		return 0
+	}
+
+	return l.filtered.Load()
+}
+
+// currentFilters returns the logger's filters
+func (l *Logger) currentFilters() []entryFilter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.filters
+}
+
+// keep reports whether entry passes every filter, counting it if not
+func (l *Logger) keep(entry *LogEntry, filters []entryFilter) bool {
+	for _, filter := range filters {
+		if !filter(entry) {
+			l.filtered.Add(1)
+			return false
+		}
+	}
+	return true
+}
+
+// FilterFieldEquals returns a filter dropping entries whose context field key
+// renders as value
+func FilterFieldEquals(key string, value interface{}) func(e *LogEntry) bool {
+	want := fmt.Sprint(value)
+	return func(e *LogEntry) bool {
+		v, ok := e.Context[key]
+		return !ok || fmt.Sprint(v) != want
+	}
+}
+
+// FilterMessageMatches returns a filter dropping entries whose message matches re
+func FilterMessageMatches(re *regexp.Regexp) func(e *LogEntry) bool {
+	return func(e *LogEntry) bool {
+		return !re.MatchString(e.Message)
+	}
+}
@@ -0,0 +1,70 @@
+package dy
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestWithFilter(t *testing.T) {
+	var buf bytes.Buffer
+	var hooked int
+	l := New(
+		WithOutput(&buf),
+		WithTimestamp(false),
+		WithFilter(FilterFieldEquals("path", "/healthz")),
+		WithFilter(FilterMessageMatches(regexp.MustCompile(`^noisy`))),
+		WithEntryHook(func(entry *LogEntry) { hooked++ }),
+	)
+
+	// Request-scoped loggers as an HTTP middleware would create them
+	l.WithContext("path", "/healthz").Info("request served")
+	l.WithContext("path", "/api/users").Info("request served")
+	l.WithContext("component", "vendor").Warn("noisy retry")
+
+	output := buf.String()
+	if strings.Contains(output, "/healthz") || strings.Contains(output, "noisy") {
+		t.Errorf("Expected filtered entries to be dropped, got: %s", output)
+	}
+	if !strings.Contains(output, "path: /api/users") {
+		t.Errorf("Expected unfiltered entry to be written, got: %s", output)
+	}
+	if hooked != 1 {
+		t.Errorf("Expected hooks to see only kept entries, got %d calls", hooked)
+	}
+	if got := l.FilteredCount(); got != 2 {
+		t.Errorf("FilteredCount() = %d, want 2", got)
+	}
+}
+
+func TestWithFilterHTTPMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(
+		WithOutput(&buf),
+		WithTimestamp(false),
+		WithTraceIDGenerator(func() string { return "generated" }),
+		WithFilter(FilterFieldEquals("path", "/healthz")),
+		WithFilter(FilterMessageMatches(regexp.MustCompile(`^noisy`))),
+	)
+
+	handler := l.HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger := FromContext(r.Context())
+		logger.Info("request served")
+		logger.Warn("noisy retry")
+	}))
+
+	for _, path := range []string{"/healthz", "/api/users", "/healthz"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	expected := "[INFO] request served {method: GET, path: /api/users, trace_id: generated}\n"
+	if got := buf.String(); got != expected {
+		t.Errorf("Expected only the unfiltered entry, got %q, want %q", got, expected)
+	}
+	if got := l.FilteredCount(); got != 5 {
+		t.Errorf("FilteredCount() = %d, want 5", got)
+	}
+}
@@ -0,0 +1,58 @@
+package dy
+
+import "flag"
+
+// WithFlagsExclude leaves the named flags, e.g. "password" or "token", out of
+// the fields added by WithContextFromFlags and WithContextFromFlagsAll
+func WithFlagsExclude(names ...string) Option {
+	return func(l *Logger) {
+		if l.flagsExclude == nil {
+			l.flagsExclude = make(map[string]bool, len(names))
+		}
+		for _, name := range names {
+			l.flagsExclude[name] = true
+		}
+	}
+}
+
+// WithContextFromFlags creates a new logger with a context field for every
+// flag set on the command line, keyed by flag name with the value as a string.
+// It is meant to record a tool's effective configuration at startup.
+func (l *Logger) WithContextFromFlags(fs *flag.FlagSet) *Logger {
+	if fs == nil {
+		return l
+	}
+	return l.withFlags(fs.Visit)
+}
+
+// WithContextFromFlagsAll is like WithContextFromFlags but includes every
+// defined flag, using the default value for flags that were not set
+func (l *Logger) WithContextFromFlagsAll(fs *flag.FlagSet) *Logger {
+	if fs == nil {
+		return l
+	}
+	return l.withFlags(fs.VisitAll)
+}
+
+// withFlags adds the flags visited by visit as context fields
+func (l *Logger) withFlags(visit func(fn func(*flag.Flag))) *Logger {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	exclude := l.flagsExclude
+	l.mu.Unlock()
+
+	fields := make(map[string]interface{})
+	visit(func(f *flag.Flag) {
+		if !exclude[f.Name] {
+			fields[f.Name] = f.Value.String()
+		}
+	})
+
+	if len(fields) == 0 {
+		return l
+	}
+	return l.WithFields(fields)
+}
@@ -0,0 +1,56 @@
+package dy
+
+import (
+	"bytes"
+	"flag"
+	"strings"
+	"testing"
+)
+
+// newTestFlagSet defines a few flags and parses args
+func newTestFlagSet(t *testing.T, args ...string) *flag.FlagSet {
+	t.Helper()
+	fs := flag.NewFlagSet("tool", flag.ContinueOnError)
+	fs.String("addr", ":8080", "listen address")
+	fs.Int("workers", 4, "number of workers")
+	fs.String("token", "", "API token")
+	if err := fs.Parse(args); err != nil {
+		t.Fatalf("Failed to parse flags: %v", err)
+	}
+	return fs
+}
+
+func TestWithContextFromFlags(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false), WithJSONFormat(true), WithFlagsExclude("token"))
+	fs := newTestFlagSet(t, "-workers", "8", "-token", "s3cret")
+
+	l.WithContextFromFlags(fs).Info("starting")
+
+	output := buf.String()
+	if !strings.Contains(output, `"workers":"8"`) {
+		t.Errorf("Expected set flag as a string field, got: %s", output)
+	}
+	if strings.Contains(output, "addr") {
+		t.Errorf("Expected unset flags to be left out, got: %s", output)
+	}
+	if strings.Contains(output, "s3cret") {
+		t.Errorf("Expected excluded flag to be left out, got: %s", output)
+	}
+}
+
+func TestWithContextFromFlagsAll(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false), WithJSONFormat(true), WithFlagsExclude("token"))
+	fs := newTestFlagSet(t, "-workers", "8")
+
+	l.WithContextFromFlagsAll(fs).Info("starting")
+
+	output := buf.String()
+	if !strings.Contains(output, `"addr":":8080"`) || !strings.Contains(output, `"workers":"8"`) {
+		t.Errorf("Expected all flags with their values, got: %s", output)
+	}
+	if strings.Contains(output, "token") {
+		t.Errorf("Expected excluded flag to be left out, got: %s", output)
+	}
+}
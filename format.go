@@ -0,0 +1,84 @@
+package dy
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// Formatter renders an entry as a log line, without the trailing newline.
+// The entry's context holds the prepared field values.
+type Formatter func(level Level, entry *LogEntry) string
+
+// formatStrategy selects the formatter of each entry
+type formatStrategy struct {
+	text   Formatter                   // Used in text mode, nil for the built-in format
+	json   Formatter                   // Used in JSON mode, nil for the built-in format
+	choose func(level Level) Formatter // Overrides text and json when set
+}
+
+// WithFormatStrategy sets the formatters of the text and the JSON mode. The
+// one matching the JSON setting when an entry is written is used, so
+// switching with EnableJSONFormat switches formatters too. A nil formatter
+// keeps the built-in format of its mode.
+func WithFormatStrategy(textFmt, jsonFmt Formatter) Option {
+	return func(l *Logger) {
+		l.formats.text = textFmt
+		l.formats.json = jsonFmt
+	}
+}
+
+// WithDynamicFormat sets a function choosing the formatter of each entry by
+// its level, taking precedence over WithFormatStrategy. When chooser returns
+// nil the entry gets the built-in format of the current mode.
+func WithDynamicFormat(chooser func(level Level) Formatter) Option {
+	return func(l *Logger) {
+		l.formats.choose = chooser
+	}
+}
+
+// WithTextTemplate formats text mode entries with a text/template parsed
+// from text, executed with the *LogEntry, e.g.
+//
+//	dy.WithTextTemplate(`{{.Level}} {{.Message}}`)
+//
+// A template that fails to parse keeps the built-in format and is reported
+// by NewE.
+func WithTextTemplate(text string) Option {
+	return func(l *Logger) {
+		tmpl, err := template.New("dy").Parse(text)
+		if err != nil {
+			l.optionError(fmt.Errorf("invalid text template: %w", err))
+			return
+		}
+		l.formats.text = TemplateFormatter(tmpl)
+	}
+}
+
+// formatter returns the formatter for an entry at level, or nil for the
+// built-in format
+func (s formatStrategy) formatter(level Level, useJSON bool) Formatter {
+	if s.choose != nil {
+		return s.choose(level)
+	}
+	if useJSON {
+		return s.json
+	}
+	return s.text
+}
+
+// TemplateFormatter returns a Formatter executing tmpl with the entry, e.g.
+//
+//	tmpl := template.Must(template.New("line").Parse(`{{.Level}} {{.Message}} {{index .Context "request_id"}}`))
+//	logger := dy.New(dy.WithFormatStrategy(dy.TemplateFormatter(tmpl), nil))
+//
+// A failing template renders the error instead of the entry.
+func TemplateFormatter(tmpl *template.Template) Formatter {
+	return func(level Level, entry *LogEntry) string {
+		var b strings.Builder
+		if err := tmpl.Execute(&b, entry); err != nil {
+			return "ERROR executing log template: " + err.Error()
+		}
+		return b.String()
+	}
+}
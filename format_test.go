@@ -0,0 +1,72 @@
+package dy
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestWithFormatStrategy(t *testing.T) {
+	tmpl := template.Must(template.New("line").Parse(`{{.Level}}|{{.Message}}|{{index .Context "user"}}`))
+	jsonFmt := func(level Level, e *LogEntry) string {
+		return fmt.Sprintf(`{"lvl":%q,"msg":%q}`, e.Level, e.Message)
+	}
+
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithFormatStrategy(TemplateFormatter(tmpl), jsonFmt))
+	l.WithContext("user", "alice").Info("text mode")
+	l.EnableJSONFormat()
+	l.Warn("json mode")
+
+	want := "INFO|text mode|alice\n" + `{"lvl":"WARN","msg":"json mode"}` + "\n"
+	if buf.String() != want {
+		t.Errorf("Expected %q, got %q", want, buf.String())
+	}
+}
+
+func TestWithFormatStrategyDefault(t *testing.T) {
+	var buf bytes.Buffer
+	custom := func(level Level, e *LogEntry) string { return "custom " + e.Message }
+	l := New(WithOutput(&buf), WithTimestamp(false), WithFormatStrategy(nil, custom))
+
+	// A nil formatter keeps the built-in text format
+	l.Info("builtin")
+	if !strings.Contains(buf.String(), "[INFO") || !strings.Contains(buf.String(), "builtin") {
+		t.Errorf("Expected the built-in text format, got: %s", buf.String())
+	}
+}
+
+func TestWithDynamicFormat(t *testing.T) {
+	var buf bytes.Buffer
+	alert := func(level Level, e *LogEntry) string { return "!!! " + e.Message }
+	l := New(WithOutput(&buf), WithTimestamp(false), WithJSONFormat(true),
+		WithFormatStrategy(nil, func(level Level, e *LogEntry) string { return "strategy" }),
+		WithDynamicFormat(func(level Level) Formatter {
+			if level >= ErrorLevel {
+				return alert
+			}
+			return nil
+		}),
+	)
+
+	l.WithContext("child", true).Error("disk full")
+	l.Info("routine")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || lines[0] != "!!! disk full" {
+		t.Fatalf("Expected the chosen formatter for errors, got: %s", buf.String())
+	}
+	if !strings.HasPrefix(lines[1], "{") || !strings.Contains(lines[1], `"message":"routine"`) {
+		t.Errorf("Expected the built-in JSON format when nil is chosen, got: %s", lines[1])
+	}
+}
+
+func TestTemplateFormatterError(t *testing.T) {
+	tmpl := template.Must(template.New("line").Parse(`{{.Missing}}`))
+	line := TemplateFormatter(tmpl)(InfoLevel, &LogEntry{Message: "m"})
+	if !strings.HasPrefix(line, "ERROR executing log template:") {
+		t.Errorf("Expected the template error rendered, got %q", line)
+	}
+}
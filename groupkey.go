@@ -0,0 +1,55 @@
+package dy
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+)
+
+var (
+	uuidPattern   = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+	digitsPattern = regexp.MustCompile(`[0-9]+`)
+)
+
+// GroupKey returns a stable, human-readable key grouping err with similar
+// errors, e.g. "*dy.SimpleError/PAYMENT_DECLINED" or "*net.OpError/dial".
+// Errors implementing ErrorWithGroupKey choose their own key; otherwise the
+// key is the error type followed by its code, or by its message with numbers
+// and UUIDs stripped. The same key is logged as ErrorData.GroupKey.
+func GroupKey(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var code string
+	if ce, ok := err.(interface{ Code() string }); ok {
+		code = ce.Code()
+	}
+	return groupKey(err, code)
+}
+
+// groupKey computes the grouping key of err given its code
+func groupKey(err error, code string) string {
+	if gk, ok := err.(ErrorWithGroupKey); ok {
+		return gk.GroupKey()
+	}
+
+	typeName := fmt.Sprintf("%T", err)
+	if code != "" {
+		return typeName + "/" + code
+	}
+
+	if opErr, ok := err.(*net.OpError); ok {
+		return typeName + "/" + opErr.Op
+	}
+
+	return typeName + "/" + normalizeMessage(err.Error())
+}
+
+// normalizeMessage strips UUIDs and numbers from an error message
+func normalizeMessage(msg string) string {
+	msg = uuidPattern.ReplaceAllString(msg, "")
+	msg = digitsPattern.ReplaceAllString(msg, "")
+	return strings.Join(strings.Fields(msg), " ")
+}
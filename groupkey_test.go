@@ -0,0 +1,54 @@
+package dy
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+)
+
+// groupedError chooses its own grouping key
+type groupedError struct{}
+
+func (groupedError) Error() string    { return "grouped" }
+func (groupedError) GroupKey() string { return "custom-group" }
+
+func TestGroupKey(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"code", NewError("card declined", "PAYMENT_DECLINED", nil), "*dy.SimpleError/PAYMENT_DECLINED"},
+		{"net op", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("refused")}, "*net.OpError/dial"},
+		{"normalized message", errors.New("order 12345 for user 3f2b8c1e-9d4a-4b7e-8f6a-1c2d3e4f5a6b not found"), "*errors.errorString/order for user not found"},
+		{"override", groupedError{}, "custom-group"},
+		{"nil", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GroupKey(tt.err); got != tt.want {
+				t.Errorf("GroupKey() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGroupKeyInOutput(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false), WithJSONFormat(true))
+
+	l.WithError(NewError("card declined", "PAYMENT_DECLINED", nil)).Error("payment failed")
+	if !strings.Contains(buf.String(), `"group_key":"*dy.SimpleError/PAYMENT_DECLINED"`) {
+		t.Errorf("Expected group key in JSON output, got: %s", buf.String())
+	}
+
+	buf.Reset()
+	l = New(WithOutput(&buf), WithTimestamp(false))
+	l.WithError(NewError("card declined", "PAYMENT_DECLINED", nil)).Error("payment failed")
+	if !strings.Contains(buf.String(), "Group: *dy.SimpleError/PAYMENT_DECLINED") {
+		t.Errorf("Expected group key in text output, got: %s", buf.String())
+	}
+}
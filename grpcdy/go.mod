@@ -0,0 +1,18 @@
+module github.com/zakirkun/dy/grpcdy
+
+go 1.23.1
+
+require (
+	github.com/zakirkun/dy v0.0.0-00010101000000-000000000000
+	google.golang.org/grpc v1.72.2
+)
+
+require (
+	golang.org/x/net v0.35.0 // indirect
+	golang.org/x/sys v0.30.0 // indirect
+	golang.org/x/text v0.22.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250218202821-56aae31c358a // indirect
+	google.golang.org/protobuf v1.36.6 // indirect
+)
+
+replace github.com/zakirkun/dy => ../
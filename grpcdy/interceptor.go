@@ -0,0 +1,67 @@
+package grpcdy
+
+import (
+	"context"
+
+	"github.com/zakirkun/dy"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Metadata keys read by the interceptors
+const (
+	requestIDKey   = "x-request-id" // Inbound correlation id
+	traceparentKey = "traceparent"  // W3C trace context
+)
+
+// UnaryServerInterceptor returns an interceptor attaching a request-scoped
+// logger to the context of every unary call, retrieved with dy.FromContext.
+// The logger has the full method name as a "method" context field. A valid
+// W3C traceparent in the call's metadata is added with
+// dy.Logger.WithTraceInfo; without one the logger gets a correlation id as
+// trace id (see dy.Logger.WithTraceID): the x-request-id metadata of the
+// call, or a generated id when it is missing. Malformed traceparent values
+// are ignored.
+func UnaryServerInterceptor(l *dy.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(dy.NewContext(ctx, requestLogger(l, ctx, info.FullMethod)), req)
+	}
+}
+
+// StreamServerInterceptor is like UnaryServerInterceptor for streaming calls
+func StreamServerInterceptor(l *dy.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := dy.NewContext(ss.Context(), requestLogger(l, ss.Context(), info.FullMethod))
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// requestLogger creates the logger for a call to method
+func requestLogger(l *dy.Logger, ctx context.Context, method string) *dy.Logger {
+	l = l.WithContext("method", method)
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	if info, err := dy.ParseTraceparent(firstValue(md, traceparentKey)); err == nil {
+		return l.WithTraceInfo(info)
+	}
+	return l.WithTraceID(firstValue(md, requestIDKey))
+}
+
+// firstValue returns the first value of key in md, or "" if there is none
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// serverStream replaces the context of a grpc.ServerStream
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the context carrying the request-scoped logger
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
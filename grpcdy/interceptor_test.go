@@ -0,0 +1,99 @@
+package grpcdy
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/zakirkun/dy"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// fakeStream is a grpc.ServerStream with a fixed context
+type fakeStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	var buf bytes.Buffer
+	l := dy.New(dy.WithOutput(&buf), dy.WithTimestamp(false),
+		dy.WithTraceIDGenerator(func() string { return "generated" }))
+
+	interceptor := UnaryServerInterceptor(l)
+	info := &grpc.UnaryServerInfo{FullMethod: "/users.Users/Get"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		dy.FromContext(ctx).Info("handled")
+		return nil, nil
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "req-42"))
+	interceptor(ctx, nil, info, handler)
+	interceptor(context.Background(), nil, info, handler)
+
+	output := buf.String()
+	for _, want := range []string{
+		"[INFO] handled {method: /users.Users/Get, trace_id: req-42}",
+		"[INFO] handled {method: /users.Users/Get, trace_id: generated}",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output, got: %s", want, output)
+		}
+	}
+}
+
+func TestUnaryServerInterceptorTraceparent(t *testing.T) {
+	var buf bytes.Buffer
+	l := dy.New(dy.WithOutput(&buf), dy.WithTimestamp(false),
+		dy.WithTraceIDGenerator(func() string { return "generated" }))
+
+	interceptor := UnaryServerInterceptor(l)
+	info := &grpc.UnaryServerInfo{FullMethod: "/users.Users/Get"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		dy.FromContext(ctx).Info("handled")
+		return nil, nil
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		"traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"x-request-id", "req-42",
+	))
+	interceptor(ctx, nil, info, handler)
+
+	// A malformed traceparent is ignored
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs("traceparent", "ff-bad"))
+	interceptor(ctx, nil, info, handler)
+
+	output := buf.String()
+	for _, want := range []string{
+		"{method: /users.Users/Get, trace_id: 4bf92f3577b34da6a3ce929d0e0e4736, parent_span_id: 00f067aa0ba902b7}",
+		"{method: /users.Users/Get, trace_id: generated}",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output, got: %s", want, output)
+		}
+	}
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	var buf bytes.Buffer
+	l := dy.New(dy.WithOutput(&buf), dy.WithTimestamp(false))
+
+	interceptor := StreamServerInterceptor(l)
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "req-42"))
+	info := &grpc.StreamServerInfo{FullMethod: "/users.Users/Watch"}
+	interceptor(nil, &fakeStream{ctx: ctx}, info, func(srv interface{}, ss grpc.ServerStream) error {
+		dy.FromContext(ss.Context()).Info("streaming")
+		return nil
+	})
+
+	if !strings.Contains(buf.String(), "{method: /users.Users/Watch, trace_id: req-42}") {
+		t.Errorf("Expected the request-scoped logger in the stream context, got: %s", buf.String())
+	}
+}
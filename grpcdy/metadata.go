@@ -0,0 +1,45 @@
+// Package grpcdy adds gRPC request metadata to dy loggers. It is kept out of
+// the dy package so that only programs using gRPC depend on it.
+package grpcdy
+
+import (
+	"context"
+	"strings"
+
+	"github.com/zakirkun/dy"
+	"google.golang.org/grpc/metadata"
+)
+
+// WithMetadata creates a new logger with the values of the given gRPC
+// metadata keys as context fields. Keys are matched case-insensitively and
+// logged in lowercase; a key with several values is logged as a
+// comma-separated string. Missing keys are left out, and the logger is
+// returned unchanged when none is present.
+func WithMetadata(l *dy.Logger, md metadata.MD, keys ...string) *dy.Logger {
+	fields := make(map[string]interface{})
+	for _, key := range keys {
+		if values := md.Get(key); len(values) > 0 {
+			fields[strings.ToLower(key)] = strings.Join(values, ",")
+		}
+	}
+
+	if len(fields) == 0 {
+		return l
+	}
+	return l.WithFields(fields)
+}
+
+// WithIncomingContext is like WithMetadata but reads the metadata of the
+// incoming request carried by ctx, as in a server handler or interceptor.
+// The logger is returned unchanged when ctx has no metadata.
+func WithIncomingContext(l *dy.Logger, ctx context.Context, keys ...string) *dy.Logger {
+	if l == nil {
+		return nil
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return l
+	}
+	return WithMetadata(l, md, keys...)
+}
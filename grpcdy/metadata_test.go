@@ -0,0 +1,49 @@
+package grpcdy
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/zakirkun/dy"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestWithMetadata(t *testing.T) {
+	var buf bytes.Buffer
+	l := dy.New(dy.WithOutput(&buf), dy.WithTimestamp(false))
+
+	md := metadata.Pairs(
+		"x-request-id", "req-42",
+		"x-route", "eu",
+		"x-route", "blue",
+		"authorization", "Bearer secret",
+	)
+	WithMetadata(l, md, "X-Request-ID", "x-route", "x-missing").Info("call")
+
+	output := buf.String()
+	for _, want := range []string{"x-request-id: req-42", "x-route: eu,blue"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output, got: %s", want, output)
+		}
+	}
+	if strings.Contains(output, "secret") || strings.Contains(output, "x-missing") {
+		t.Errorf("Expected only the requested keys, got: %s", output)
+	}
+}
+
+func TestWithIncomingContext(t *testing.T) {
+	var buf bytes.Buffer
+	l := dy.New(dy.WithOutput(&buf), dy.WithTimestamp(false))
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "req-42"))
+	WithIncomingContext(l, ctx, "x-request-id").Info("call")
+	if !strings.Contains(buf.String(), "{x-request-id: req-42}") {
+		t.Errorf("Expected metadata from the incoming context, got: %s", buf.String())
+	}
+
+	if got := WithIncomingContext(l, context.Background(), "x-request-id"); got != l {
+		t.Error("Expected the logger unchanged without incoming metadata")
+	}
+}
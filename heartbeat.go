@@ -0,0 +1,107 @@
+package dy
+
+import (
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+)
+
+// processStart is used to report uptime in heartbeat entries
+var processStart = time.Now()
+
+// heartbeatFunc returns custom fields for heartbeat entries
+type heartbeatFunc func() map[string]interface{}
+
+// WithHeartbeatFields adds the fields returned by fn to every heartbeat entry.
+// fn is called on each tick and its fields override the runtime stats.
+func WithHeartbeatFields(fn func() map[string]interface{}) Option {
+	return func(l *Logger) {
+		l.heartbeat = fn
+	}
+}
+
+// WithHeartbeat logs a "heartbeat" entry at level every interval until stop is
+// called. Each entry carries goroutines, heap_alloc_mb, gc_count, uptime_sec
+// and type: heartbeat fields, which are added to the entry even when the
+// logger's context is frozen. stop waits for the heartbeat goroutine to exit
+// and is safe to call more than once. A non-positive interval logs nothing.
+func (l *Logger) WithHeartbeat(interval time.Duration, level Level) (stop func()) {
+	if l == nil || interval <= 0 {
+		return func() {}
+	}
+
+	l.mu.Lock()
+	extra := l.heartbeat
+	l.mu.Unlock()
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				l.logHeartbeat(level, extra)
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			wg.Wait()
+		})
+	}
+}
+
+// logHeartbeat logs a heartbeat entry with the runtime stats as entry fields
+func (l *Logger) logHeartbeat(level Level, extra heartbeatFunc) {
+	if level.Severity() < l.level.Severity() && l.ring == nil {
+		return
+	}
+	if !l.sampler.allow(level, "heartbeat") {
+		return
+	}
+
+	entry, fields := l.buildEntry(level, "heartbeat", 1)
+
+	stats := heartbeatFields(extra)
+	keys := make([]string, 0, len(stats))
+	for key := range stats {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fields = append(fields, ContextField{Key: key, Value: stats[key]})
+	}
+
+	l.emit(level, entry, fields)
+	l.pool.put(fields)
+}
+
+// heartbeatFields collects runtime stats and any custom fields
+func heartbeatFields(extra heartbeatFunc) map[string]interface{} {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	fields := map[string]interface{}{
+		"type":          "heartbeat",
+		"goroutines":    runtime.NumGoroutine(),
+		"heap_alloc_mb": float64(mem.HeapAlloc) / (1024 * 1024),
+		"gc_count":      mem.NumGC,
+		"uptime_sec":    int64(time.Since(processStart).Seconds()),
+	}
+	if extra != nil {
+		for k, v := range extra() {
+			fields[k] = v
+		}
+	}
+	return fields
+}
@@ -0,0 +1,80 @@
+package dy
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer is a bytes.Buffer safe for use from the heartbeat goroutine
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf strings.Builder
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestWithHeartbeat(t *testing.T) {
+	var buf syncBuffer
+	l := New(WithOutput(&buf), WithTimestamp(false), WithHeartbeatFields(func() map[string]interface{} {
+		return map[string]interface{}{"queue_depth": 7}
+	}))
+
+	stop := l.WithHeartbeat(5*time.Millisecond, WarnLevel)
+	time.Sleep(30 * time.Millisecond)
+	stop()
+	stop() // Safe to call twice
+
+	output := buf.String()
+	for _, want := range []string{"[WARN] heartbeat", "type: heartbeat", "goroutines:", "heap_alloc_mb:", "gc_count:", "uptime_sec:", "queue_depth: 7"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in heartbeat output, got: %s", want, output)
+		}
+	}
+
+	count := strings.Count(output, "heartbeat {")
+	time.Sleep(20 * time.Millisecond)
+	if got := strings.Count(buf.String(), "heartbeat {"); got != count {
+		t.Errorf("Expected no heartbeats after stop, got %d more", got-count)
+	}
+}
+
+func TestWithHeartbeatInvalidInterval(t *testing.T) {
+	var buf syncBuffer
+	l := New(WithOutput(&buf), WithTimestamp(false))
+
+	for _, interval := range []time.Duration{0, -time.Second} {
+		stop := l.WithHeartbeat(interval, InfoLevel)
+		stop()
+	}
+	if got := buf.String(); got != "" {
+		t.Errorf("Expected no heartbeats for a non-positive interval, got: %s", got)
+	}
+}
+
+func TestWithHeartbeatImmutableContext(t *testing.T) {
+	var buf syncBuffer
+	l := New(WithOutput(&buf), WithTimestamp(false)).WithContext("service", "api").WithContextImmutable()
+
+	stop := l.WithHeartbeat(5*time.Millisecond, InfoLevel)
+	time.Sleep(20 * time.Millisecond)
+	stop()
+
+	output := buf.String()
+	for _, want := range []string{"service: api", "type: heartbeat", "goroutines:"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in heartbeat output of a frozen logger, got: %s", want, output)
+		}
+	}
+}
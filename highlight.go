@@ -0,0 +1,140 @@
+package dy
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// highlightRule colors text entries whose context has field set to value
+type highlightRule struct {
+	field string
+	value interface{}
+	color string
+	line  bool // Color the whole line instead of the field
+}
+
+// highlighter holds the highlight rules of a logger and the loggers derived
+// from it. Rules are replaced as a whole, so writing an entry only needs an
+// atomic load.
+type highlighter struct {
+	mu    sync.Mutex // Serializes changes to rules
+	rules atomic.Pointer[[]highlightRule]
+}
+
+// WithHighlight renders the field of text entries whose context has field
+// set to value in color, e.g. Cyan or Inverse (the default when color is
+// empty), so that one request stands out while following it through the
+// console. It can be used several times and only applies when colors are
+// active.
+func WithHighlight(field string, value interface{}, color string) Option {
+	return func(l *Logger) {
+		l.highlights.set(highlightRule{field: field, value: value, color: color})
+	}
+}
+
+// WithHighlightLine is like WithHighlight but colors the whole line
+func WithHighlightLine(field string, value interface{}, color string) Option {
+	return func(l *Logger) {
+		l.highlights.set(highlightRule{field: field, value: value, color: color, line: true})
+	}
+}
+
+// Highlight starts rendering the field of text entries whose context has
+// field set to value in inverse video, see WithHighlight. It applies to the
+// logger, its parents and the loggers derived from them, and is undone with
+// Unhighlight.
+func (l *Logger) Highlight(field string, value interface{}) {
+	if l == nil {
+		return
+	}
+
+	l.highlights.set(highlightRule{field: field, value: value})
+}
+
+// Unhighlight stops highlighting entries whose context has field set to
+// value, however the highlight was added
+func (l *Logger) Unhighlight(field string, value interface{}) {
+	if l == nil {
+		return
+	}
+
+	l.highlights.update(func(rules []highlightRule) []highlightRule {
+		return slices.DeleteFunc(rules, func(rule highlightRule) bool {
+			return rule.field == field && valuesEqual(rule.value, value)
+		})
+	})
+}
+
+// set adds rule, replacing a rule for the same field and value
+func (h *highlighter) set(rule highlightRule) {
+	if rule.color == "" {
+		rule.color = Inverse
+	}
+	h.update(func(rules []highlightRule) []highlightRule {
+		rules = slices.DeleteFunc(rules, func(existing highlightRule) bool {
+			return existing.field == rule.field && valuesEqual(existing.value, rule.value)
+		})
+		return append(rules, rule)
+	})
+}
+
+// update replaces the rules with fn applied to a copy of them
+func (h *highlighter) update(fn func(rules []highlightRule) []highlightRule) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	rules := fn(slices.Clone(h.active()))
+	h.rules.Store(&rules)
+}
+
+// active returns the current rules
+func (h *highlighter) active() []highlightRule {
+	if h == nil {
+		return nil
+	}
+	if rules := h.rules.Load(); rules != nil {
+		return *rules
+	}
+	return nil
+}
+
+// highlightParts colors the context parts matched by rules, returning a copy
+// of parts if any changed, and the color of the whole line if a line rule
+// matched
+func highlightParts(rules []highlightRule, entry *LogEntry, parts []string) ([]string, string) {
+	var lineColor string
+	copied := false
+	for _, rule := range rules {
+		value, ok := entry.Context[rule.field]
+		if !ok || !valuesEqual(value, rule.value) {
+			continue
+		}
+		if rule.line {
+			if lineColor == "" {
+				lineColor = rule.color
+			}
+			continue
+		}
+
+		prefix := rule.field + ": "
+		for i, part := range parts {
+			if !strings.HasPrefix(part, prefix) {
+				continue
+			}
+			// The parts may be cached with the logger's context
+			if !copied {
+				parts = slices.Clone(parts)
+				copied = true
+			}
+			parts[i] = rule.color + part + Reset
+		}
+	}
+	return parts, lineColor
+}
+
+// highlightLine colors line, restoring the color after any reset in it
+func highlightLine(line, color string) string {
+	return color + strings.ReplaceAll(line, Reset, Reset+color) + Reset
+}
@@ -0,0 +1,102 @@
+package dy
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+// captureStderr runs fn with os.Stderr redirected to a pipe, so that loggers
+// writing to it get colors, and returns what was written
+func captureStderr(t *testing.T, fn func()) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := os.Stderr
+	os.Stderr = w
+	defer func() { os.Stderr = saved }()
+
+	done := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- string(data)
+	}()
+
+	fn()
+	w.Close()
+	return <-done
+}
+
+func TestWithHighlight(t *testing.T) {
+	out := captureStderr(t, func() {
+		l := New(WithOutput(os.Stderr), WithTimestamp(false), WithHighlight("request_id", "abc-123", Cyan))
+		l.WithContext("request_id", "abc-123").WithContext("user", "alice").Info("followed")
+		l.WithContext("request_id", "def-456").Info("other")
+	})
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %q", out)
+	}
+	if !strings.Contains(lines[0], Cyan+"request_id: abc-123"+Reset) {
+		t.Errorf("Expected the matching field to be highlighted, got %q", lines[0])
+	}
+	if strings.Contains(lines[0], Cyan+"user") {
+		t.Errorf("Expected only the matching field to be highlighted, got %q", lines[0])
+	}
+	if strings.Contains(lines[1], Cyan) {
+		t.Errorf("Expected other values not to be highlighted, got %q", lines[1])
+	}
+}
+
+func TestWithHighlightLine(t *testing.T) {
+	out := captureStderr(t, func() {
+		l := New(WithOutput(os.Stderr), WithTimestamp(false), WithHighlightLine("tenant", 42, ""))
+		l.WithContext("tenant", 42).Warn("quota exceeded")
+	})
+
+	if !strings.HasPrefix(out, Inverse) || !strings.HasSuffix(out, Reset+"\n") {
+		t.Errorf("Expected the whole line in inverse video, got %q", out)
+	}
+	// The level color's reset must not end the highlight
+	if !strings.Contains(out, Reset+Inverse) {
+		t.Errorf("Expected the highlight to resume after the level color, got %q", out)
+	}
+}
+
+func TestHighlightAtRuntime(t *testing.T) {
+	out := captureStderr(t, func() {
+		parent := New(WithOutput(os.Stderr), WithTimestamp(false))
+		child := parent.WithContext("order", "A-1")
+
+		child.Info("before")
+		parent.Highlight("order", "A-1")
+		child.Info("during")
+		child.Unhighlight("order", "A-1")
+		child.Info("after")
+	})
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 lines, got %q", out)
+	}
+	for i, want := range []bool{false, true, false} {
+		if got := strings.Contains(lines[i], Inverse+"order: A-1"); got != want {
+			t.Errorf("Line %d highlighted = %v, want %v: %q", i, got, want, lines[i])
+		}
+	}
+}
+
+func TestHighlightRequiresColors(t *testing.T) {
+	buf := &syncBuffer{}
+	l := New(WithOutput(buf), WithHighlight("request_id", "abc-123", Cyan))
+	l.WithContext("request_id", "abc-123").Info("plain")
+
+	if strings.Contains(buf.String(), Cyan) {
+		t.Errorf("Expected no highlight on non-terminal output, got %q", buf.String())
+	}
+}
@@ -0,0 +1,74 @@
+package dy
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// WithHTTPResponse creates a new logger with the metadata of resp as context
+// fields: "http.response.status", "http.response.status_code",
+// "http.response.content_length" when known, "http.response.content_type"
+// when set, and each of headerKeys present in the response as
+// "http.response.header.<key>" with the key lowercased. A nil resp returns l
+// unchanged.
+func (l *Logger) WithHTTPResponse(resp *http.Response, headerKeys ...string) *Logger {
+	if resp == nil {
+		return l
+	}
+	fields := make(map[string]interface{})
+	addResponseFields(fields, resp, headerKeys)
+	return l.WithFields(fields)
+}
+
+// WithHTTPRoundTrip creates a new logger for an access log entry of an
+// outgoing request, with "http.request.method" and "http.request.url" from
+// req, the fields of WithHTTPResponse from resp and the elapsed time as
+// "http.duration". Sensitive query parameters of the URL are redacted as by
+// WithContextFromURL. Either req or resp may be nil, for example when the
+// request failed.
+func (l *Logger) WithHTTPRoundTrip(req *http.Request, resp *http.Response, duration time.Duration) *Logger {
+	if l == nil {
+		return nil
+	}
+
+	fields := map[string]interface{}{
+		"http.duration": duration,
+	}
+	if req != nil {
+		fields["http.request.method"] = req.Method
+		if req.URL != nil {
+			l.mu.Lock()
+			sensitive := l.sensitive
+			l.mu.Unlock()
+			if sensitive == nil {
+				sensitive = defaultSensitiveParams
+			}
+
+			u := *req.URL
+			u.RawQuery = redactQuery(u.RawQuery, sensitive)
+			fields["http.request.url"] = u.String()
+		}
+	}
+	if resp != nil {
+		addResponseFields(fields, resp, nil)
+	}
+	return l.WithFields(fields)
+}
+
+// addResponseFields adds the metadata of resp and the given headers to fields
+func addResponseFields(fields map[string]interface{}, resp *http.Response, headerKeys []string) {
+	fields["http.response.status"] = resp.Status
+	fields["http.response.status_code"] = resp.StatusCode
+	if resp.ContentLength >= 0 {
+		fields["http.response.content_length"] = resp.ContentLength
+	}
+	if contentType := resp.Header.Get("Content-Type"); contentType != "" {
+		fields["http.response.content_type"] = contentType
+	}
+	for _, key := range headerKeys {
+		if values := resp.Header.Values(key); len(values) > 0 {
+			fields["http.response.header."+strings.ToLower(key)] = strings.Join(values, ", ")
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package dy
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithHTTPResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Add("X-Request-Id", "req-1")
+		w.Header().Add("Cache-Control", "no-cache")
+		w.Header().Add("Cache-Control", "no-store")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	l := New(WithOutput(&bytes.Buffer{})).WithHTTPResponse(resp, "X-Request-Id", "Cache-Control", "X-Missing")
+	want := map[string]interface{}{
+		"http.response.status":               "201 Created",
+		"http.response.status_code":          201,
+		"http.response.content_length":       int64(11),
+		"http.response.content_type":         "application/json",
+		"http.response.header.x-request-id":  "req-1",
+		"http.response.header.cache-control": "no-cache, no-store",
+	}
+	for key, value := range want {
+		if got, _ := l.ContextValue(key); got != value {
+			t.Errorf("Expected %s = %v (%T), got %v (%T)", key, value, value, got, got)
+		}
+	}
+	if _, ok := l.ContextValue("http.response.header.x-missing"); ok {
+		t.Error("Expected missing headers to be left out")
+	}
+}
+
+func TestWithHTTPRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false))
+
+	req := httptest.NewRequest(http.MethodGet, "https://api.example.com/users?id=7&token=abc", nil)
+	resp := &http.Response{Status: "200 OK", StatusCode: 200, ContentLength: -1, Header: http.Header{}}
+	l.WithHTTPRoundTrip(req, resp, 150*time.Millisecond).Info("request completed")
+
+	output := buf.String()
+	for _, want := range []string{
+		"http.request.method: GET",
+		"http.request.url: https://api.example.com/users?id=7&token=[REDACTED]",
+		"http.response.status_code: 200",
+		"http.duration: 150ms",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output, got: %s", want, output)
+		}
+	}
+	if strings.Contains(output, "content_length") {
+		t.Errorf("Expected an unknown content length to be left out, got: %s", output)
+	}
+
+	// A failed request has no response
+	buf.Reset()
+	l.WithHTTPRoundTrip(req, nil, time.Second).Error("request failed")
+	if strings.Contains(buf.String(), "http.response") || !strings.Contains(buf.String(), "http.duration: 1s") {
+		t.Errorf("Expected only request fields and duration, got: %s", buf.String())
+	}
+}
@@ -0,0 +1,136 @@
+package dy
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// humanizeConfig holds the settings used by Humanize
+type humanizeConfig struct {
+	minLevel Level
+	where    map[string]string
+	color    bool
+}
+
+// HumanizeOption configures Humanize
+type HumanizeOption func(*humanizeConfig)
+
+// WithHumanizeLevel drops entries below the given level
+func WithHumanizeLevel(level Level) HumanizeOption {
+	return func(c *humanizeConfig) {
+		c.minLevel = level
+	}
+}
+
+// WithHumanizeWhere keeps only entries whose context field key renders as value
+func WithHumanizeWhere(key, value string) HumanizeOption {
+	return func(c *humanizeConfig) {
+		if c.where == nil {
+			c.where = make(map[string]string)
+		}
+		c.where[key] = value
+	}
+}
+
+// WithHumanizeColor enables or disables colored levels. Colors are still only
+// written when the output is a terminal, as with a logger.
+func WithHumanizeColor(enable bool) HumanizeOption {
+	return func(c *humanizeConfig) {
+		c.color = enable
+	}
+}
+
+// Humanize reads NDJSON log entries from r and writes them to w in the text
+// format a logger would have produced for the same entries. Lines that are
+// not JSON entries are copied through unchanged, except that lines longer
+// than 1 MiB are truncated. Context fields are rendered
+// in key order since JSON output does not preserve insertion order.
+func Humanize(r io.Reader, w io.Writer, opts ...HumanizeOption) error {
+	cfg := humanizeConfig{
+		minLevel: DebugLevel,
+		color:    true,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	// The logger is only used for formatting, never for writing
+	formatter := New(WithOutput(w), WithColor(cfg.color))
+
+	d := NewEntryDecoder(r)
+	for {
+		entry, err := d.Next()
+		if err == io.EOF {
+			return nil
+		}
+
+		var malformed *MalformedLineError
+		if errors.As(err, &malformed) {
+			if _, err := io.WriteString(w, malformed.Raw+"\n"); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		level := ParseLevel(entry.Level)
+		if level.Severity() < cfg.minLevel.Severity() || !cfg.matches(entry) {
+			continue
+		}
+
+		line := formatter.humanizeEntry(level, entry)
+		if _, err := io.WriteString(w, line+"\n"); err != nil {
+			return err
+		}
+	}
+}
+
+// humanizeEntry renders a decoded entry in the text format
+func (l *Logger) humanizeEntry(level Level, entry *LogEntry) string {
+	// Epoch timestamps are rendered in the text format's layout
+	if entry.epochTimestamp {
+		entry.Timestamp = l.formatTime(entry.Time)
+	}
+
+	var indent string
+	if entry.NestLevel > 0 {
+		indent = strings.Repeat(l.indentString, entry.NestLevel)
+	}
+
+	return l.formatText(level, entry, sortedFields(entry.Context), indent)
+}
+
+// matches reports whether an entry satisfies every where filter
+func (c *humanizeConfig) matches(entry *LogEntry) bool {
+	for key, want := range c.where {
+		value, ok := entry.Context[key]
+		if !ok || fmt.Sprint(value) != want {
+			return false
+		}
+	}
+	return true
+}
+
+// sortedFields converts a context map into fields ordered by key
+func sortedFields(context map[string]interface{}) []ContextField {
+	if len(context) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(context))
+	for key := range context {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fields := make([]ContextField, 0, len(keys))
+	for _, key := range keys {
+		fields = append(fields, ContextField{Key: key, Value: context[key]})
+	}
+	return fields
+}
@@ -0,0 +1,61 @@
+package dy
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestHumanizeMatchesTextOutput(t *testing.T) {
+	var jsonBuf, textBuf bytes.Buffer
+	jsonLogger := New(WithOutput(&jsonBuf), WithJSONFormat(true), WithTimestamp(false))
+	textLogger := New(WithOutput(&textBuf), WithTimestamp(false))
+
+	for _, l := range []*Logger{jsonLogger, textLogger} {
+		l.WithContext("request_id", "abc").Info("served")
+		l.WithError(NewError("boom", "E_BOOM", nil)).Error("failed")
+	}
+
+	var out bytes.Buffer
+	if err := Humanize(&jsonBuf, &out); err != nil {
+		t.Fatalf("Humanize returned error: %v", err)
+	}
+	if out.String() != textBuf.String() {
+		t.Errorf("Humanize output = %q, want %q", out.String(), textBuf.String())
+	}
+}
+
+func TestHumanizeFilters(t *testing.T) {
+	input := strings.Join([]string{
+		`{"level":"INFO","message":"skipped by level"}`,
+		`plain text line`,
+		`{"level":"WARN","message":"kept","context":{"request_id":"abc"}}`,
+		`{"level":"ERROR","message":"skipped by where","context":{"request_id":"xyz"}}`,
+	}, "\n")
+
+	var out bytes.Buffer
+	err := Humanize(strings.NewReader(input), &out,
+		WithHumanizeLevel(WarnLevel),
+		WithHumanizeWhere("request_id", "abc"),
+	)
+	if err != nil {
+		t.Fatalf("Humanize returned error: %v", err)
+	}
+
+	expected := "plain text line\n[WARN] kept {request_id: abc}\n"
+	if out.String() != expected {
+		t.Errorf("Humanize output = %q, want %q", out.String(), expected)
+	}
+}
+
+func TestHumanizeLineTooLong(t *testing.T) {
+	input := strings.Repeat("x", maxDecodeLineSize+10) + "\n" + `{"level":"INFO","message":"after"}`
+
+	var out bytes.Buffer
+	if err := Humanize(strings.NewReader(input), &out, WithHumanizeColor(false)); err != nil {
+		t.Fatalf("Humanize returned error: %v", err)
+	}
+	if !strings.HasSuffix(out.String(), "\n[INFO] after\n") {
+		t.Errorf("Expected humanizing to continue after an overly long line, got %q", out.String()[maxDecodeLineSize-10:])
+	}
+}
@@ -0,0 +1,120 @@
+package dy
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// defaultJWTMapping maps JWT claims to the context keys they are logged under
+var defaultJWTMapping = map[string]string{
+	"sub": "user_id",
+	"iss": "issuer",
+	"exp": "token_exp",
+}
+
+// WithJWTFieldMapping sets the context key WithContextFromJWT logs the claim
+// jwtClaim under. The defaults log "sub" as "user_id", "iss" as "issuer" and
+// "exp" as "token_exp"; other claims use their own name.
+func WithJWTFieldMapping(jwtClaim, logField string) Option {
+	return func(l *Logger) {
+		mapping := make(map[string]string, len(defaultJWTMapping)+1)
+		for k, v := range defaultJWTMapping {
+			mapping[k] = v
+		}
+		for k, v := range l.jwtMapping {
+			mapping[k] = v
+		}
+		mapping[jwtClaim] = logField
+		l.jwtMapping = mapping
+	}
+}
+
+// WithContextFromJWT creates a new logger with claims from the payload of a
+// JWT as context fields. The signature is not verified, so only use this
+// for tokens the authentication layer has already accepted. Without claims
+// the mapped claims, "sub", "iss" and "exp" by default, are logged. Claims
+// missing from the token are left out. An error is returned, along with l,
+// when the token is not a well-formed JWT.
+func (l *Logger) WithContextFromJWT(token string, claims ...string) (*Logger, error) {
+	if l == nil {
+		return nil, nil
+	}
+
+	payload, err := decodeJWTPayload(token)
+	if err != nil {
+		return l, err
+	}
+
+	l.mu.Lock()
+	mapping := l.jwtMapping
+	l.mu.Unlock()
+	if mapping == nil {
+		mapping = defaultJWTMapping
+	}
+
+	if len(claims) == 0 {
+		for claim := range mapping {
+			claims = append(claims, claim)
+		}
+		sort.Strings(claims)
+	}
+
+	fields := make(map[string]interface{}, len(claims))
+	for _, claim := range claims {
+		value, ok := payload[claim]
+		if !ok {
+			continue
+		}
+		key := claim
+		if mapped, ok := mapping[claim]; ok {
+			key = mapped
+		}
+		fields[key] = jwtClaimValue(value)
+	}
+
+	if len(fields) == 0 {
+		return l, nil
+	}
+	return l.WithFields(fields), nil
+}
+
+// decodeJWTPayload decodes the claims of a compact serialized JWT
+func decodeJWTPayload(token string) (map[string]interface{}, error) {
+	parts := strings.Split(strings.TrimPrefix(strings.TrimSpace(token), "Bearer "), ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("invalid JWT: expected 3 segments, got %d", len(parts))
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT payload: %w", err)
+	}
+
+	var payload map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&payload); err != nil {
+		return nil, fmt.Errorf("invalid JWT payload: %w", err)
+	}
+	return payload, nil
+}
+
+// jwtClaimValue converts numeric claims, such as timestamps, to int64 when
+// they are whole numbers
+func jwtClaimValue(v interface{}) interface{} {
+	n, ok := v.(json.Number)
+	if !ok {
+		return v
+	}
+	if i, err := n.Int64(); err == nil {
+		return i
+	}
+	if f, err := n.Float64(); err == nil {
+		return f
+	}
+	return n.String()
+}
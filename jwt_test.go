@@ -0,0 +1,74 @@
+package dy
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+// testJWT builds an unsigned token with the given JSON payload
+func testJWT(payload string) string {
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString([]byte(`{"alg":"none"}`)) + "." + enc.EncodeToString([]byte(payload)) + ".sig"
+}
+
+func TestWithContextFromJWT(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false))
+
+	token := testJWT(`{"sub":"user-42","iss":"https://auth.example.com","exp":1767225600,"scope":"read"}`)
+	child, err := l.WithContextFromJWT(token)
+	if err != nil {
+		t.Fatalf("WithContextFromJWT failed: %v", err)
+	}
+	child.Info("request")
+
+	output := buf.String()
+	for _, want := range []string{"user_id: user-42", "issuer: https://auth.example.com", "token_exp: 1767225600"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output, got: %s", want, output)
+		}
+	}
+	if strings.Contains(output, "scope") {
+		t.Errorf("Expected only the mapped claims by default, got: %s", output)
+	}
+	if exp, _ := child.ContextValue("token_exp"); exp != int64(1767225600) {
+		t.Errorf("Expected exp as int64, got %T %v", exp, exp)
+	}
+}
+
+func TestWithContextFromJWTClaims(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false), WithJWTFieldMapping("tenant", "tenant_id"))
+
+	token := "Bearer " + testJWT(`{"sub":"user-42","tenant":"acme","scope":"read"}`)
+	child, err := l.WithContextFromJWT(token, "tenant", "scope", "sub", "missing")
+	if err != nil {
+		t.Fatalf("WithContextFromJWT failed: %v", err)
+	}
+	child.Info("request")
+
+	output := buf.String()
+	for _, want := range []string{"tenant_id: acme", "scope: read", "user_id: user-42"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output, got: %s", want, output)
+		}
+	}
+	if strings.Contains(output, "missing") {
+		t.Errorf("Expected missing claims to be left out, got: %s", output)
+	}
+}
+
+func TestWithContextFromJWTInvalid(t *testing.T) {
+	l := New(WithOutput(&bytes.Buffer{}))
+	for _, token := range []string{"", "abc", "a.!!!.c", "a." + base64.RawURLEncoding.EncodeToString([]byte("not json")) + ".c"} {
+		got, err := l.WithContextFromJWT(token)
+		if err == nil {
+			t.Errorf("Expected an error for %q", token)
+		}
+		if got != l {
+			t.Errorf("Expected the logger unchanged for %q", token)
+		}
+	}
+}
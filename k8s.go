@@ -0,0 +1,61 @@
+package dy
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// podInfoDir is where a Downward API volume is expected to be mounted
+var podInfoDir = "/etc/podinfo"
+
+// k8sFields maps the Downward API environment variables to context keys.
+// The fallback file in podInfoDir is named after the variable in lowercase.
+var k8sFields = []struct {
+	env string
+	key string
+}{
+	{"POD_NAME", "k8s.pod"},
+	{"POD_NAMESPACE", "k8s.namespace"},
+	{"NODE_NAME", "k8s.node"},
+	{"POD_IP", "k8s.pod_ip"},
+}
+
+// WithK8sContext creates a new logger with the pod name, namespace, node
+// name and pod IP exposed by the Kubernetes Downward API as "k8s.pod",
+// "k8s.namespace", "k8s.node" and "k8s.pod_ip" context fields. They are read
+// from the POD_NAME, POD_NAMESPACE, NODE_NAME and POD_IP environment
+// variables, falling back to the files pod_name, pod_namespace, node_name
+// and pod_ip of a Downward API volume mounted at /etc/podinfo. Values that
+// are not found are left out, and the logger is returned unchanged when none
+// is.
+func (l *Logger) WithK8sContext() *Logger {
+	if l == nil {
+		return nil
+	}
+
+	fields := make(map[string]interface{})
+	for _, f := range k8sFields {
+		if value := downwardValue(f.env); value != "" {
+			fields[f.key] = value
+		}
+	}
+
+	if len(fields) == 0 {
+		return l
+	}
+	return l.WithFields(fields)
+}
+
+// downwardValue returns the value of the environment variable env, or of
+// its file in podInfoDir
+func downwardValue(env string) string {
+	if value := strings.TrimSpace(os.Getenv(env)); value != "" {
+		return value
+	}
+	data, err := os.ReadFile(filepath.Join(podInfoDir, strings.ToLower(env)))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,54 @@
+package dy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// withPodInfoDir points the Downward API file fallback at dir for t
+func withPodInfoDir(t *testing.T, dir string) {
+	saved := podInfoDir
+	podInfoDir = dir
+	t.Cleanup(func() { podInfoDir = saved })
+}
+
+func TestWithK8sContext(t *testing.T) {
+	dir := t.TempDir()
+	withPodInfoDir(t, dir)
+	for _, env := range []string{"POD_NAME", "POD_NAMESPACE", "NODE_NAME", "POD_IP"} {
+		t.Setenv(env, "")
+	}
+
+	// Environment variables win over files
+	t.Setenv("POD_NAME", "web-7d9f")
+	t.Setenv("NODE_NAME", "node-1")
+	os.WriteFile(filepath.Join(dir, "pod_name"), []byte("from-file\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "pod_namespace"), []byte("shop\n"), 0644)
+
+	l := New(WithOutput(&syncBuffer{})).WithK8sContext()
+	for key, want := range map[string]string{
+		"k8s.pod":       "web-7d9f",
+		"k8s.namespace": "shop",
+		"k8s.node":      "node-1",
+	} {
+		if got, _ := l.ContextValue(key); got != want {
+			t.Errorf("Expected %s=%q, got %v", key, want, got)
+		}
+	}
+	if _, ok := l.ContextValue("k8s.pod_ip"); ok {
+		t.Error("Expected the missing pod IP to be left out")
+	}
+}
+
+func TestWithK8sContextOutsideKubernetes(t *testing.T) {
+	withPodInfoDir(t, filepath.Join(t.TempDir(), "missing"))
+	for _, env := range []string{"POD_NAME", "POD_NAMESPACE", "NODE_NAME", "POD_IP"} {
+		t.Setenv(env, "")
+	}
+
+	l := New(WithOutput(&syncBuffer{}))
+	if got := l.WithK8sContext(); got != l {
+		t.Error("Expected the logger unchanged outside Kubernetes")
+	}
+}
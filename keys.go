@@ -0,0 +1,120 @@
+package dy
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+)
+
+// defaultKeyPattern is the rule WithStrictKeys checks keys against
+var defaultKeyPattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// errEmptyKey is reported for empty context keys
+var errEmptyKey = errors.New("key is empty")
+
+// keyChecker validates context keys when strict keys are enabled. It is
+// shared with child loggers, so its results are cached once per key.
+type keyChecker struct {
+	enabled bool
+	panics  bool
+	pattern *regexp.Regexp
+	results sync.Map // Key to its error, or nil for valid keys
+	warned  sync.Map // Keys and call sites a warning has been logged for
+}
+
+// WithStrictKeys checks the keys added with WithContext, WithFields and With
+// against a rule, by default non-empty and matching [a-z0-9_]+, see
+// WithKeyPattern. The first time a key breaks the rule at a call site, a WARN
+// entry naming the key and the call site is logged; the field is still added.
+func WithStrictKeys(enable bool) Option {
+	return func(l *Logger) {
+		l.keyRules().enabled = enable
+	}
+}
+
+// WithStrictKeysPanic makes strict keys panic on a key breaking the rule
+// instead of logging a warning, for development and tests. Enabling it also
+// enables WithStrictKeys.
+func WithStrictKeysPanic(enable bool) Option {
+	return func(l *Logger) {
+		rules := l.keyRules()
+		rules.panics = enable
+		if enable {
+			rules.enabled = true
+		}
+	}
+}
+
+// WithKeyPattern replaces the rule of WithStrictKeys with a regular
+// expression keys must match. Empty keys are always rejected. An invalid
+// pattern keeps the current rule and is reported by NewE.
+func WithKeyPattern(pattern string) Option {
+	return func(l *Logger) {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			l.optionError(fmt.Errorf("invalid key pattern: %w", err))
+			return
+		}
+		l.keyRules().pattern = re
+	}
+}
+
+// keyRules returns the key checker of a logger being created, adding one
+func (l *Logger) keyRules() *keyChecker {
+	if l.keys == nil {
+		l.keys = &keyChecker{pattern: defaultKeyPattern}
+	}
+	return l.keys
+}
+
+// checkKey validates a key added by the caller of the method calling it
+func (l *Logger) checkKey(key string) {
+	keys := l.keys
+	if keys == nil || !keys.enabled {
+		return
+	}
+
+	err := keys.check(key)
+	if err == nil {
+		return
+	}
+
+	site := getCaller(3, nil) // skip getCaller, checkKey and the adding method
+	if keys.panics {
+		panic(fmt.Sprintf("invalid context key %q at %s:%d: %v", key, site.File, site.Line, err))
+	}
+
+	if _, warned := keys.warned.LoadOrStore(fmt.Sprintf("%s@%s:%d", key, site.File, site.Line), true); warned {
+		return
+	}
+	l.keyWarner().log(WarnLevel, "invalid context key %q at %s:%d (%s): %v", key, site.File, site.Line, site.Function, err)
+}
+
+// check returns why key breaks the rule, or nil for a valid key
+func (c *keyChecker) check(key string) error {
+	if result, ok := c.results.Load(key); ok {
+		err, _ := result.(error)
+		return err
+	}
+
+	var err error
+	if key == "" {
+		err = errEmptyKey
+	} else if !c.pattern.MatchString(key) {
+		err = fmt.Errorf("key does not match %s", c.pattern)
+	}
+	c.results.Store(key, err)
+	return err
+}
+
+// keyWarner returns a logger for invalid key warnings that does not check keys
+func (l *Logger) keyWarner() *Logger {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	warner := l.newChild()
+	warner.context = l.context.Clone()
+	warner.keys = nil
+	return warner
+}
@@ -0,0 +1,79 @@
+package dy
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStrictKeysWarnsOncePerCallSite(t *testing.T) {
+	buf := &syncBuffer{}
+	l := New(WithOutput(buf), WithTimestamp(false), WithColor(false), WithStrictKeys(true))
+
+	for i := 0; i < 3; i++ {
+		l.WithContext("userId", i) // Same call site every time
+	}
+	l.WithFields(map[string]interface{}{"user id": 1, "": 2, "user_id": 3})
+	l.With(ContextField{Key: "trace.id", Value: "abc"})
+
+	out := buf.String()
+	if n := strings.Count(out, `invalid context key "userId"`); n != 1 {
+		t.Errorf("Expected one warning for userId, got %d in %q", n, out)
+	}
+	for _, key := range []string{`"user id"`, `""`, `"trace.id"`} {
+		if !strings.Contains(out, "invalid context key "+key) {
+			t.Errorf("Expected a warning for %s, got %q", key, out)
+		}
+	}
+	if strings.Contains(out, `"user_id"`) {
+		t.Errorf("Expected no warning for a valid key, got %q", out)
+	}
+	if !strings.Contains(out, "keys_test.go:") {
+		t.Errorf("Expected the warning to name the call site, got %q", out)
+	}
+}
+
+func TestStrictKeysKeepsField(t *testing.T) {
+	l := New(WithOutput(&syncBuffer{}), WithStrictKeys(true)).WithContext("userId", 42)
+	if got, ok := l.ContextValue("userId"); !ok || got != 42 {
+		t.Errorf("Expected the field to be added despite the warning, got %v", got)
+	}
+}
+
+func TestStrictKeysDisabled(t *testing.T) {
+	buf := &syncBuffer{}
+	l := New(WithOutput(buf))
+	l.WithContext("Bad Key", 1).Info("entry")
+
+	if strings.Contains(buf.String(), "invalid context key") {
+		t.Errorf("Expected no key validation by default, got %q", buf.String())
+	}
+}
+
+func TestStrictKeysPanic(t *testing.T) {
+	l := New(WithOutput(&syncBuffer{}), WithStrictKeysPanic(true))
+
+	defer func() {
+		r := recover()
+		if r == nil || !strings.Contains(r.(string), `invalid context key "userId"`) {
+			t.Errorf("Expected a panic naming the key, got %v", r)
+		}
+	}()
+	l.WithContext("userId", 1)
+}
+
+func TestWithKeyPattern(t *testing.T) {
+	buf := &syncBuffer{}
+	l := New(WithOutput(buf), WithStrictKeys(true), WithKeyPattern(`^[a-z][a-zA-Z]*$`))
+
+	l.WithContext("userId", 1)
+	l.WithContext("user_id", 2)
+
+	out := buf.String()
+	if strings.Contains(out, `"userId"`) || !strings.Contains(out, `invalid context key "user_id"`) {
+		t.Errorf("Expected only user_id to break the custom rule, got %q", out)
+	}
+
+	if _, err := NewE(WithKeyPattern("[")); err == nil || !strings.Contains(err.Error(), "invalid key pattern") {
+		t.Errorf("Expected NewE to report the invalid pattern, got %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package dy
+
+import (
+	"maps"
+	"sort"
+	"strings"
+)
+
+// WithLabels creates a new logger with string labels, the indexed key-value
+// pairs of platforms such as Loki or Prometheus, kept apart from the context
+// fields. JSON entries carry them under a top-level "labels" key and text
+// entries as "[key=value ...]" after the level. Labels are added to those of
+// the logger, replacing labels with the same key.
+func (l *Logger) WithLabels(labels map[string]string) *Logger {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	child := l.newChild()
+	child.context = l.context.Clone()
+	if len(labels) == 0 {
+		return child
+	}
+
+	// Entries share the map, so it is never modified once set
+	merged := make(map[string]string, len(l.labels)+len(labels))
+	maps.Copy(merged, l.labels)
+	maps.Copy(merged, labels)
+	child.labels = merged
+	return child
+}
+
+// Labels returns a copy of the logger's labels
+func (l *Logger) Labels() map[string]string {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	labels := make(map[string]string, len(l.labels))
+	maps.Copy(labels, l.labels)
+	return labels
+}
+
+// formatLabels renders labels as "[key=value ...]" sorted by key
+func formatLabels(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = key + "=" + labels[key]
+	}
+	return "[" + strings.Join(parts, " ") + "]"
+}
@@ -0,0 +1,74 @@
+package dy
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestWithLabelsJSON(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithJSONFormat(true)).
+		WithLabels(map[string]string{"app": "shop", "env": "prod"}).
+		WithContext("user", "alice")
+
+	l.Info("order placed")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to parse JSON output %q: %v", buf.String(), err)
+	}
+	labels, ok := entry["labels"].(map[string]interface{})
+	if !ok || labels["app"] != "shop" || labels["env"] != "prod" {
+		t.Errorf("Expected top-level labels app=shop env=prod, got %v", entry["labels"])
+	}
+	context, _ := entry["context"].(map[string]interface{})
+	if _, ok := context["app"]; ok {
+		t.Errorf("Expected labels to stay out of the context, got %v", context)
+	}
+}
+
+func TestWithLabelsText(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false), WithColor(false)).
+		WithLabels(map[string]string{"env": "prod", "app": "shop"})
+
+	l.Info("order placed")
+
+	if got, want := buf.String(), "[INFO] [app=shop env=prod] order placed\n"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestWithLabelsMerge(t *testing.T) {
+	parent := New(WithOutput(&bytes.Buffer{})).WithLabels(map[string]string{"app": "shop", "env": "dev"})
+	child := parent.WithLabels(map[string]string{"env": "prod"})
+
+	if got := parent.Labels(); got["env"] != "dev" || len(got) != 2 {
+		t.Errorf("Expected the parent labels to be unchanged, got %v", got)
+	}
+	if got := child.Labels(); got["app"] != "shop" || got["env"] != "prod" {
+		t.Errorf("Expected merged labels app=shop env=prod, got %v", got)
+	}
+
+	// Labels returns a copy
+	child.Labels()["env"] = "staging"
+	if got := child.Labels()["env"]; got != "prod" {
+		t.Errorf("Expected modifying the copy to leave the labels alone, got env=%s", got)
+	}
+}
+
+func TestWithoutLabels(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithJSONFormat(true))
+
+	l.Info("plain")
+
+	if strings.Contains(buf.String(), "labels") {
+		t.Errorf("Expected no labels key, got %q", buf.String())
+	}
+	if got := l.Labels(); got == nil || len(got) != 0 {
+		t.Errorf("Expected an empty label map, got %v", got)
+	}
+}
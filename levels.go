@@ -0,0 +1,101 @@
+package dy
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// levelInfo describes a level registered with RegisterLevel
+type levelInfo struct {
+	name  string
+	color string
+}
+
+// levelRegistry holds custom levels
+var levelRegistry = struct {
+	sync.RWMutex
+	byValue map[Level]levelInfo
+	byName  map[string]Level
+}{
+	byValue: make(map[Level]levelInfo),
+	byName:  make(map[string]Level),
+}
+
+// builtinLevels are the levels that cannot be redefined
+var builtinLevels = []Level{DebugLevel, InfoLevel, WarnLevel, ErrorLevel, DPanicLevel, FatalLevel}
+
+// Severity returns the rank levels are filtered and compared by. The
+// built-in levels keep their values and rank DebugLevel 0, InfoLevel 100,
+// WarnLevel 200, ErrorLevel 300, DPanicLevel 350 and FatalLevel 400; any
+// other level ranks by its value.
+func (l Level) Severity() int {
+	switch l {
+	case DPanicLevel:
+		return ErrorLevel.Severity() + 50
+	case DebugLevel, InfoLevel, WarnLevel, ErrorLevel, FatalLevel:
+		return int(l) * 100
+	default:
+		return int(l)
+	}
+}
+
+// RegisterLevel adds a custom level with the given name and ANSI color
+// (e.g. dy.Magenta). A custom level's value is also its severity, see
+// Level.Severity, so a level with value 150 sits between InfoLevel and
+// WarnLevel:
+//
+//	const AuditLevel dy.Level = 150
+//	dy.RegisterLevel(AuditLevel, "AUDIT", dy.Cyan)
+//	logger.Log(AuditLevel, "user %s signed in", user)
+//
+// An error is returned if the value or name is already taken, or if the
+// value equals the severity of a built-in level.
+func RegisterLevel(value Level, name string, color string) error {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	if name == "" {
+		return fmt.Errorf("level name must not be empty")
+	}
+
+	for _, builtin := range builtinLevels {
+		if value == builtin {
+			return fmt.Errorf("level value %d is used by built-in level %s", value, builtin)
+		}
+		if int(value) == builtin.Severity() {
+			return fmt.Errorf("level value %d is the severity of built-in level %s", value, builtin)
+		}
+		if name == builtin.String() {
+			return fmt.Errorf("level name %q is used by a built-in level", name)
+		}
+	}
+
+	levelRegistry.Lock()
+	defer levelRegistry.Unlock()
+
+	if existing, ok := levelRegistry.byValue[value]; ok {
+		return fmt.Errorf("level value %d is already registered as %s", value, existing.name)
+	}
+	if _, ok := levelRegistry.byName[name]; ok {
+		return fmt.Errorf("level name %q is already registered", name)
+	}
+
+	levelRegistry.byValue[value] = levelInfo{name: name, color: color}
+	levelRegistry.byName[name] = value
+	return nil
+}
+
+// lookupLevel returns the registration of a custom level
+func lookupLevel(level Level) (levelInfo, bool) {
+	levelRegistry.RLock()
+	defer levelRegistry.RUnlock()
+	info, ok := levelRegistry.byValue[level]
+	return info, ok
+}
+
+// lookupLevelName returns the custom level registered under name
+func lookupLevelName(name string) (Level, bool) {
+	levelRegistry.RLock()
+	defer levelRegistry.RUnlock()
+	level, ok := levelRegistry.byName[strings.ToUpper(name)]
+	return level, ok
+}
@@ -0,0 +1,117 @@
+package dy
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRegisterLevel(t *testing.T) {
+	const auditLevel Level = 150 // Between InfoLevel and WarnLevel
+	if err := RegisterLevel(auditLevel, "audit", Cyan); err != nil {
+		t.Fatalf("RegisterLevel returned error: %v", err)
+	}
+
+	if got := auditLevel.String(); got != "AUDIT" {
+		t.Errorf("Expected registered name, got %q", got)
+	}
+	if got := ParseLevel("audit"); got != auditLevel {
+		t.Errorf("ParseLevel(\"audit\") = %d, want %d", got, auditLevel)
+	}
+	if got := getLevelColor(auditLevel); got != Cyan {
+		t.Errorf("Expected registered color, got %q", got)
+	}
+
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false), WithLevel(auditLevel))
+	l.Info("filtered")
+	l.Log(auditLevel, "user %s signed in", "alice")
+	l.Warn("kept")
+
+	output := buf.String()
+	if strings.Contains(output, "filtered") {
+		t.Errorf("Expected InfoLevel to be filtered below AUDIT, got: %s", output)
+	}
+	if !strings.Contains(output, "[AUDIT] user alice signed in") || !strings.Contains(output, "[WARN] kept") {
+		t.Errorf("Expected AUDIT and WARN entries, got: %s", output)
+	}
+
+	// A logger at WarnLevel filters AUDIT
+	buf.Reset()
+	New(WithOutput(&buf), WithLevel(WarnLevel)).Log(auditLevel, "filtered")
+	if buf.Len() > 0 {
+		t.Errorf("Expected AUDIT to be filtered below WARN, got: %s", buf.String())
+	}
+
+	buf.Reset()
+	l.EnableJSONFormat()
+	l.Log(auditLevel, "json entry")
+
+	var entry LogEntry
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v", err)
+	}
+	if entry.Level != "AUDIT" {
+		t.Errorf("Expected JSON level AUDIT, got %q", entry.Level)
+	}
+}
+
+func TestRegisterLevelCollisions(t *testing.T) {
+	tests := []struct {
+		value Level
+		name  string
+	}{
+		{InfoLevel, "NOTICE"},
+		{WarnLevel + 1, "warn"},
+		{DPanicLevel, "ASSERT"},
+		{Level(200), "ASSERT"}, // Severity of WarnLevel
+		{Level(350), "ASSERT"}, // Severity of DPanicLevel
+		{WarnLevel + 2, ""},
+	}
+
+	for _, test := range tests {
+		if err := RegisterLevel(test.value, test.name, ""); err == nil {
+			t.Errorf("RegisterLevel(%d, %q) expected error", test.value, test.name)
+		}
+	}
+
+	if err := RegisterLevel(DPanicLevel+20, "NOTICE", ""); err != nil {
+		t.Fatalf("RegisterLevel returned error: %v", err)
+	}
+	if err := RegisterLevel(DPanicLevel+20, "OTHER", ""); err == nil {
+		t.Errorf("Expected error registering a taken value")
+	}
+	if err := RegisterLevel(DPanicLevel+21, "notice", ""); err == nil {
+		t.Errorf("Expected error registering a taken name")
+	}
+}
+
+func TestBuiltinLevelValues(t *testing.T) {
+	tests := []struct {
+		level Level
+		value int
+	}{
+		{DebugLevel, 0},
+		{InfoLevel, 1},
+		{WarnLevel, 2},
+		{ErrorLevel, 3},
+		{FatalLevel, 4},
+		{DPanicLevel, 5},
+	}
+
+	for _, test := range tests {
+		if int(test.level) != test.value {
+			t.Errorf("%s = %d, want %d", test.level, test.level, test.value)
+		}
+	}
+}
+
+func TestLevelSeverity(t *testing.T) {
+	ordered := []Level{DebugLevel, Level(50), InfoLevel, Level(150), WarnLevel, ErrorLevel, DPanicLevel, FatalLevel}
+	for i := 1; i < len(ordered); i++ {
+		if ordered[i-1].Severity() >= ordered[i].Severity() {
+			t.Errorf("Expected %d to rank below %d", ordered[i-1], ordered[i])
+		}
+	}
+}
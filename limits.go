@@ -0,0 +1,226 @@
+package dy
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Default limits applied to context values, high enough not to affect
+// ordinary values
+const (
+	defaultMaxValueDepth    = 32
+	defaultMaxCollectionLen = 1000
+)
+
+// Markers written in place of the parts of a value cut by the limits
+const (
+	maxDepthExceededValue    = "<max depth exceeded>"
+	truncatedCollectionKey   = "<truncated>"
+	truncatedCollectionCount = "<%d more>"
+)
+
+// valueLimits bounds the size of context values written to an entry
+type valueLimits struct {
+	maxDepth int // Nesting depth of maps, slices and structs, 0 for no limit
+	maxLen   int // Elements of a single map, slice or array, 0 for no limit
+}
+
+// WithMaxValueDepth sets how deeply nested maps, slices, arrays and structs
+// in context values and error attributes are written. Deeper values are
+// replaced by "<max depth exceeded>". A depth of 0 or less removes the limit.
+// The default is 32.
+func WithMaxValueDepth(d int) Option {
+	return func(l *Logger) {
+		l.limits.maxDepth = max(d, 0)
+	}
+}
+
+// WithMaxCollectionLen sets how many elements of a map, slice or array in a
+// context value or error attribute are written. Longer slices end with a
+// "<N more>" element, and longer maps keep their first n keys in sorted order
+// plus a "<truncated>" key holding the same marker. A length of 0 or less
+// removes the limit. The default is 1000.
+func WithMaxCollectionLen(n int) Option {
+	return func(l *Logger) {
+		l.limits.maxLen = max(n, 0)
+	}
+}
+
+// enabled reports whether any limit is set
+func (c valueLimits) enabled() bool {
+	return c.maxDepth > 0 || c.maxLen > 0
+}
+
+// limitFields returns fields with oversized values cut down. fields is
+// returned unchanged when every value is within the limits.
+func (c valueLimits) limitFields(fields []ContextField) []ContextField {
+	var result []ContextField
+	for i, field := range fields {
+		value, changed := c.limitValue(field.Value)
+		if !changed {
+			continue
+		}
+		if result == nil {
+			result = make([]ContextField, len(fields))
+			copy(result, fields)
+		}
+		result[i].Value = value
+	}
+
+	if result == nil {
+		return fields
+	}
+	return result
+}
+
+// limitValue cuts down a single value, reporting whether it changed
+func (c valueLimits) limitValue(v interface{}) (interface{}, bool) {
+	if data, ok := v.(ErrorData); ok {
+		return c.limitErrorData(data)
+	}
+
+	rv := reflect.ValueOf(v)
+	if !c.exceeds(rv, 1) {
+		return v, false
+	}
+	return c.limit(rv, 1), true
+}
+
+// limitErrorData cuts down the attributes of data and its causes
+func (c valueLimits) limitErrorData(data ErrorData) (ErrorData, bool) {
+	changed := false
+	if len(data.Attributes) > 0 {
+		attrs := make(map[string]interface{}, len(data.Attributes))
+		for k, v := range data.Attributes {
+			value, ok := c.limitValue(v)
+			attrs[k] = value
+			changed = changed || ok
+		}
+		if changed {
+			data.Attributes = attrs
+		}
+	}
+	if data.Cause != nil {
+		if cause, ok := c.limitErrorData(*data.Cause); ok {
+			data.Cause = &cause
+			changed = true
+		}
+	}
+	return data, changed
+}
+
+// exceeds reports whether v, found at depth, breaks a limit. It does not
+// allocate, so values within the limits cost a single walk.
+func (c valueLimits) exceeds(v reflect.Value, depth int) bool {
+	if !v.IsValid() || leafValue(v) {
+		return false
+	}
+
+	switch v.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		return !v.IsNil() && c.exceeds(v.Elem(), depth)
+	}
+	if c.maxDepth > 0 && depth > c.maxDepth {
+		return true
+	}
+
+	switch v.Kind() {
+	case reflect.Map:
+		if c.maxLen > 0 && v.Len() > c.maxLen {
+			return true
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			if c.exceeds(iter.Value(), depth+1) {
+				return true
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if c.maxLen > 0 && v.Len() > c.maxLen {
+			return true
+		}
+		for i := 0; i < v.Len(); i++ {
+			if c.exceeds(v.Index(i), depth+1) {
+				return true
+			}
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if _, ok := jsonFieldName(t.Field(i)); ok && c.exceeds(v.Field(i), depth+1) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// limit returns a copy of v within the limits, keeping parts that already are
+// as they are. Maps are rendered with string keys and structs as maps keyed by
+// their fields' JSON names.
+func (c valueLimits) limit(v reflect.Value, depth int) interface{} {
+	if !c.exceeds(v, depth) {
+		if !v.IsValid() {
+			return nil
+		}
+		return v.Interface()
+	}
+
+	switch v.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		return c.limit(v.Elem(), depth)
+	}
+	if c.maxDepth > 0 && depth > c.maxDepth {
+		return maxDepthExceededValue
+	}
+
+	switch v.Kind() {
+	case reflect.Map:
+		keys := make([]string, 0, v.Len())
+		values := make(map[string]reflect.Value, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key := fmt.Sprint(iter.Key().Interface())
+			keys = append(keys, key)
+			values[key] = iter.Value()
+		}
+		sort.Strings(keys)
+
+		n := len(keys)
+		if c.maxLen > 0 && n > c.maxLen {
+			n = c.maxLen
+		}
+		result := make(map[string]interface{}, n+1)
+		for _, key := range keys[:n] {
+			result[key] = c.limit(values[key], depth+1)
+		}
+		if omitted := len(keys) - n; omitted > 0 {
+			result[truncatedCollectionKey] = fmt.Sprintf(truncatedCollectionCount, omitted)
+		}
+		return result
+	case reflect.Slice, reflect.Array:
+		n := v.Len()
+		if c.maxLen > 0 && n > c.maxLen {
+			n = c.maxLen
+		}
+		result := make([]interface{}, n, n+1)
+		for i := range result {
+			result[i] = c.limit(v.Index(i), depth+1)
+		}
+		if omitted := v.Len() - n; omitted > 0 {
+			result = append(result, fmt.Sprintf(truncatedCollectionCount, omitted))
+		}
+		return result
+	case reflect.Struct:
+		t := v.Type()
+		result := make(map[string]interface{}, t.NumField())
+		for i := 0; i < t.NumField(); i++ {
+			if name, ok := jsonFieldName(t.Field(i)); ok {
+				result[name] = c.limit(v.Field(i), depth+1)
+			}
+		}
+		return result
+	}
+	return v.Interface()
+}
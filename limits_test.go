@@ -0,0 +1,100 @@
+package dy
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestWithMaxValueDepth(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithJSONFormat(true), WithTimestamp(false), WithMaxValueDepth(2))
+
+	l.WithContext("config", map[string]interface{}{
+		"name": "svc",
+		"db":   map[string]interface{}{"pool": map[string]interface{}{"size": 10}},
+	}).Info("loaded")
+
+	var entry LogEntry
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to parse output %q: %v", buf.String(), err)
+	}
+	config := entry.Context["config"].(map[string]interface{})
+	db := config["db"].(map[string]interface{})
+	if config["name"] != "svc" || db["pool"] != maxDepthExceededValue {
+		t.Errorf("Unexpected limited value: %v", config)
+	}
+}
+
+func TestWithMaxCollectionLen(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithJSONFormat(true), WithTimestamp(false), WithMaxCollectionLen(3))
+
+	l.WithFields(map[string]interface{}{
+		"ids":  []int{1, 2, 3, 4, 5},
+		"tags": map[string]int{"a": 1, "b": 2, "c": 3, "d": 4},
+		"few":  []string{"x", "y"},
+	}).Info("batch")
+
+	output := buf.String()
+	for _, want := range []string{
+		`"ids":[1,2,3,"\u003c2 more\u003e"]`,
+		`"tags":{"\u003ctruncated\u003e":"\u003c1 more\u003e","a":1,"b":2,"c":3}`,
+		`"few":["x","y"]`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %s in output, got: %s", want, output)
+		}
+	}
+}
+
+func TestValueLimitsText(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false), WithMaxCollectionLen(2))
+
+	l.WithContext("ids", []int{1, 2, 3}).Info("batch")
+
+	if got := buf.String(); got != "[INFO] batch {ids: [1 2 <1 more>]}\n" {
+		t.Errorf("Unexpected output: %q", got)
+	}
+}
+
+func TestValueLimitsErrorAttributes(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithJSONFormat(true), WithTimestamp(false), WithMaxCollectionLen(1))
+
+	err := NewError("batch failed", "BATCH", map[string]interface{}{"ids": []int{1, 2, 3}})
+	l.WithError(err).Error("batch")
+
+	if !strings.Contains(buf.String(), `"ids":[1,"\u003c2 more\u003e"]`) {
+		t.Errorf("Expected truncated error attribute, got: %s", buf.String())
+	}
+}
+
+func TestValueLimitsStruct(t *testing.T) {
+	type node struct {
+		Name string `json:"name"`
+		Next *node  `json:"next,omitempty"`
+	}
+	n := &node{Name: "a"}
+	n.Next = n
+
+	limits := valueLimits{maxDepth: 3}
+	value, changed := limits.limitValue(n)
+	if !changed {
+		t.Fatal("Expected a cyclic value to be cut at the maximum depth")
+	}
+	data, _ := json.Marshal(value)
+	if got := string(data); got != `{"name":"a","next":{"name":"a","next":{"name":"a","next":"\u003cmax depth exceeded\u003e"}}}` {
+		t.Errorf("Unexpected limited struct: %s", got)
+	}
+}
+
+func TestValueLimitsDefaults(t *testing.T) {
+	fields := []ContextField{{Key: "ids", Value: make([]int, 100)}}
+	limits := New().limits
+	if got := limits.limitFields(fields); &got[0] != &fields[0] {
+		t.Error("Expected ordinary values to be left alone by the default limits")
+	}
+}
@@ -0,0 +1,90 @@
+package dy
+
+import (
+	"bytes"
+	"sync"
+)
+
+// maxLineLength is the longest line a LineWriter buffers before emitting it
+// as is, so a process that never writes a newline cannot grow the buffer forever
+const maxLineLength = 64 * 1024
+
+// LineWriter is an io.WriteCloser that logs each line written to it as a
+// separate entry. It is typically used as the Stdout or Stderr of an exec.Cmd.
+type LineWriter struct {
+	mu     sync.Mutex
+	logger *Logger
+	level  Level
+	buf    bytes.Buffer
+}
+
+// NewLineWriter creates a writer that logs every complete line at level with
+// the given fields, e.g. {"cmd": "terraform", "stream": "stderr"}. Partial
+// lines are buffered across writes and flushed by Close; lines longer than
+// 64 KiB are split. A LineWriter is safe for concurrent use.
+//
+// Example usage:
+//
+//	stderr := dy.NewLineWriter(logger, dy.WarnLevel, map[string]interface{}{"cmd": "terraform", "stream": "stderr"})
+//	defer stderr.Close()
+//	cmd.Stderr = stderr
+func NewLineWriter(l *Logger, level Level, fields map[string]interface{}) *LineWriter {
+	if len(fields) > 0 {
+		l = l.WithFields(fields)
+	}
+	return &LineWriter{
+		logger: l,
+		level:  level,
+	}
+}
+
+// Write buffers p and logs every complete line
+func (w *LineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf.Write(p)
+	for {
+		data := w.buf.Bytes()
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			break
+		}
+		w.emitChunks(data[:i])
+		w.buf.Next(i + 1)
+	}
+
+	// Emit overly long lines in chunks
+	for w.buf.Len() >= maxLineLength {
+		w.emit(w.buf.Next(maxLineLength))
+	}
+
+	return len(p), nil
+}
+
+// Close logs any trailing partial line
+func (w *LineWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.buf.Len() > 0 {
+		w.emit(w.buf.Bytes())
+		w.buf.Reset()
+	}
+	return nil
+}
+
+// emitChunks logs a complete line, split into chunks of at most maxLineLength
+func (w *LineWriter) emitChunks(line []byte) {
+	for len(line) > maxLineLength {
+		w.emit(line[:maxLineLength])
+		line = line[maxLineLength:]
+	}
+	w.emit(line)
+}
+
+// emit logs a single line, dropping a trailing carriage return
+func (w *LineWriter) emit(line []byte) {
+	line = bytes.TrimSuffix(line, []byte("\r"))
+	w.logger.Log(w.level, "%s", line)
+}
@@ -0,0 +1,103 @@
+package dy
+
+import (
+	"bytes"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestLineWriter(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false))
+	w := NewLineWriter(l, InfoLevel, map[string]interface{}{"stream": "stdout"})
+
+	w.Write([]byte("first li"))
+	w.Write([]byte("ne\r\nsecond line\nthird"))
+
+	expected := "[INFO] first line {stream: stdout}\n[INFO] second line {stream: stdout}\n"
+	if got := buf.String(); got != expected {
+		t.Errorf("LineWriter output = %q, want %q", got, expected)
+	}
+
+	w.Close()
+	if !strings.HasSuffix(buf.String(), "[INFO] third {stream: stdout}\n") {
+		t.Errorf("Expected trailing partial line on Close, got: %q", buf.String())
+	}
+}
+
+func TestLineWriterMaxLineLength(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false))
+	w := NewLineWriter(l, InfoLevel, nil)
+
+	w.Write(bytes.Repeat([]byte("x"), maxLineLength+10))
+	if got := strings.Count(buf.String(), "\n"); got != 1 {
+		t.Errorf("Expected one chunk emitted for an overly long line, got %d", got)
+	}
+
+	w.Close()
+	if got := strings.Count(buf.String(), "\n"); got != 2 {
+		t.Errorf("Expected remainder emitted on Close, got %d entries", got)
+	}
+
+	// A terminated line is split the same way within a single write
+	buf.Reset()
+	line := append(bytes.Repeat([]byte("y"), 2*maxLineLength+10), '\n')
+	w.Write(line)
+	if got := strings.Count(buf.String(), "\n"); got != 3 {
+		t.Errorf("Expected a terminated overly long line emitted in 3 chunks, got %d", got)
+	}
+	if got := strings.Count(buf.String(), "y"); got != 2*maxLineLength+10 {
+		t.Errorf("Expected every byte of the line to be logged, got %d", got)
+	}
+}
+
+func TestLineWriterConcurrent(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false))
+	w := NewLineWriter(l, InfoLevel, nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				io.WriteString(w, "line\n")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := strings.Count(buf.String(), "[INFO] line\n"); got != 200 {
+		t.Errorf("Expected 200 complete lines, got %d", got)
+	}
+}
+
+func TestLineWriterWithCommand(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false))
+	stdout := NewLineWriter(l, InfoLevel, map[string]interface{}{"stream": "stdout"})
+	stderr := NewLineWriter(l, WarnLevel, map[string]interface{}{"stream": "stderr"})
+
+	cmd := exec.Command("sh", "-c", "echo out; echo err >&2")
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+	stdout.Close()
+	stderr.Close()
+
+	output := buf.String()
+	if !strings.Contains(output, "[INFO] out {stream: stdout}") || !strings.Contains(output, "[WARN] err {stream: stderr}") {
+		t.Errorf("Expected both streams in output, got: %s", output)
+	}
+}
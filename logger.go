@@ -2,6 +2,7 @@ package dy
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -9,6 +10,7 @@ import (
 	"runtime"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -28,6 +30,11 @@ const (
 	FatalLevel
 )
 
+// DPanicLevel logs assertion failures that panic in development, see Logger.DPanic.
+// Its value follows FatalLevel so the values of the other levels stay unchanged,
+// but it ranks between ErrorLevel and FatalLevel, see Level.Severity.
+const DPanicLevel = FatalLevel + 1
+
 func (l Level) String() string {
 	switch l {
 	case DebugLevel:
@@ -38,9 +45,14 @@ func (l Level) String() string {
 		return "WARN"
 	case ErrorLevel:
 		return "ERROR"
+	case DPanicLevel:
+		return "DPANIC"
 	case FatalLevel:
 		return "FATAL"
 	default:
+		if info, ok := lookupLevel(l); ok {
+			return info.name
+		}
 		return "UNKNOWN"
 	}
 }
@@ -57,13 +69,27 @@ func ParseLevel(l string) Level {
 		return WarnLevel
 	case "ERROR":
 		return ErrorLevel
+	case "DPANIC":
+		return DPanicLevel
 	case "FATAL":
 		return FatalLevel
 	default:
+		if level, ok := lookupLevelName(l); ok {
+			return level
+		}
 		return InfoLevel // info level for default
 	}
 }
 
+// parseLevelStrict is like ParseLevel but fails for an unknown level name
+func parseLevelStrict(name string) (Level, error) {
+	level := ParseLevel(name)
+	if !strings.EqualFold(level.String(), strings.TrimSpace(name)) {
+		return level, fmt.Errorf("unknown log level %q", name)
+	}
+	return level, nil
+}
+
 // LogEntry represents a structured log entry for JSON output
 type LogEntry struct {
 	Timestamp   string                 `json:"timestamp,omitempty"`
@@ -75,6 +101,13 @@ type LogEntry struct {
 	TraceType   string                 `json:"trace_type,omitempty"` // "entry" or "exit" for trace logs
 	ElapsedTime string                 `json:"elapsed_time,omitempty"`
 	Context     map[string]interface{} `json:"context,omitempty"` // New field for context
+	Labels      map[string]string      `json:"labels,omitempty"`  // String labels, see WithLabels
+
+	// Time is the parsed timestamp, set by EntryDecoder
+	Time time.Time `json:"-"`
+
+	epochTimestamp bool // Timestamp holds an epoch number, see WithEpochTimestamps
+	contextLen     int  // Number of leading fields taken from the logger's context
 }
 
 // CallerInfo contains information about the caller of the log function
@@ -84,7 +117,12 @@ type CallerInfo struct {
 	Line     int    `json:"line"`
 }
 
-// Logger represents a logger with configurable outputs and level
+// Logger represents a logger with configurable outputs and level.
+//
+// A nil *Logger is a valid logger that discards everything, so an optional
+// logger can be passed as nil: logging methods return immediately, methods
+// deriving a logger return nil, tracing returns a no-op function and
+// Errorf-style methods still return their error. Fatal still exits.
 type Logger struct {
 	mu           sync.Mutex
 	out          io.Writer
@@ -99,6 +137,59 @@ type Logger struct {
 	colorEnabled bool         // Add this field for color support
 	closer       func() error // Function to close the output writer
 	context      *LogContext
+	stack        stackConfig             // Controls which frames are kept in error stacks
+	frozen       bool                    // Prevents further context changes when set
+	queue        *entryQueue             // Buffers writes when backpressure is enabled
+	writeMu      *sync.Mutex             // Serializes writes to out, shared with child loggers
+	ring         *RingBuffer             // Records recent entries in memory
+	hooks        []func(entry *LogEntry) // Called after each entry is written
+	validator    fieldValidator          // Checks fields added to the context
+	validation   ValidationMode          // What to do with fields failing validation
+	development  bool                    // Development mode, see DPanic
+	sampler      *sampler                // Drops repeated entries when sampling is enabled
+	timeFormat   string                  // Layout used for timestamps
+	utc          bool                    // Render timestamps in UTC
+	epoch        EpochUnit               // Unit of numeric JSON timestamps, 0 for formatted ones
+	encoding     valueEncoding           // How time and duration field values are rendered
+	pool         *ContextFieldPool       // Supplies the per-entry field slices when set
+	flagsExclude map[string]bool         // Flags left out by WithContextFromFlags
+	sensitive    map[string]bool         // Query parameters redacted by WithContextFromURL
+	heartbeat    heartbeatFunc           // Extra fields added to heartbeat entries
+	filters      []entryFilter           // Entries are dropped unless every filter passes
+	filtered     *atomic.Uint64          // Number of entries dropped by filters
+	transforms   []FieldTransformer      // Applied to every field before encoding
+	callerSkip   int                     // Extra frames skipped when reporting the caller
+	callSite     *CallerInfo             // Fixed caller reported instead of the real one
+	traceSampler *traceSampler           // Samples whole traces, see EnableDistributedSampling
+	collisions   collisionConfig         // Handling of context keys that are reserved
+	errorFields  errorFieldConfig        // Rendering of error values in context fields
+	redaction    redactConfig            // Field values replaced by [REDACTED]
+	limits       valueLimits             // Bounds the depth and length of field values
+	traceIDGen   traceIDGenerator        // Creates the ids attached by WithNewTraceID
+	traceIDKey   string                  // Context key of ids attached by WithNewTraceID
+	maxValueLen  int                     // Runes kept of each field value, 0 for all
+	checkpoints  *checkpoints            // Times set by Checkpoint
+	jwtMapping   map[string]string       // Context keys of JWT claims, see WithContextFromJWT
+	ctxCache     *contextCache           // Context fields prepared for output, not shared with children
+	traceCond    traceCondition          // Decides per call whether TraceFunction traces
+	sqlArgs      bool                    // Log argument values in WithSQLContext
+	accumulator  *contextAccumulator     // Records added context fields, see WithContextAccumulator
+	tees         []ContextTee            // Called for each added context field, see WithContextTee
+	patterns     []patternRule           // Suppress or demote matching entries, see WithSuppressPattern
+	suppressed   *atomic.Uint64          // Number of entries suppressed or demoted by patterns
+	dynamic      []dynamicField          // Fields computed per entry, see WithDynamicField
+	required     []requiredField         // Context keys every entry must have, see WithRequiredFields
+	formats      formatStrategy          // Custom formatters, see WithFormatStrategy
+	optionErrs   []error                 // Errors reported by options, see NewE
+	labels       map[string]string       // Indexed string labels, see WithLabels
+	keys         *keyChecker             // Validates context keys, see WithStrictKeys
+	normalize    keyNormalization        // Naming convention of encoded keys, see WithKeyNormalization
+	highlights   *highlighter            // Highlighted field values, see WithHighlight
+
+	autoStack       bool  // Capture a stack trace for entries at or above autoStackLevel
+	autoStackLevel  Level // Minimum level for automatic stack traces
+	callerFullPath  bool  // Report the module-relative caller file instead of its base name
+	errorStackLevel Level // Error stacks are dropped from entries below this level
 }
 
 // Option is a function that modifies a Logger
@@ -118,6 +209,20 @@ func WithLevel(level Level) Option {
 	}
 }
 
+// WithLevelString sets the minimum log level by name, e.g. "warn" from a
+// configuration file. An unknown name keeps the current level and is
+// reported by NewE.
+func WithLevelString(name string) Option {
+	return func(l *Logger) {
+		level, err := parseLevelStrict(name)
+		if err != nil {
+			l.optionError(err)
+			return
+		}
+		l.level = level
+	}
+}
+
 // WithPrefix sets a prefix for all log messages
 func WithPrefix(prefix string) Option {
 	return func(l *Logger) {
@@ -153,6 +258,46 @@ func WithJSONFormat(enable bool) Option {
 	}
 }
 
+// WithDevelopment enables or disables development mode, in which DPanic panics
+func WithDevelopment(enable bool) Option {
+	return func(l *Logger) {
+		l.development = enable
+	}
+}
+
+// defaultTimeFormat is the layout used for timestamps unless WithTimeFormat is set
+const defaultTimeFormat = "2006-01-02 15:04:05.000"
+
+// WithTimeFormat sets the layout used for timestamps, e.g. time.RFC3339
+func WithTimeFormat(layout string) Option {
+	return func(l *Logger) {
+		l.timeFormat = layout
+	}
+}
+
+// WithUTC renders timestamps in UTC instead of local time
+func WithUTC(enable bool) Option {
+	return func(l *Logger) {
+		l.utc = enable
+	}
+}
+
+// WithErrorStackLevel only keeps the stack trace of errors attached with
+// WithError on entries at or above level; below it the stack is left out
+func WithErrorStackLevel(level Level) Option {
+	return func(l *Logger) {
+		l.errorStackLevel = level
+	}
+}
+
+// WithEntryHook registers a function called with every entry after it has
+// been written. Hooks run on the logging goroutine and must not block.
+func WithEntryHook(hook func(entry *LogEntry)) Option {
+	return func(l *Logger) {
+		l.hooks = append(l.hooks, hook)
+	}
+}
+
 // WithCallerInfo enables or disables including caller information (file, line, function)
 func WithCallerInfo(enable bool) Option {
 	return func(l *Logger) {
@@ -160,8 +305,34 @@ func WithCallerInfo(enable bool) Option {
 	}
 }
 
-// New creates a new Logger with the given options
+// New creates a new Logger with the given options. It never fails: options
+// that cannot be applied fall back to a working setting and their errors are
+// printed to stderr, see NewE.
 func New(options ...Option) *Logger {
+	l := newLogger(options)
+	for _, err := range l.optionErrs {
+		fmt.Fprintf(os.Stderr, "dy: %v\n", err)
+	}
+	l.optionErrs = nil
+	return l
+}
+
+// NewE is like New but fails if any option could not be applied, e.g. a
+// WithRotateWriter whose file cannot be created, returning the errors of all
+// failing options joined together
+func NewE(options ...Option) (*Logger, error) {
+	l := newLogger(options)
+	if len(l.optionErrs) > 0 {
+		err := errors.Join(l.optionErrs...)
+		l.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// newLogger creates a logger with the default settings and applies options,
+// collecting their errors in optionErrs
+func newLogger(options []Option) *Logger {
 	l := &Logger{
 		out:          os.Stdout,
 		level:        InfoLevel,
@@ -173,6 +344,15 @@ func New(options ...Option) *Logger {
 		callerInfo:   false, // Default to no caller info
 		colorEnabled: true,  // Default to using colors
 		context:      &LogContext{},
+		stack:        stackConfig{maxFrames: defaultMaxStackFrames},
+		timeFormat:   defaultTimeFormat,
+		writeMu:      &sync.Mutex{},
+		filtered:     &atomic.Uint64{},
+		suppressed:   &atomic.Uint64{},
+		highlights:   &highlighter{},
+		checkpoints:  &checkpoints{times: make(map[string]time.Time)},
+		ctxCache:     &contextCache{},
+		limits:       valueLimits{maxDepth: defaultMaxValueDepth, maxLen: defaultMaxCollectionLen},
 	}
 
 	for _, option := range options {
@@ -182,167 +362,373 @@ func New(options ...Option) *Logger {
 	return l
 }
 
+// optionError records an error of an option being applied
+func (l *Logger) optionError(err error) {
+	l.optionErrs = append(l.optionErrs, err)
+}
+
 // DefaultLogger is the default logger used by package-level functions
 var DefaultLogger = New()
 
 // log writes a log message if the level is sufficient
 func (l *Logger) log(level Level, format string, args ...interface{}) {
-	if level < l.level {
+	if l == nil {
+		return
+	}
+
+	// Entries below the level are still recorded by a ring buffer
+	if level.Severity() < l.level.Severity() && l.ring == nil {
+		return
+	}
+
+	if !l.sampler.allow(level, format) {
 		return
 	}
 
 	// Format the message
 	msg := fmt.Sprintf(format, args...)
 
+	entry, fields := l.buildEntry(level, msg, 1) // skip the calling method
+	l.emit(level, entry, fields)
+
+	// Nothing keeps the field slice once the entry has been emitted
+	l.pool.put(fields)
+}
+
+// buildEntry creates the entry for a message at level along with the context
+// fields to emit with it. skip is the number of frames between the caller of
+// buildEntry and the code being logged.
+func (l *Logger) buildEntry(level Level, msg string, skip int) (*LogEntry, []ContextField) {
 	// Acquire lock only for reading state
 	l.mu.Lock()
 	nestingLevel := l.nestingLevel
 	hasPrefix := l.prefix != ""
 	prefixValue := l.prefix
 	hasTimestamp := l.timestamp
-	traceEnabled := l.traceEnabled
-	indentStr := l.indentString
-	useJSON := l.jsonFormat
 	includeCaller := l.callerInfo
-	out := l.out // Keep a reference to output
-	context := l.context
+	fields := l.context.appendFieldsFor(l.pool.get(), level)
+	contextLen := len(fields)
+	autoStack := l.autoStack && level.Severity() >= l.autoStackLevel.Severity()
+	stackCfg := l.stack
+	if l.jsonFormat && !stackCfg.snippetsInJSON {
+		stackCfg.snippets = 0
+	}
+	errorStacks := level.Severity() >= l.errorStackLevel.Severity()
+	skip += l.callerSkip
+	callSite := l.callSite
+	labels := l.labels
 	l.mu.Unlock()
 
+	if !errorStacks {
+		fields = withoutErrorStacks(fields)
+	}
+
+	// Attach a stack trace to entries at or above the auto stack level
+	if autoStack && !hasField(fields, "stack") {
+		fields = append(fields, ContextField{Key: "stack", Value: StackTrace(captureStack(skip+3, stackCfg))}) // skip buildEntry and its caller
+	}
+
 	// Get caller info if enabled
 	var caller *CallerInfo
 	if includeCaller {
-		caller = getCaller(3) // skip log, calling method, and actual caller
+		if callSite != nil {
+			caller = l.formatCallSite(callSite)
+		} else {
+			caller = getCaller(skip+3, l.callerPaths()) // skip getCaller, buildEntry and its caller
+		}
 	}
 
 	// Current time for timestamp
 	now := time.Now()
-	timestampStr := now.Format("2006-01-02 15:04:05.000")
 
-	if useJSON {
-		// Create a structured log entry
-		entry := LogEntry{
-			Level:     level.String(),
-			Message:   msg,
-			NestLevel: nestingLevel,
-		}
+	// Create a structured log entry
+	entry := LogEntry{
+		Level:      level.String(),
+		Message:    msg,
+		NestLevel:  nestingLevel,
+		Labels:     labels,
+		contextLen: contextLen,
+	}
 
-		if hasTimestamp {
-			entry.Timestamp = timestampStr
-		}
+	if hasTimestamp {
+		l.stamp(&entry, now)
+	}
 
-		if hasPrefix {
-			entry.Prefix = prefixValue
-		}
+	if hasPrefix {
+		entry.Prefix = prefixValue
+	}
 
-		if includeCaller {
-			entry.Caller = caller
-		}
+	if includeCaller {
+		entry.Caller = caller
+	}
 
-		// Add context fields if they exist
-		if context != nil && len(context.Fields) > 0 {
-			contextMap := make(map[string]interface{})
-			for _, field := range context.Fields {
-				contextMap[field.Key] = field.Value
-			}
-			entry.Context = contextMap
+	return &entry, fields
+}
+
+// emit formats an entry with the given context fields and writes it to the
+// output. Entries below the logger's level are only recorded by the ring buffer.
+func (l *Logger) emit(level Level, entry *LogEntry, fields []ContextField) {
+	if l.output(level, entry, fields) && level == FatalLevel {
+		l.flushQueue(fatalDrainTimeout)
+		os.Exit(1)
+	}
+}
+
+// output formats and writes an entry like emit, without exiting on fatal
+// entries. It reports whether the entry was written.
+func (l *Logger) output(level Level, entry *LogEntry, fields []ContextField) bool {
+	l.mu.Lock()
+	minLevel := l.level
+	traceEnabled := l.traceEnabled
+	indentStr := l.indentString
+	useJSON := l.jsonFormat
+	out := l.out // Keep a reference to output
+	filters := l.filters
+	patterns := l.patterns
+	dynamic := l.dynamic
+	required := l.required
+	formats := l.formats
+	traceSampler := l.traceSampler
+	l.mu.Unlock()
+
+	if !traceSampler.allow(level, fields) {
+		return false
+	}
+
+	// Dynamic fields are only computed for entries that will be written
+	if len(dynamic) > 0 && level.Severity() >= minLevel.Severity() {
+		fields = appendDynamicFields(fields, dynamic, level)
+	}
+
+	// Global overrides replace same-named fields and come last
+	fields, contextLen := applyGlobalOverrides(fields, entry.contextLen)
+
+	// The logger's own context fields are prepared once and cached
+	fields, contextParts, errorData := l.prepareEntryFields(level, useJSON, entry, fields, contextLen)
+
+	// Pattern rules are only evaluated when some are registered
+	if len(patterns) > 0 {
+		var ok bool
+		if level, ok = l.applyPatterns(level, entry, patterns); !ok {
+			return false
 		}
+	}
 
+	if !l.keep(entry, filters) {
+		return false
+	}
+
+	if level.Severity() < minLevel.Severity() {
+		l.ring.add(entry)
+		return false
+	}
+
+	if len(required) > 0 && !l.checkRequired(entry, required) {
+		return false
+	}
+
+	var line string
+	if formatter := formats.formatter(level, useJSON); formatter != nil {
+		line = formatter(level, entry)
+	} else if useJSON {
 		// Marshal to JSON
 		jsonData, err := json.Marshal(entry)
 		if err != nil {
 			// Fallback to plain text if JSON marshaling fails
-			fmt.Fprintf(out, "ERROR marshaling log entry to JSON: %v\n", err)
+			line = fmt.Sprintf("ERROR marshaling log entry to JSON: %v", err)
 		} else {
-			fmt.Fprintln(out, string(jsonData))
+			line = string(jsonData)
 		}
 	} else {
-		// Original text format
-		var prefix string
-		if hasPrefix {
-			prefix = prefixValue + " "
+		var indent string
+		if traceEnabled && entry.NestLevel > 0 {
+			indent = strings.Repeat(indentStr, entry.NestLevel)
 		}
 
-		var timestamp string
-		if hasTimestamp {
-			timestamp = timestampStr + " "
+		// Highlights are only looked for when some are registered
+		var lineColor string
+		if rules := l.highlights.active(); len(rules) > 0 && l.colorEnabled && isTerminal(out) {
+			contextParts, lineColor = highlightParts(rules, entry, contextParts)
 		}
+		line = l.formatTextParts(level, entry, contextParts, errorData, indent)
+		if lineColor != "" {
+			line = highlightLine(line, lineColor)
+		}
+	}
 
-		var indent string
-		if traceEnabled && nestingLevel > 0 {
-			indent = strings.Repeat(indentStr, nestingLevel)
+	l.writeEntry(out, entry, line)
+	return true
+}
+
+// formatText renders an entry in the human readable text format
+func (l *Logger) formatText(level Level, entry *LogEntry, fields []ContextField, indent string) string {
+	contextParts, errorData := textContextParts(nil, fields)
+	return l.formatTextParts(level, entry, contextParts, errorData, indent)
+}
+
+// textContextParts appends the rendered "key: value" parts of fields to parts.
+// Error data under the "error" key is returned separately, to be rendered on
+// its own lines; when there are several the last one wins.
+func textContextParts(parts []string, fields []ContextField) ([]string, *ErrorData) {
+	var errorData *ErrorData
+	for _, field := range fields {
+		if field.Key == "error" {
+			// Save error data for special handling
+			if data, ok := field.Value.(ErrorData); ok {
+				errorData = &data
+				continue
+			}
+		}
+		if data, ok := field.Value.(ErrorData); ok {
+			part := fmt.Sprintf("%s: %s", field.Key, data.Message)
+			if data.Code != "" {
+				part += fmt.Sprintf(" (code=%s)", data.Code)
+			}
+			parts = append(parts, part)
+			continue
+		}
+		if raw, ok := field.Value.(json.RawMessage); ok {
+			parts = append(parts, fmt.Sprintf("%s: %s", field.Key, raw))
+			continue
 		}
+		parts = append(parts, fmt.Sprintf("%s: %v", field.Key, field.Value))
+	}
+	return parts, errorData
+}
 
-		// Add caller info if enabled
-		var callerInfo string
-		if includeCaller && caller != nil {
-			callerInfo = fmt.Sprintf(" [%s:%d %s] ", caller.File, caller.Line, caller.Function)
+// formatTextParts renders an entry in the text format from its rendered
+// context parts and error data
+func (l *Logger) formatTextParts(level Level, entry *LogEntry, contextParts []string, errorData *ErrorData, indent string) string {
+	var prefix string
+	if entry.Prefix != "" {
+		prefix = entry.Prefix + " "
+	}
+
+	var timestamp string
+	if entry.Timestamp != "" {
+		timestamp = entry.Timestamp + " "
+	}
+
+	// Add caller info if enabled
+	var callerInfo string
+	if caller := entry.Caller; caller != nil {
+		callerInfo = fmt.Sprintf(" [%s:%d %s] ", caller.File, caller.Line, caller.Function)
+	}
+
+	var labels string
+	if len(entry.Labels) > 0 {
+		labels = " " + formatLabels(entry.Labels)
+	}
+
+	// Format the base log message
+	logMsg := fmt.Sprintf("%s%s[%s]%s%s %s%s", timestamp, prefix, l.colorizeLevel(level), labels, callerInfo, indent, entry.Message)
+
+	// Add context fields if they exist
+	if len(contextParts) > 0 || errorData != nil {
+		// Add context fields
+		if len(contextParts) > 0 {
+			logMsg += " {" + strings.Join(contextParts, ", ") + "}"
 		}
 
-		// Format the base log message
-		logMsg := fmt.Sprintf("%s%s[%s]%s %s%s", timestamp, prefix, l.colorizeLevel(level), callerInfo, indent, msg)
-
-		// Add context fields if they exist
-		if context != nil && len(context.Fields) > 0 {
-			var contextParts []string
-			var errorData *ErrorData
-
-			// First handle regular fields
-			for _, field := range context.Fields {
-				if field.Key == "error" {
-					// Save error data for special handling
-					if data, ok := field.Value.(ErrorData); ok {
-						errorData = &data
-						continue
-					}
-				}
-				contextParts = append(contextParts, fmt.Sprintf("%s: %v", field.Key, field.Value))
+		// Add error information in a more readable format
+		if errorData != nil {
+			logMsg += fmt.Sprintf("\n%sError: %s", indent+"  ", errorData.Message)
+			if errorData.Code != "" {
+				logMsg += fmt.Sprintf(" (code=%s)", errorData.Code)
 			}
-
-			// Add context fields
-			if len(contextParts) > 0 {
-				logMsg += " {" + strings.Join(contextParts, ", ") + "}"
+			if errorData.GroupKey != "" {
+				logMsg += fmt.Sprintf("\n%sGroup: %s", indent+"  ", errorData.GroupKey)
 			}
 
-			// Add error information in a more readable format
-			if errorData != nil {
-				logMsg += fmt.Sprintf("\n%sError: %s", indent+"  ", errorData.Message)
-				if errorData.Code != "" {
-					logMsg += fmt.Sprintf(" (code=%s)", errorData.Code)
-				}
-
-				// Add stack trace if available
-				if len(errorData.Stack) > 0 {
-					logMsg += "\n" + indent + "  Stack:"
-					for i, frame := range errorData.Stack {
-						logMsg += fmt.Sprintf("\n%s%d: %s at %s:%d", indent+"    ", i+1, frame.Function, frame.File, frame.Line)
+			// Add stack trace if available
+			if len(errorData.Stack) > 0 {
+				logMsg += "\n" + indent + "  Stack:"
+				for i, frame := range errorData.Stack {
+					logMsg += fmt.Sprintf("\n%s%d: %s at %s:%d", indent+"    ", i+1, frame.Function, frame.File, frame.Line)
+					if len(frame.SourceContext) > 0 {
+						for _, line := range frame.SourceContext {
+							logMsg += "\n" + indent + "        | " + line
+						}
+					} else if frame.Source != "" {
+						logMsg += "\n" + indent + "        > " + frame.Source
 					}
 				}
+			}
 
-				// Add error attributes if available
-				if len(errorData.Attributes) > 0 {
-					logMsg += "\n" + indent + "  Attributes:"
-					for k, v := range errorData.Attributes {
-						logMsg += fmt.Sprintf("\n%s%s: %v", indent+"    ", k, v)
-					}
+			// Add error attributes if available
+			if len(errorData.Attributes) > 0 {
+				logMsg += "\n" + indent + "  Attributes:"
+				for k, v := range errorData.Attributes {
+					logMsg += fmt.Sprintf("\n%s%s: %v", indent+"    ", k, v)
 				}
+			}
 
-				// Add cause if available
-				if errorData.Cause != nil {
-					logMsg += fmt.Sprintf("\n%sCaused by: %s", indent+"  ", errorData.Cause.Message)
-				}
+			// Add cause if available
+			if errorData.Cause != nil {
+				logMsg += fmt.Sprintf("\n%sCaused by: %s", indent+"  ", errorData.Cause.Message)
 			}
 		}
+	}
+
+	return logMsg
+}
 
-		fmt.Fprintln(out, logMsg)
+// formatTime renders a timestamp using the configured layout and time zone
+func (l *Logger) formatTime(t time.Time) string {
+	l.mu.Lock()
+	layout := l.timeFormat
+	utc := l.utc
+	l.mu.Unlock()
+
+	if utc {
+		t = t.UTC()
 	}
+	return t.Format(layout)
+}
 
-	if level == FatalLevel {
-		os.Exit(1)
+// writeEntry writes a formatted log line to out, handing it to the
+// backpressure queue instead when one is configured, then records the
+// entry in the ring buffer and passes it to the entry hooks.
+//
+// Every entry, including trace entries and multi-line text, must reach out
+// through this function as one complete line with a single Write call, so
+// concurrent entries cannot interleave in writers without their own locking.
+func (l *Logger) writeEntry(out io.Writer, entry *LogEntry, line string) {
+	l.mu.Lock()
+	queue := l.queue
+	ring := l.ring
+	hooks := l.hooks
+	l.mu.Unlock()
+
+	data := []byte(line + "\n")
+	if queue == nil || !queue.enqueue(out, l.writeMu, entry, data) {
+		l.writeMu.Lock()
+		out.Write(data)
+		l.writeMu.Unlock()
+	}
+
+	ring.add(entry)
+	for _, hook := range hooks {
+		hook(entry)
 	}
 }
 
-// getCaller returns information about the calling function
-func getCaller(skip int) *CallerInfo {
+// callerPaths returns the path configuration used for caller info,
+// or nil when only the file's base name should be reported
+func (l *Logger) callerPaths() *pathConfig {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.callerFullPath {
+		return nil
+	}
+	paths := l.stack.paths
+	return &paths
+}
+
+// getCaller returns information about the calling function. When paths is
+// nil only the base name of the file is reported.
+func getCaller(skip int, paths *pathConfig) *CallerInfo {
 	pc, file, line, ok := runtime.Caller(skip)
 	if !ok {
 		return &CallerInfo{
@@ -352,15 +738,17 @@ func getCaller(skip int) *CallerInfo {
 		}
 	}
 
-	// Get function name
-	fn := runtime.FuncForPC(pc)
-	funcName := fn.Name()
+	// Names are resolved once per call site
+	names := callers.lookup(pc)
 
-	// Shorten the file path to just filename
+	// Shorten the file path to just filename unless the full path was requested
 	fileName := filepath.Base(file)
+	if paths != nil {
+		fileName = paths.trim(file, names.function)
+	}
 
 	return &CallerInfo{
-		Function: funcName,
+		Function: names.function,
 		File:     fileName,
 		Line:     line,
 	}
@@ -373,12 +761,8 @@ func getFunctionName(skip int) string {
 		return "unknown"
 	}
 
-	// Get full function name (including package path)
-	fullName := runtime.FuncForPC(pc).Name()
-
-	// Extract just the function name (without package path)
-	parts := strings.Split(fullName, ".")
-	return parts[len(parts)-1]
+	// Just the function name, without the package path
+	return callers.lookup(pc).short
 }
 
 // Debug logs a debug message
@@ -401,30 +785,124 @@ func (l *Logger) Error(format string, args ...interface{}) {
 	l.log(ErrorLevel, format, args...)
 }
 
+// Log logs a message at an arbitrary level, including levels added with RegisterLevel
+func (l *Logger) Log(level Level, format string, args ...interface{}) {
+	l.log(level, format, args...)
+}
+
+// LogEntry emits a pre-built entry, e.g. one forwarded from another process or
+// read back with a decoder. It behaves like Emit, discarding its error: nil
+// entries and entries with an unknown level are dropped.
+func (l *Logger) LogEntry(e *LogEntry) {
+	_ = l.Emit(e)
+}
+
+// DPanic logs an assertion failure. In development mode (see WithDevelopment)
+// it logs at DPanicLevel and then panics with the message; otherwise it logs
+// at ErrorLevel with a "dpanic: true" field and continues.
+func (l *Logger) DPanic(format string, args ...interface{}) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	development := l.development
+	l.mu.Unlock()
+
+	if development {
+		l.log(DPanicLevel, format, args...)
+		panic(fmt.Sprintf(format, args...))
+	}
+	l.WithContext("dpanic", true).log(ErrorLevel, format, args...)
+}
+
 // Fatal logs a fatal message and exits
 func (l *Logger) Fatal(format string, args ...interface{}) {
+	if l == nil {
+		os.Exit(1)
+	}
+
 	l.log(FatalLevel, format, args...)
 }
 
 // SetLevel sets the minimum log level
 func (l *Logger) SetLevel(level Level) {
+	if l == nil {
+		return
+	}
+
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	l.level = level
 }
 
+// traceCondition decides whether a call to TraceFunction is traced
+type traceCondition func() bool
+
+// ConditionalTrace creates a new logger whose TraceFunction only traces when
+// fn returns true. fn is called at the start of every TraceFunction call, so
+// tracing can follow a feature flag or other runtime setting:
+//
+//	l.ConditionalTrace(func() bool { return flags.IsEnabled("log_traces") })
+//
+// Tracing must still be enabled with WithTrace.
+func (l *Logger) ConditionalTrace(fn func() bool) *Logger {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	child := l.newChild()
+	child.context = l.context.Clone()
+	child.traceCond = fn
+
+	return child
+}
+
+// traceAllowed reports whether the trace condition, if any, allows tracing
+func (l *Logger) traceAllowed() bool {
+	l.mu.Lock()
+	cond := l.traceCond
+	l.mu.Unlock()
+
+	return cond == nil || cond()
+}
+
 // TraceFunction logs entry and exit of a function with proper nesting
 // It returns a function that should be deferred to log the exit
 func (l *Logger) TraceFunction(args ...interface{}) func() {
-	if !l.traceEnabled || DebugLevel < l.level {
+	exit := l.traceEnter(1, args) // skip TraceFunction
+	if exit == nil {
 		return func() {}
 	}
+	return func() {
+		exit(1, nil) // skip this function
+	}
+}
+
+// traceEnter logs the entry of the function skip frames above its caller
+// and returns the function logging its exit with the given fields, where
+// skip counts the frames between the exit function's caller and the traced
+// code. It returns nil when tracing is disabled.
+func (l *Logger) traceEnter(skip int, args []interface{}) func(skip int, fields []ContextField) {
+	if l == nil {
+		return nil
+	}
+
+	if !l.traceEnabled || DebugLevel.Severity() < l.level.Severity() {
+		return nil
+	}
+	if !l.traceAllowed() {
+		return nil
+	}
 
 	// Get calling function name and location
-	funcName := getFunctionName(2) // skip TraceFunction and caller
+	funcName := getFunctionName(skip + 2) // skip getFunctionName and traceEnter
 	var caller *CallerInfo
 	if l.callerInfo {
-		caller = getCaller(2)
+		caller = getCaller(skip+2, l.callerPaths())
 	}
 
 	// Prepare the entry message outside the lock
@@ -453,38 +931,39 @@ func (l *Logger) TraceFunction(args ...interface{}) func() {
 
 	// Record start time for elapsed time calculation
 	startTime := time.Now()
-	timestampStr := startTime.Format("2006-01-02 15:04:05.000")
+	timestampStr := l.formatTime(startTime)
 
 	// Log after releasing the lock to avoid potential deadlock
-	if DebugLevel >= l.level {
-		if useJSON {
-			// Create a structured log entry
-			entry := LogEntry{
-				Level:     DebugLevel.String(),
-				Message:   entryMsg,
-				NestLevel: currentLevel,
-				TraceType: "entry",
-			}
+	if DebugLevel.Severity() >= l.level.Severity() {
+		// Create a structured log entry
+		entry := LogEntry{
+			Level:     DebugLevel.String(),
+			Message:   entryMsg,
+			NestLevel: currentLevel,
+			TraceType: "entry",
+		}
 
-			if hasTimestamp {
-				entry.Timestamp = timestampStr
-			}
+		if hasTimestamp {
+			l.stamp(&entry, startTime)
+		}
 
-			if hasPrefix {
-				entry.Prefix = prefixValue
-			}
+		if hasPrefix {
+			entry.Prefix = prefixValue
+		}
 
-			if includeCaller && caller != nil {
-				entry.Caller = caller
-			}
+		if includeCaller && caller != nil {
+			entry.Caller = caller
+		}
 
+		var line string
+		if useJSON {
 			// Marshal to JSON
 			jsonData, err := json.Marshal(entry)
 			if err != nil {
 				// Fallback to plain text if JSON marshaling fails
-				fmt.Fprintf(out, "ERROR marshaling trace entry to JSON: %v\n", err)
+				line = fmt.Sprintf("ERROR marshaling trace entry to JSON: %v", err)
 			} else {
-				fmt.Fprintln(out, string(jsonData))
+				line = string(jsonData)
 			}
 		} else {
 			// Original text format
@@ -509,16 +988,20 @@ func (l *Logger) TraceFunction(args ...interface{}) func() {
 				callerInfo = fmt.Sprintf(" [%s:%d %s] ", caller.File, caller.Line, caller.Function)
 			}
 
-			fmt.Fprintf(out, "%s%s[%s]%s %s%s\n", timestamp, prefix, l.colorizeLevel(DebugLevel), callerInfo, indent, entryMsg)
+			line = fmt.Sprintf("%s%s[%s]%s %s%s", timestamp, prefix, l.colorizeLevel(DebugLevel), callerInfo, indent, entryMsg)
+		}
+
+		if l.keep(&entry, l.currentFilters()) {
+			l.writeEntry(out, &entry, line)
 		}
 	}
 
 	// Return function to be deferred
-	return func() {
+	return func(skip int, fields []ContextField) {
 		exitMsg := fmt.Sprintf("← Exiting %s", funcName)
 		endTime := time.Now()
 		elapsed := endTime.Sub(startTime)
-		elapsedStr := elapsed.String()
+		elapsedStr := l.formatElapsed(elapsed)
 
 		// Lock only for the minimal necessary operations
 		l.mu.Lock()
@@ -538,44 +1021,54 @@ func (l *Logger) TraceFunction(args ...interface{}) func() {
 		l.mu.Unlock()
 
 		// Log after releasing the lock
-		if DebugLevel >= l.level {
-			timestampStr := endTime.Format("2006-01-02 15:04:05.000")
+		if DebugLevel.Severity() >= l.level.Severity() {
+			timestampStr := l.formatTime(endTime)
 
-			if useJSON {
-				// Get updated caller info for exit
-				var exitCaller *CallerInfo
-				if includeCaller {
-					exitCaller = getCaller(2)
-				}
+			// Get updated caller info for exit
+			var exitCaller *CallerInfo
+			if includeCaller {
+				exitCaller = getCaller(skip+2, l.callerPaths())
+			}
 
-				// Create a structured log entry
-				entry := LogEntry{
-					Level:       DebugLevel.String(),
-					Message:     exitMsg,
-					NestLevel:   currentLevel,
-					TraceType:   "exit",
-					ElapsedTime: elapsedStr,
-				}
+			// Create a structured log entry
+			entry := LogEntry{
+				Level:       DebugLevel.String(),
+				Message:     exitMsg,
+				NestLevel:   currentLevel,
+				TraceType:   "exit",
+				ElapsedTime: elapsedStr,
+			}
 
-				if hasTimestamp {
-					entry.Timestamp = timestampStr
-				}
+			if hasTimestamp {
+				l.stamp(&entry, endTime)
+			}
 
-				if hasPrefix {
-					entry.Prefix = prefixValue
-				}
+			if hasPrefix {
+				entry.Prefix = prefixValue
+			}
 
-				if includeCaller && exitCaller != nil {
-					entry.Caller = exitCaller
+			if includeCaller && exitCaller != nil {
+				entry.Caller = exitCaller
+			}
+
+			// Add the fields passed on exit, such as return values
+			if len(fields) > 0 {
+				fields = l.prepareFields(fields)
+				entry.Context = make(map[string]interface{}, len(fields))
+				for _, field := range fields {
+					entry.Context[field.Key] = field.Value
 				}
+			}
 
+			var line string
+			if useJSON {
 				// Marshal to JSON
 				jsonData, err := json.Marshal(entry)
 				if err != nil {
 					// Fallback to plain text if JSON marshaling fails
-					fmt.Fprintf(out, "ERROR marshaling trace exit to JSON: %v\n", err)
+					line = fmt.Sprintf("ERROR marshaling trace exit to JSON: %v", err)
 				} else {
-					fmt.Fprintln(out, string(jsonData))
+					line = string(jsonData)
 				}
 			} else {
 				// Original text format
@@ -596,14 +1089,28 @@ func (l *Logger) TraceFunction(args ...interface{}) func() {
 
 				// Add caller info and elapsed time for exit
 				var exitInfo string
-				if includeCaller {
-					caller := getCaller(2)
-					exitInfo = fmt.Sprintf(" [%s:%d %s] (took %s) ", caller.File, caller.Line, caller.Function, elapsedStr)
+				if includeCaller && exitCaller != nil {
+					exitInfo = fmt.Sprintf(" [%s:%d %s] (took %s) ", exitCaller.File, exitCaller.Line, exitCaller.Function, elapsedStr)
 				} else {
 					exitInfo = fmt.Sprintf(" (took %s) ", elapsedStr)
 				}
 
-				fmt.Fprintf(out, "%s%s[%s]%s %s%s\n", timestamp, prefix, l.colorizeLevel(DebugLevel), exitInfo, indent, exitMsg)
+				line = fmt.Sprintf("%s%s[%s]%s %s%s", timestamp, prefix, l.colorizeLevel(DebugLevel), exitInfo, indent, exitMsg)
+				if len(fields) > 0 {
+					parts, errorData := textContextParts(nil, fields)
+					if errorData != nil {
+						part := "error: " + errorData.Message
+						if errorData.Code != "" {
+							part += fmt.Sprintf(" (code=%s)", errorData.Code)
+						}
+						parts = append(parts, part)
+					}
+					line += " {" + strings.Join(parts, ", ") + "}"
+				}
+			}
+
+			if l.keep(&entry, l.currentFilters()) {
+				l.writeEntry(out, &entry, line)
 			}
 		}
 	}
@@ -611,6 +1118,10 @@ func (l *Logger) TraceFunction(args ...interface{}) func() {
 
 // EnableTrace enables function call tracing
 func (l *Logger) EnableTrace() {
+	if l == nil {
+		return
+	}
+
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	l.traceEnabled = true
@@ -618,6 +1129,10 @@ func (l *Logger) EnableTrace() {
 
 // DisableTrace disables function call tracing
 func (l *Logger) DisableTrace() {
+	if l == nil {
+		return
+	}
+
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	l.traceEnabled = false
@@ -625,6 +1140,10 @@ func (l *Logger) DisableTrace() {
 
 // EnableJSONFormat enables JSON output format
 func (l *Logger) EnableJSONFormat() {
+	if l == nil {
+		return
+	}
+
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	l.jsonFormat = true
@@ -632,6 +1151,10 @@ func (l *Logger) EnableJSONFormat() {
 
 // DisableJSONFormat disables JSON output format (switches to text format)
 func (l *Logger) DisableJSONFormat() {
+	if l == nil {
+		return
+	}
+
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	l.jsonFormat = false
@@ -639,6 +1162,10 @@ func (l *Logger) DisableJSONFormat() {
 
 // EnableCallerInfo enables including caller information in logs
 func (l *Logger) EnableCallerInfo() {
+	if l == nil {
+		return
+	}
+
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	l.callerInfo = true
@@ -646,6 +1173,10 @@ func (l *Logger) EnableCallerInfo() {
 
 // DisableCallerInfo disables including caller information in logs
 func (l *Logger) DisableCallerInfo() {
+	if l == nil {
+		return
+	}
+
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	l.callerInfo = false
@@ -653,15 +1184,41 @@ func (l *Logger) DisableCallerInfo() {
 
 // GetOutput returns the current output writer
 func (l *Logger) GetOutput() io.Writer {
+	if l == nil {
+		return nil
+	}
+
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	return l.out
 }
 
+// HealthCheck verifies that the logger's output is working. It delegates to
+// the output writer if it has a HealthCheck method, such as RotateWriter, and
+// returns nil otherwise.
+func (l *Logger) HealthCheck() error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	out := l.out
+	l.mu.Unlock()
+
+	if hc, ok := out.(interface{ HealthCheck() error }); ok {
+		return hc.HealthCheck()
+	}
+	return nil
+}
+
 // Close closes any underlying resources associated with the logger
 // such as open files from a RotateWriter. It should be deferred when
 // using WithRotateWriter to ensure all logs are flushed properly.
 func (l *Logger) Close() error {
+	if l == nil {
+		return nil
+	}
+
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
@@ -696,6 +1253,16 @@ func Error(format string, args ...interface{}) {
 	DefaultLogger.Error(format, args...)
 }
 
+// Log logs a message at an arbitrary level using the default logger
+func Log(level Level, format string, args ...interface{}) {
+	DefaultLogger.Log(level, format, args...)
+}
+
+// DPanic logs an assertion failure using the default logger, panicking in development mode
+func DPanic(format string, args ...interface{}) {
+	DefaultLogger.DPanic(format, args...)
+}
+
 // Fatal logs a fatal message and exits using the default logger
 func Fatal(format string, args ...interface{}) {
 	DefaultLogger.Fatal(format, args...)
@@ -2,7 +2,15 @@ package dy
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -16,6 +24,7 @@ func TestLevelString(t *testing.T) {
 		{InfoLevel, "INFO"},
 		{WarnLevel, "WARN"},
 		{ErrorLevel, "ERROR"},
+		{DPanicLevel, "DPANIC"},
 		{FatalLevel, "FATAL"},
 		{Level(99), "UNKNOWN"},
 	}
@@ -211,6 +220,39 @@ func TestTraceFunctionEnabled(t *testing.T) {
 	}
 }
 
+func TestConditionalTrace(t *testing.T) {
+	var buf bytes.Buffer
+	var enabled atomic.Bool
+	l := New(
+		WithOutput(&buf),
+		WithTimestamp(false),
+		WithTrace(true),
+		WithLevel(DebugLevel),
+	).ConditionalTrace(enabled.Load)
+
+	traced := func() {
+		defer l.TraceFunction()()
+	}
+
+	traced()
+	if strings.Contains(buf.String(), "Entering") {
+		t.Errorf("Expected no trace while the condition is false, got: %s", buf.String())
+	}
+
+	enabled.Store(true)
+	traced()
+	if !strings.Contains(buf.String(), "→ Entering") || !strings.Contains(buf.String(), "← Exiting") {
+		t.Errorf("Expected a trace once the condition is true, got: %s", buf.String())
+	}
+
+	buf.Reset()
+	enabled.Store(false)
+	traced()
+	if buf.Len() != 0 {
+		t.Errorf("Expected no trace after the condition turns false, got: %s", buf.String())
+	}
+}
+
 func TestTraceFunctionNesting(t *testing.T) {
 	var buf bytes.Buffer
 	l := New(
@@ -339,3 +381,254 @@ func TestEnableDisableTrace(t *testing.T) {
 		t.Errorf("Expected no trace logs after disabling, got: %s", thirdOutput)
 	}
 }
+
+func TestLoggerLog(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false), WithLevel(WarnLevel))
+
+	for attempt, level := range []Level{InfoLevel, WarnLevel, ErrorLevel} {
+		l.Log(level, "attempt %d failed", attempt+1)
+	}
+
+	expected := "[WARN] attempt 2 failed\n[ERROR] attempt 3 failed\n"
+	if got := buf.String(); got != expected {
+		t.Errorf("Logger.Log() output = %q, want %q", got, expected)
+	}
+}
+
+func TestLoggerLogEntry(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false), WithLevel(InfoLevel)).
+		WithContext("service", "proxy").
+		WithContext("region", "eu")
+
+	l.LogEntry(&LogEntry{Level: "DEBUG", Message: "filtered"})
+	if buf.Len() > 0 {
+		t.Errorf("Expected entry below level to be filtered, got %q", buf.String())
+	}
+
+	original := &LogEntry{
+		Level:     "WARN",
+		Message:   "forwarded",
+		Timestamp: "2024-01-02 03:04:05.000",
+		Context:   map[string]interface{}{"region": "us", "upstream": "api"},
+	}
+	l.LogEntry(original)
+
+	expected := "2024-01-02 03:04:05.000 [WARN] forwarded {service: proxy, region: us, upstream: api}\n"
+	if got := buf.String(); got != expected {
+		t.Errorf("Logger.LogEntry() output = %q, want %q", got, expected)
+	}
+	if len(original.Context) != 2 {
+		t.Errorf("Expected original entry to be left untouched, got: %v", original.Context)
+	}
+
+	// A forwarded fatal entry is written without exiting the process
+	buf.Reset()
+	l.LogEntry(&LogEntry{Level: "FATAL", Message: "upstream died"})
+	if !strings.Contains(buf.String(), "[FATAL] upstream died") {
+		t.Errorf("Expected forwarded fatal entry to be written, got %q", buf.String())
+	}
+}
+
+func TestDPanicDevelopment(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false), WithDevelopment(true))
+
+	defer func() {
+		r := recover()
+		if r != "invariant broken: 42" {
+			t.Errorf("Expected panic with formatted message, got: %v", r)
+		}
+		if got := buf.String(); got != "[DPANIC] invariant broken: 42\n" {
+			t.Errorf("Expected DPANIC entry before panic, got %q", got)
+		}
+	}()
+
+	l.DPanic("invariant broken: %d", 42)
+	t.Errorf("Expected DPanic to panic in development mode")
+}
+
+func TestDPanicProduction(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false))
+
+	l.DPanic("invariant broken: %d", 42)
+
+	expected := "[ERROR] invariant broken: 42 {dpanic: true}\n"
+	if got := buf.String(); got != expected {
+		t.Errorf("DPanic() output = %q, want %q", got, expected)
+	}
+}
+
+func TestDPanicLevelRanksBelowFatal(t *testing.T) {
+	var buf bytes.Buffer
+	New(WithOutput(&buf), WithTimestamp(false), WithLevel(FatalLevel)).Log(DPanicLevel, "filtered")
+	New(WithOutput(&buf), WithTimestamp(false), WithLevel(ErrorLevel)).Log(DPanicLevel, "kept")
+
+	if got := buf.String(); got != "[DPANIC] kept\n" {
+		t.Errorf("Expected DPANIC between ERROR and FATAL, got %q", got)
+	}
+}
+
+// boundaryWriter records the data of every Write call separately
+type boundaryWriter struct {
+	mu     sync.Mutex
+	writes []string
+}
+
+func (w *boundaryWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.writes = append(w.writes, string(p))
+	return len(p), nil
+}
+
+func TestOneWritePerEntry(t *testing.T) {
+	const goroutines, entries = 8, 50
+
+	var text, jsonOut, trace boundaryWriter
+	textLogger := New(WithOutput(&text))
+	jsonLogger := New(WithOutput(&jsonOut), WithJSONFormat(true))
+	traceLogger := New(WithOutput(&trace), WithTrace(true), WithLevel(DebugLevel))
+
+	traced := func(g, i int) {
+		defer traceLogger.TraceFunction(g, i)()
+		traceLogger.Info("entry-%d-%d", g, i)
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			err := errors.New("line one\nline two")
+			for i := 0; i < entries; i++ {
+				// Errors render over several lines with their stack
+				textLogger.WithError(err).Error("entry-%d-%d", g, i)
+				jsonLogger.WithError(err).Error("entry-%d-%d", g, i)
+				traced(g, i)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	total := goroutines * entries
+	check := func(name string, w *boundaryWriter, want int) {
+		if len(w.writes) != want {
+			t.Errorf("%s: expected %d writes, got %d", name, want, len(w.writes))
+		}
+		for _, data := range w.writes {
+			markers := strings.Count(data, "entry-") + strings.Count(data, "Entering") + strings.Count(data, "Exiting")
+			if markers != 1 || !strings.HasSuffix(data, "\n") {
+				t.Fatalf("%s: expected one complete entry per write, got %q", name, data)
+			}
+		}
+	}
+	check("text", &text, total)
+	check("json", &jsonOut, total)
+	check("trace", &trace, 3*total)
+
+	for _, data := range jsonOut.writes {
+		if !json.Valid([]byte(data)) {
+			t.Fatalf("Expected one JSON document per write, got %q", data)
+		}
+	}
+	if strings.Count(text.writes[0], "\n") < 2 {
+		t.Errorf("Expected a multi-line text entry, got %q", text.writes[0])
+	}
+}
+
+func TestOneWritePerEntrySharedWriter(t *testing.T) {
+	var w boundaryWriter
+	a := New(WithOutput(&w))
+	b := New(WithOutput(&w), WithJSONFormat(true))
+
+	var wg sync.WaitGroup
+	for _, l := range []*Logger{a, b, a.WithContext("child", true)} {
+		wg.Add(1)
+		go func(l *Logger) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				l.WithError(fmt.Errorf("failed %d", i)).Error("entry-%d", i)
+			}
+		}(l)
+	}
+	wg.Wait()
+
+	if len(w.writes) != 300 {
+		t.Fatalf("Expected 300 writes, got %d", len(w.writes))
+	}
+	for _, data := range w.writes {
+		if strings.Count(data, "entry-") != 1 || !strings.HasSuffix(data, "\n") {
+			t.Fatalf("Expected one complete entry per write, got %q", data)
+		}
+	}
+}
+
+func TestNewEReportsOptionErrors(t *testing.T) {
+	// A regular file where the log directory should be
+	blocker := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(blocker, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		option Option
+		want   string
+	}{
+		{"rotate writer", WithRotateWriter(filepath.Join(blocker, "app.log")), "failed to create rotate writer"},
+		{"rotate tee", WithRotateWriterTee(filepath.Join(blocker, "app.log"), io.Discard), "failed to create rotate writer"},
+		{"level string", WithLevelString("verbose"), `unknown log level "verbose"`},
+		{"text template", WithTextTemplate("{{.Level"), "invalid text template"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := NewE(tt.option)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("NewE() error = %v, want it to contain %q", err, tt.want)
+			}
+			if l != nil {
+				t.Errorf("NewE() returned a logger along with the error")
+			}
+		})
+	}
+}
+
+func TestNewEJoinsOptionErrors(t *testing.T) {
+	_, err := NewE(WithLevelString("loud"), WithTextTemplate("{{"))
+	if err == nil {
+		t.Fatal("NewE() error = nil")
+	}
+	for _, want := range []string{`unknown log level "loud"`, "invalid text template"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("NewE() error = %q, want it to contain %q", err, want)
+		}
+	}
+}
+
+func TestNewEValidOptions(t *testing.T) {
+	var buf bytes.Buffer
+	l, err := NewE(WithOutput(&buf), WithTimestamp(false), WithLevelString("warn"), WithTextTemplate("{{.Level}}:{{.Message}}"))
+	if err != nil {
+		t.Fatalf("NewE() error = %v", err)
+	}
+
+	l.Info("hidden")
+	l.Warn("shown")
+	if got := buf.String(); got != "WARN:shown\n" {
+		t.Fatalf("output = %q, want %q", got, "WARN:shown\n")
+	}
+}
+
+func TestNewKeepsWorkingWithOptionErrors(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false), WithColor(false), WithLevel(WarnLevel), WithLevelString("verbose"), WithTextTemplate("{{"))
+
+	l.Info("hidden")
+	l.Warn("shown")
+	if got := buf.String(); !strings.Contains(got, "[WARN] shown") || strings.Contains(got, "hidden") {
+		t.Fatalf("output = %q, want the WARN entry in the built-in format only", got)
+	}
+}
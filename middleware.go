@@ -0,0 +1,59 @@
+package dy
+
+import (
+	"context"
+	"net/http"
+)
+
+// RequestIDHeader is the header HTTPMiddleware reads an inbound correlation
+// id from
+const RequestIDHeader = "X-Request-ID"
+
+// loggerKey is the context key of the logger attached by NewContext
+type loggerKey struct{}
+
+// NewContext returns a copy of ctx carrying l, see FromContext
+func NewContext(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// FromContext returns the logger attached to ctx with NewContext, or nil,
+// which is a no-op logger, when there is none
+func FromContext(ctx context.Context) *Logger {
+	if ctx == nil {
+		return nil
+	}
+	l, _ := ctx.Value(loggerKey{}).(*Logger)
+	return l
+}
+
+// HTTPMiddleware wraps next so that every request carries a request-scoped
+// logger in its context, retrieved with FromContext. The logger has the
+// request's "method" and "path" as context fields. A valid W3C traceparent
+// header is added with WithTraceInfo; without one the logger gets a
+// correlation id as trace id (see WithTraceID): the X-Request-ID header of
+// the request, or a generated id when the header is missing. Malformed
+// traceparent headers are ignored.
+//
+// Example usage:
+//
+//	mux.HandleFunc("/users", func(w http.ResponseWriter, r *http.Request) {
+//	    dy.FromContext(r.Context()).Info("listing users")
+//	})
+//	http.ListenAndServe(":8080", logger.HTTPMiddleware(mux))
+func (l *Logger) HTTPMiddleware(next http.Handler) http.Handler {
+	if l == nil {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqLogger := l.WithContext("method", r.Method).WithContext("path", r.URL.Path)
+		if info, ok := TraceparentFromRequest(r); ok {
+			reqLogger = reqLogger.WithTraceInfo(info)
+		} else {
+			reqLogger = reqLogger.WithTraceID(r.Header.Get(RequestIDHeader))
+		}
+
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), reqLogger)))
+	})
+}
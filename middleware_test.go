@@ -0,0 +1,83 @@
+package dy
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHTTPMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false),
+		WithTraceIDGenerator(func() string { return "generated" }))
+
+	handler := l.HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		FromContext(r.Context()).Info("handled")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/users?page=2", nil)
+	req.Header.Set(RequestIDHeader, "req-42")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/orders", nil))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	expected := []string{
+		"[INFO] handled {method: GET, path: /users, trace_id: req-42}",
+		"[INFO] handled {method: POST, path: /orders, trace_id: generated}",
+	}
+	if len(lines) != len(expected) {
+		t.Fatalf("Expected %d entries, got: %s", len(expected), buf.String())
+	}
+	for i, want := range expected {
+		if lines[i] != want {
+			t.Errorf("Entry %d = %q, want %q", i, lines[i], want)
+		}
+	}
+}
+
+func TestHTTPMiddlewareTraceparent(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false),
+		WithTraceIDGenerator(func() string { return "generated" }))
+
+	handler := l.HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		FromContext(r.Context()).Info("handled")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	req.Header.Set(RequestIDHeader, "req-42")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	// A malformed header is ignored rather than failing the request
+	req = httptest.NewRequest(http.MethodGet, "/users", nil)
+	req.Header.Set("traceparent", "00-bad-00f067aa0ba902b7-01")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	output := buf.String()
+	for _, want := range []string{
+		"{method: GET, path: /users, trace_id: 4bf92f3577b34da6a3ce929d0e0e4736, parent_span_id: 00f067aa0ba902b7}",
+		"{method: GET, path: /users, trace_id: generated}",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output, got: %s", want, output)
+		}
+	}
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected the request to be served, got status %d", rec.Code)
+	}
+}
+
+func TestFromContextWithoutLogger(t *testing.T) {
+	l := FromContext(context.Background())
+	if l != nil {
+		t.Fatalf("Expected no logger, got %v", l)
+	}
+
+	// The nil logger is a no-op
+	l.Info("dropped")
+}
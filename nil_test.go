@@ -0,0 +1,115 @@
+package dy
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"reflect"
+	"testing"
+)
+
+// TestNilLogger calls every method of a nil *Logger with zero arguments,
+// and any functions they return, checking none of them panics
+func TestNilLogger(t *testing.T) {
+	var l *Logger
+	v := reflect.ValueOf(l)
+	typ := v.Type()
+
+	for i := 0; i < typ.NumMethod(); i++ {
+		method := typ.Method(i)
+		if method.Name == "Fatal" {
+			continue // exits even on a nil logger
+		}
+		t.Run(method.Name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Fatalf("%s panicked on a nil logger: %v", method.Name, r)
+				}
+			}()
+			results := v.Method(i).Call(zeroArgs(method.Type, 1))
+			for _, result := range results {
+				// The command still runs without a logger
+				if result.Kind() == reflect.Func && !result.IsNil() && method.Name != "WithSubprocessContext" {
+					result.Call(zeroArgs(result.Type(), 0))
+				}
+				if result.Type() == typ && !result.IsNil() {
+					t.Errorf("Expected %s to return a nil logger", method.Name)
+				}
+			}
+		})
+	}
+}
+
+// zeroArgs returns zero values for the parameters of fn from index first on
+func zeroArgs(fn reflect.Type, first int) []reflect.Value {
+	var args []reflect.Value
+	for i := first; i < fn.NumIn(); i++ {
+		if fn.IsVariadic() && i == fn.NumIn()-1 {
+			break
+		}
+		args = append(args, reflect.Zero(fn.In(i)))
+	}
+	return args
+}
+
+func TestNilLoggerReturnsErrors(t *testing.T) {
+	var l *Logger
+
+	if err := l.Errorf("failed: %d", 1); err == nil || err.Error() != "failed: 1" {
+		t.Errorf("Expected Errorf to return the error, got %v", err)
+	}
+	if err := l.NewError("CODE", "failed"); err == nil || err.Code() != "CODE" {
+		t.Errorf("Expected NewError to return the error, got %v", err)
+	}
+	if err := l.Rotate(); !errors.Is(err, ErrNoRotator) {
+		t.Errorf("Expected ErrNoRotator, got %v", err)
+	}
+	if child := l.WithContext("k", "v").WithError(errors.New("e")); child != nil {
+		t.Errorf("Expected derived loggers to stay nil, got %v", child)
+	}
+	l.Info("nothing happens")
+	defer l.TraceFunction()()
+}
+
+func TestNilLoggerWithArguments(t *testing.T) {
+	var l *Logger
+	err := errors.New("boom")
+	u, _ := url.Parse("https://example.com/?token=x")
+	headers := http.Header{"Traceparent": {"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}, "X-B3-Traceid": {"a3ce929d0e0e4736"}}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("name", "value", "")
+
+	derived := map[string]*Logger{
+		"WithError":            l.WithError(err),
+		"WithErrorNoStack":     l.WithErrorNoStack(err),
+		"WithErrorCode":        l.WithErrorCode("CODE"),
+		"WithContextFromURL":   l.WithContextFromURL(u),
+		"WithQueryParams":      l.WithQueryParams("a=1"),
+		"WithHTTPResponse":     l.WithHTTPResponse(&http.Response{StatusCode: 200, Header: http.Header{}}),
+		"WithB3Headers":        l.WithB3Headers(headers),
+		"WithContextFromTrace": l.WithContextFromTrace(ContextWithTraceHeaders(context.Background(), headers)),
+		"WithContextFromFlags": l.WithContextFromFlagsAll(fs),
+		"WithCmdContext":       l.WithCmdContext(exec.Command("true")),
+		"WithContextTee":       l.WithContextTee(func(string, interface{}) {}),
+		"WithDynamicField":     l.WithDynamicField("k", func() interface{} { return 1 }),
+		"WithRequiredFields":   l.WithRequiredFields("k"),
+		"WithCachedJSON":       l.WithCachedJSON("k", map[string]int{"a": 1}),
+	}
+	for name, child := range derived {
+		if child != nil {
+			t.Errorf("Expected %s to return a nil logger", name)
+		}
+	}
+
+	l.LogEntry(&LogEntry{Level: "INFO", Message: "m"})
+	if err := l.Emit(&LogEntry{Level: "INFO", Message: "m"}); err != nil {
+		t.Errorf("Expected Emit on a nil logger to succeed, got %v", err)
+	}
+	l.Trace("operation")(err)
+	l.TraceMethodWithResult(1)(2, err)
+	l.WriteStruct(InfoLevel, "k", struct{ A int }{1})
+	l.CaptureErrorEntry(err, "m")
+}
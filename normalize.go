@@ -0,0 +1,215 @@
+package dy
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// KeyCase is a naming convention context keys can be rewritten to
+type KeyCase int
+
+const (
+	// KeyCaseNone keeps keys as they are
+	KeyCaseNone KeyCase = iota
+	// SnakeCase rewrites keys like requestId and RequestID to request_id
+	SnakeCase
+	// CamelCase rewrites keys like request_id and RequestID to requestId
+	CamelCase
+)
+
+// keyNormalization controls how context keys are rewritten
+type keyNormalization struct {
+	keyCase KeyCase
+	warn    bool // Log a warning when two keys are merged
+}
+
+// WithKeyNormalization rewrites the keys of context fields and error
+// attributes to keyCase when entries are encoded, so that requestId,
+// RequestID and request_id end up in the same place. Dot-separated segments
+// are converted separately, keeping namespaces like http.status_code. When
+// several keys of an entry normalize to the same key the last one wins. The
+// logger's context itself is left as is. The default is KeyCaseNone.
+func WithKeyNormalization(keyCase KeyCase) Option {
+	return func(l *Logger) {
+		l.normalize.keyCase = keyCase
+	}
+}
+
+// WithKeyMergeWarnings logs a WARN entry the first time two distinct keys
+// are merged by WithKeyNormalization
+func WithKeyMergeWarnings(enable bool) Option {
+	return func(l *Logger) {
+		l.normalize.warn = enable
+	}
+}
+
+// maxNormalizedKeys bounds the cache of normalized keys, which is cleared
+// when full
+const maxNormalizedKeys = 1024
+
+// normalizedKey identifies a key converted to a case
+type normalizedKey struct {
+	keyCase KeyCase
+	key     string
+}
+
+// normalizedKeys caches converted keys
+var normalizedKeys = struct {
+	sync.RWMutex
+	entries map[normalizedKey]string
+}{}
+
+// mergedKeys records the key pairs a merge warning has been logged for
+var mergedKeys sync.Map
+
+// normalizeFields returns a copy of fields with normalized keys, merging
+// fields whose keys become the same
+func (l *Logger) normalizeFields(fields []ContextField, cfg keyNormalization) []ContextField {
+	result := make([]ContextField, 0, len(fields))
+	index := make(map[string]int, len(fields))
+	sources := make(map[string]string, len(fields))
+	for _, field := range fields {
+		key := normalizeKey(field.Key, cfg.keyCase)
+		value := field.Value
+		if data, ok := value.(ErrorData); ok {
+			value = normalizeErrorData(data, cfg.keyCase)
+		}
+
+		i, seen := index[key]
+		if !seen {
+			index[key] = len(result)
+			sources[key] = field.Key
+			result = append(result, ContextField{Key: key, Value: value})
+			continue
+		}
+
+		// The last field wins, at the position of the first
+		if cfg.warn && sources[key] != field.Key {
+			l.warnMerged(sources[key], field.Key, key)
+		}
+		sources[key] = field.Key
+		result[i].Value = value
+	}
+	return result
+}
+
+// warnMerged logs a warning the first time keys a and b are merged into key
+func (l *Logger) warnMerged(a, b, key string) {
+	if _, warned := mergedKeys.LoadOrStore(a+"\x00"+b, true); warned {
+		return
+	}
+	l.Warn("context keys %q and %q both normalize to %q, keeping the value of %q", a, b, key, b)
+}
+
+// normalizeErrorData returns a copy of data with normalized attribute keys,
+// including those of its causes. Merged attributes keep the value of the
+// last source key in sorted order.
+func normalizeErrorData(data ErrorData, keyCase KeyCase) ErrorData {
+	if len(data.Attributes) > 0 {
+		keys := make([]string, 0, len(data.Attributes))
+		for k := range data.Attributes {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		attrs := make(map[string]interface{}, len(data.Attributes))
+		for _, k := range keys {
+			attrs[normalizeKey(k, keyCase)] = data.Attributes[k]
+		}
+		data.Attributes = attrs
+	}
+	if data.Cause != nil {
+		cause := normalizeErrorData(*data.Cause, keyCase)
+		data.Cause = &cause
+	}
+	return data
+}
+
+// normalizeKey converts key to keyCase, using the cache
+func normalizeKey(key string, keyCase KeyCase) string {
+	if keyCase == KeyCaseNone || key == "" {
+		return key
+	}
+
+	id := normalizedKey{keyCase: keyCase, key: key}
+	normalizedKeys.RLock()
+	normalized, ok := normalizedKeys.entries[id]
+	normalizedKeys.RUnlock()
+	if ok {
+		return normalized
+	}
+
+	segments := strings.Split(key, ".")
+	for i, segment := range segments {
+		segments[i] = convertCase(segment, keyCase)
+	}
+	normalized = strings.Join(segments, ".")
+
+	normalizedKeys.Lock()
+	defer normalizedKeys.Unlock()
+	if normalizedKeys.entries == nil || len(normalizedKeys.entries) >= maxNormalizedKeys {
+		normalizedKeys.entries = make(map[normalizedKey]string, 64)
+	}
+	normalizedKeys.entries[id] = normalized
+	return normalized
+}
+
+// convertCase joins the words of s in keyCase. A segment without words,
+// such as "-", is kept as is.
+func convertCase(s string, keyCase KeyCase) string {
+	words := splitWords(s)
+	if len(words) == 0 {
+		return s
+	}
+
+	if keyCase == SnakeCase {
+		return strings.ToLower(strings.Join(words, "_"))
+	}
+
+	var b strings.Builder
+	for i, word := range words {
+		word = strings.ToLower(word)
+		if i > 0 {
+			runes := []rune(word)
+			runes[0] = unicode.ToUpper(runes[0])
+			word = string(runes)
+		}
+		b.WriteString(word)
+	}
+	return b.String()
+}
+
+// splitWords splits s into words at separators and case changes, treating
+// a run of capitals as one word: HTTPStatusCode is HTTP, Status and Code
+func splitWords(s string) []string {
+	var words []string
+	runes := []rune(s)
+	start := -1
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if start >= 0 {
+				words = append(words, string(runes[start:i]))
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+			continue
+		}
+
+		prev := runes[i-1]
+		lowerToUpper := unicode.IsUpper(r) && (unicode.IsLower(prev) || unicode.IsDigit(prev))
+		acronymEnd := unicode.IsUpper(r) && unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+		if lowerToUpper || acronymEnd {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	if start >= 0 {
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
@@ -0,0 +1,96 @@
+package dy
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeKey(t *testing.T) {
+	tests := []struct {
+		key   string
+		snake string
+		camel string
+	}{
+		{"requestId", "request_id", "requestId"},
+		{"RequestID", "request_id", "requestId"},
+		{"request_id", "request_id", "requestId"},
+		{"HTTPStatusCode", "http_status_code", "httpStatusCode"},
+		{"user-id", "user_id", "userId"},
+		{"user id", "user_id", "userId"},
+		{"userID2", "user_id2", "userId2"},
+		{"k8s.podName", "k8s.pod_name", "k8s.podName"},
+		{"__private", "private", "private"},
+		{"x", "x", "x"},
+		{"-", "-", "-"},
+	}
+	for _, tt := range tests {
+		if got := normalizeKey(tt.key, SnakeCase); got != tt.snake {
+			t.Errorf("normalizeKey(%q, SnakeCase) = %q, want %q", tt.key, got, tt.snake)
+		}
+		if got := normalizeKey(tt.key, CamelCase); got != tt.camel {
+			t.Errorf("normalizeKey(%q, CamelCase) = %q, want %q", tt.key, got, tt.camel)
+		}
+		if got := normalizeKey(tt.key, KeyCaseNone); got != tt.key {
+			t.Errorf("normalizeKey(%q, KeyCaseNone) = %q, want it unchanged", tt.key, got)
+		}
+	}
+}
+
+func TestWithKeyNormalization(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithJSONFormat(true), WithKeyNormalization(SnakeCase)).
+		WithContext("userId", 7).
+		WithError(NewError("declined", "DECLINED", map[string]interface{}{"cardType": "visa"}))
+
+	l.Error("payment failed")
+
+	var entry struct {
+		Context map[string]json.RawMessage `json:"context"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to parse JSON output %q: %v", buf.String(), err)
+	}
+	if _, ok := entry.Context["user_id"]; !ok {
+		t.Errorf("Expected user_id in the context, got %s", buf.String())
+	}
+	if !strings.Contains(string(entry.Context["error"]), `"card_type":"visa"`) {
+		t.Errorf("Expected the error attribute key to be normalized, got %s", entry.Context["error"])
+	}
+
+	// The logger's own context is unchanged
+	if _, ok := l.ContextValue("userId"); !ok {
+		t.Error("Expected the context to keep the original key")
+	}
+}
+
+func TestKeyNormalizationMerge(t *testing.T) {
+	buf := &syncBuffer{}
+	l := New(WithOutput(buf), WithTimestamp(false), WithColor(false),
+		WithKeyNormalization(SnakeCase), WithKeyMergeWarnings(true)).
+		With(ContextField{Key: "requestId", Value: "a"}, ContextField{Key: "RequestID", Value: "b"})
+
+	l.Info("first")
+	l.Info("second")
+
+	out := buf.String()
+	if n := strings.Count(out, `context keys "requestId" and "RequestID" both normalize to "request_id"`); n != 1 {
+		t.Errorf("Expected one merge warning, got %d in %q", n, out)
+	}
+	if !strings.Contains(out, "[INFO] first {request_id: b}") {
+		t.Errorf("Expected the last key to win, got %q", out)
+	}
+}
+
+func TestKeyNormalizationCamelCase(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false), WithColor(false), WithKeyNormalization(CamelCase)).
+		WithContext("http_status_code", 200)
+
+	l.Info("done")
+
+	if !strings.Contains(buf.String(), "{httpStatusCode: 200}") {
+		t.Errorf("Expected a camelCase key, got %q", buf.String())
+	}
+}
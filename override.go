@@ -0,0 +1,83 @@
+package dy
+
+import "sync"
+
+var (
+	globalOverridesMu sync.RWMutex
+	globalOverrides   []ContextField
+)
+
+// SetGlobalOverride sets a field logged by every logger of the process that
+// child loggers cannot override, such as "env": "production". It is applied
+// after all of a logger's own fields and replaces any field with the same
+// key, in the text output as well as the JSON context map. Setting an
+// existing key replaces its value.
+func SetGlobalOverride(key string, value interface{}) {
+	globalOverridesMu.Lock()
+	defer globalOverridesMu.Unlock()
+
+	// Copy on write, entries being logged keep using the previous slice
+	overrides := make([]ContextField, 0, len(globalOverrides)+1)
+	replaced := false
+	for _, field := range globalOverrides {
+		if field.Key == key {
+			field.Value = value
+			replaced = true
+		}
+		overrides = append(overrides, field)
+	}
+	if !replaced {
+		overrides = append(overrides, ContextField{Key: key, Value: value})
+	}
+	globalOverrides = overrides
+}
+
+// ClearGlobalOverride removes the override set for key by SetGlobalOverride
+func ClearGlobalOverride(key string) {
+	globalOverridesMu.Lock()
+	defer globalOverridesMu.Unlock()
+
+	overrides := make([]ContextField, 0, len(globalOverrides))
+	for _, field := range globalOverrides {
+		if field.Key != key {
+			overrides = append(overrides, field)
+		}
+	}
+	globalOverrides = overrides
+}
+
+// applyGlobalOverrides returns fields without the keys of the global
+// overrides, followed by the overrides. contextLen is adjusted for the
+// removed fields of the logger's own context, and is 0 when any was removed
+// so the cached rendering of the context is not used.
+func applyGlobalOverrides(fields []ContextField, contextLen int) ([]ContextField, int) {
+	globalOverridesMu.RLock()
+	overrides := globalOverrides
+	globalOverridesMu.RUnlock()
+
+	if len(overrides) == 0 {
+		return fields, contextLen
+	}
+
+	result := make([]ContextField, 0, len(fields)+len(overrides))
+	for i, field := range fields {
+		if overridden(overrides, field.Key) {
+			if i < contextLen {
+				contextLen = 0
+			}
+			continue
+		}
+		result = append(result, field)
+	}
+	return append(result, overrides...), contextLen
+}
+
+// overridden reports whether overrides has a field for key
+func overridden(overrides []ContextField, key string) bool {
+	for _, field := range overrides {
+		if field.Key == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package dy
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSetGlobalOverride(t *testing.T) {
+	SetGlobalOverride("env", "production")
+	defer ClearGlobalOverride("env")
+
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false)).WithContext("env", "dev").WithContext("user", "alice")
+	l.Info("text")
+	l.Info("again") // the cached context must not bring "env: dev" back
+	l.WithContext("env", "staging").Info("child")
+
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if strings.Contains(line, "env: dev") || strings.Contains(line, "env: staging") {
+			t.Errorf("Expected the override to replace the logger's field, got: %s", line)
+		}
+		if strings.Count(line, "env: production") != 1 || !strings.Contains(line, "user: alice") {
+			t.Errorf("Expected env: production once and the other fields, got: %s", line)
+		}
+	}
+}
+
+func TestSetGlobalOverrideJSON(t *testing.T) {
+	SetGlobalOverride("env", "production")
+	defer ClearGlobalOverride("env")
+
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithJSONFormat(true))
+	l.WithFields(map[string]interface{}{"env": "dev", "region": "eu"}).Info("json")
+
+	var entry LogEntry
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to decode entry: %v", err)
+	}
+	if entry.Context["env"] != "production" || entry.Context["region"] != "eu" {
+		t.Errorf("Expected env overridden and region kept, got %v", entry.Context)
+	}
+}
+
+func TestClearGlobalOverride(t *testing.T) {
+	SetGlobalOverride("env", "production")
+	SetGlobalOverride("env", "canary")
+	SetGlobalOverride("zone", "a")
+	defer ClearGlobalOverride("zone")
+
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false)).WithContext("env", "dev")
+	l.Info("before")
+	ClearGlobalOverride("env")
+	l.Info("after")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d: %s", len(lines), buf.String())
+	}
+	if !strings.Contains(lines[0], "env: canary") || !strings.Contains(lines[0], "zone: a") {
+		t.Errorf("Expected the latest override value, got: %s", lines[0])
+	}
+	if !strings.Contains(lines[1], "env: dev") || !strings.Contains(lines[1], "zone: a") {
+		t.Errorf("Expected only the cleared override removed, got: %s", lines[1])
+	}
+}
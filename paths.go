@@ -0,0 +1,143 @@
+package dy
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// pathConfig controls how source file paths are rendered in stack frames and caller info
+type pathConfig struct {
+	absolute   bool   // Keep raw build paths
+	moduleRoot string // Directory trimmed from paths, detected from build info when empty
+}
+
+// WithAbsolutePaths keeps the raw build paths in stack frames and full-path
+// caller info instead of trimming them to be module-relative
+func WithAbsolutePaths(enable bool) Option {
+	return func(l *Logger) {
+		l.stack.paths.absolute = enable
+	}
+}
+
+// WithModuleRoot sets the directory that source paths are made relative to.
+// By default the main module is detected from the binary's build info.
+func WithModuleRoot(dir string) Option {
+	return func(l *Logger) {
+		l.stack.paths.moduleRoot = filepath.ToSlash(filepath.Clean(dir))
+	}
+}
+
+// WithCallerFullPath reports the module-relative path of the caller's file
+// (e.g. internal/db/store.go) instead of just its base name
+func WithCallerFullPath(enable bool) Option {
+	return func(l *Logger) {
+		l.callerFullPath = enable
+	}
+}
+
+// mainModulePath returns the module path of the running binary, if known
+func mainModulePath() string {
+	if info := readBuildInfo(); info != nil {
+		return info.Main.Path
+	}
+	return ""
+}
+
+// packagePath returns the import path of the package a function belongs to
+func packagePath(function string) string {
+	// The package path ends at the first dot after the last slash,
+	// e.g. "github.com/acme/svc/db.(*Store).Get" -> "github.com/acme/svc/db"
+	lastSlash := strings.LastIndex(function, "/")
+	dot := strings.Index(function[lastSlash+1:], ".")
+	if dot < 0 {
+		return function
+	}
+	return function[:lastSlash+1+dot]
+}
+
+// trim shortens an absolute source path to be module-relative. The function
+// name is used to recover the package path when the file lives outside any
+// recognisable module directory.
+func (c pathConfig) trim(file, function string) string {
+	if c.absolute || file == "" {
+		return file
+	}
+
+	file = filepath.ToSlash(file)
+
+	// An explicitly configured root wins
+	if c.moduleRoot != "" && strings.HasPrefix(file, c.moduleRoot+"/") {
+		return strings.TrimPrefix(file, c.moduleRoot+"/")
+	}
+
+	// GOPATH-style builds keep the module path in the file path
+	module := mainModulePath()
+	if module != "" {
+		if i := strings.Index(file, "/"+module+"/"); i >= 0 {
+			return file[i+len(module)+2:]
+		}
+	}
+
+	// Otherwise rebuild the path from the function's package
+	pkg := packagePath(function)
+	base := filepath.Base(file)
+	switch {
+	case module != "" && pkg == module:
+		return base
+	case module != "" && strings.HasPrefix(pkg, module+"/"):
+		return strings.TrimPrefix(pkg, module+"/") + "/" + base
+	case strings.Contains(pkg, "/"):
+		return pkg + "/" + base
+	}
+
+	return file
+}
+
+// WithCallerSkip creates a new logger that reports the caller n frames further
+// up the stack, for helpers that wrap a log call:
+//
+//	func logAndCount(log *dy.Logger, msg string) {
+//		count++
+//		log.WithCallerSkip(1).Info(msg)
+//	}
+func (l *Logger) WithCallerSkip(n int) *Logger {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	child := l.newChild()
+	child.context = l.context.Clone()
+	child.callerSkip += n
+	return child
+}
+
+// WithCallSite creates a new logger that reports file and line as the caller
+// of every entry, e.g. when replaying entries recorded elsewhere. The file is
+// rendered according to the caller path options.
+func (l *Logger) WithCallSite(file string, line int) *Logger {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	child := l.newChild()
+	child.context = l.context.Clone()
+	child.callSite = &CallerInfo{Function: "unknown", File: file, Line: line}
+	return child
+}
+
+// formatCallSite renders an explicit call site like a captured caller
+func (l *Logger) formatCallSite(site *CallerInfo) *CallerInfo {
+	caller := *site
+	if paths := l.callerPaths(); paths != nil {
+		caller.File = paths.trim(site.File, site.Function)
+	} else {
+		caller.File = filepath.Base(site.File)
+	}
+	return &caller
+}
@@ -0,0 +1,154 @@
+package dy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestPackagePath(t *testing.T) {
+	tests := []struct {
+		function string
+		expected string
+	}{
+		{"github.com/acme/svc/internal/db.(*Store).Get", "github.com/acme/svc/internal/db"},
+		{"github.com/acme/svc.Run.func1", "github.com/acme/svc"},
+		{"main.main", "main"},
+	}
+
+	for _, test := range tests {
+		if got := packagePath(test.function); got != test.expected {
+			t.Errorf("packagePath(%q) = %q, want %q", test.function, got, test.expected)
+		}
+	}
+}
+
+func TestPathTrim(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   pathConfig
+		file     string
+		function string
+		expected string
+	}{
+		{
+			name:     "configured root",
+			config:   pathConfig{moduleRoot: "/home/ci/build/svc"},
+			file:     "/home/ci/build/svc/internal/db/store.go",
+			function: "main.run",
+			expected: "internal/db/store.go",
+		},
+		{
+			name:     "package of another module",
+			file:     "/home/ci/go/pkg/mod/github.com/lib/pq@v1.10.0/conn.go",
+			function: "github.com/lib/pq.(*conn).query",
+			expected: "github.com/lib/pq/conn.go",
+		},
+		{
+			name:     "absolute paths kept",
+			config:   pathConfig{absolute: true},
+			file:     "/home/ci/build/svc/internal/db/store.go",
+			function: "github.com/acme/svc/internal/db.(*Store).Get",
+			expected: "/home/ci/build/svc/internal/db/store.go",
+		},
+	}
+
+	for _, test := range tests {
+		if got := test.config.trim(test.file, test.function); got != test.expected {
+			t.Errorf("%s: trim(%q) = %q, want %q", test.name, test.file, got, test.expected)
+		}
+	}
+}
+
+func TestStackFramesModuleRelative(t *testing.T) {
+	l := New(WithOutput(&bytes.Buffer{}))
+
+	stack := errorStack(t, l.WithError(errors.New("boom")))
+	if len(stack) == 0 {
+		t.Fatalf("Expected at least one stack frame")
+	}
+	if stack[0].File != "paths_test.go" {
+		t.Errorf("Expected module-relative file, got: %s", stack[0].File)
+	}
+
+	l = New(WithOutput(&bytes.Buffer{}), WithAbsolutePaths(true))
+	stack = errorStack(t, l.WithError(errors.New("boom")))
+	if !strings.HasSuffix(stack[0].File, "/paths_test.go") || !strings.HasPrefix(stack[0].File, "/") {
+		t.Errorf("Expected absolute file path, got: %s", stack[0].File)
+	}
+}
+
+func TestCallerFullPath(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(
+		WithOutput(&buf),
+		WithJSONFormat(true),
+		WithCallerInfo(true),
+		WithCallerFullPath(true),
+		WithModuleRoot("/nonexistent"),
+	)
+
+	l.Info("hello")
+
+	var entry LogEntry
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v", err)
+	}
+	if entry.Caller == nil || entry.Caller.File != "paths_test.go" {
+		t.Errorf("Expected module-relative caller file, got: %+v", entry.Caller)
+	}
+}
+
+// logThroughHelper wraps a log call in one helper layer
+func logThroughHelper(l *Logger) {
+	l.WithCallerSkip(1).Info("through one helper")
+}
+
+// logThroughTwoHelpers wraps a log call in two helper layers
+func logThroughTwoHelpers(l *Logger) {
+	logThroughTwoHelpersInner(l)
+}
+
+func logThroughTwoHelpersInner(l *Logger) {
+	l.WithCallerSkip(2).Info("through two helpers")
+}
+
+func TestWithCallerSkip(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithJSONFormat(true), WithCallerInfo(true))
+
+	_, _, line, _ := runtime.Caller(0)
+	logThroughHelper(l)
+	logThroughTwoHelpers(l)
+
+	d := NewEntryDecoder(&buf)
+	for i, want := range []int{line + 1, line + 2} {
+		entry, err := d.Next()
+		if err != nil {
+			t.Fatalf("Next returned error: %v", err)
+		}
+		if entry.Caller == nil || entry.Caller.File != "paths_test.go" || entry.Caller.Line != want {
+			t.Errorf("Entry %d caller = %+v, want paths_test.go:%d", i, entry.Caller, want)
+		}
+	}
+}
+
+func TestWithCallSite(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false), WithCallerInfo(true))
+
+	l.WithCallSite("/src/app/internal/db/store.go", 42).Info("replayed")
+	if !strings.Contains(buf.String(), "[store.go:42 unknown]") {
+		t.Errorf("Expected explicit call site in text output, got: %s", buf.String())
+	}
+
+	buf.Reset()
+	l = New(WithOutput(&buf), WithJSONFormat(true), WithCallerInfo(true), WithCallerFullPath(true), WithModuleRoot("/src/app"))
+	l.WithCallSite("/src/app/internal/db/store.go", 42).Info("replayed")
+	if !strings.Contains(buf.String(), `"file":"internal/db/store.go","line":42`) {
+		t.Errorf("Expected call site to honour caller path options, got: %s", buf.String())
+	}
+}
@@ -0,0 +1,89 @@
+package dy
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// patternRule suppresses or demotes entries at or below level whose message,
+// or context field key when set, matches re
+type patternRule struct {
+	level  Level
+	key    string
+	re     *regexp.Regexp
+	demote bool
+}
+
+// WithSuppressPattern drops entries at or below level whose formatted
+// message matches re, for noisy messages from dependencies that cannot be
+// changed. The option can be repeated; see SuppressedCount.
+func WithSuppressPattern(level Level, re *regexp.Regexp) Option {
+	return withPatternRule(patternRule{level: level, re: re})
+}
+
+// WithSuppressFieldPattern is like WithSuppressPattern but matches re
+// against the value of the context field key, as rendered in text output.
+// Entries without the field are kept.
+func WithSuppressFieldPattern(level Level, key string, re *regexp.Regexp) Option {
+	return withPatternRule(patternRule{level: level, key: key, re: re})
+}
+
+// WithDemotePattern is like WithSuppressPattern but logs matching entries at
+// DebugLevel instead of dropping them, so they are still written when the
+// logger's level allows
+func WithDemotePattern(level Level, re *regexp.Regexp) Option {
+	return withPatternRule(patternRule{level: level, re: re, demote: true})
+}
+
+// withPatternRule adds rule to the logger's pattern rules
+func withPatternRule(rule patternRule) Option {
+	return func(l *Logger) {
+		if rule.re == nil {
+			return
+		}
+		l.patterns = append(l.patterns, rule)
+	}
+}
+
+// SuppressedCount returns the number of entries suppressed or demoted by
+// pattern rules. The count is shared with child loggers.
+func (l *Logger) SuppressedCount() uint64 {
+	if l == nil {
+		return 0
+	}
+
+	return l.suppressed.Load()
+}
+
+// applyPatterns checks entry against the pattern rules and returns the level
+// to log it at, reporting false when it is suppressed. A demoted entry gets
+// DebugLevel.
+func (l *Logger) applyPatterns(level Level, entry *LogEntry, rules []patternRule) (Level, bool) {
+	for _, rule := range rules {
+		if level.Severity() > rule.level.Severity() || !rule.matches(entry) {
+			continue
+		}
+		l.suppressed.Add(1)
+		if !rule.demote {
+			return level, false
+		}
+		entry.Level = DebugLevel.String()
+		return DebugLevel, true
+	}
+	return level, true
+}
+
+// matches reports whether the rule's pattern matches entry
+func (rule patternRule) matches(entry *LogEntry) bool {
+	if rule.key == "" {
+		return rule.re.MatchString(entry.Message)
+	}
+	v, ok := entry.Context[rule.key]
+	if !ok {
+		return false
+	}
+	if s, ok := v.(string); ok {
+		return rule.re.MatchString(s)
+	}
+	return rule.re.MatchString(fmt.Sprint(v))
+}
@@ -0,0 +1,81 @@
+package dy
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestWithSuppressPattern(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false),
+		WithSuppressPattern(InfoLevel, regexp.MustCompile(`context canceled`)),
+		WithSuppressPattern(WarnLevel, regexp.MustCompile(`^retrying`)),
+	)
+
+	l.Info("request failed: context canceled")
+	l.Error("request failed: context canceled") // above the rule's level
+	l.Warn("retrying in 1s")
+	l.WithContext("id", 1).Info("retrying in 2s")
+	l.Info("request done")
+
+	output := buf.String()
+	if strings.Contains(output, "retrying") || strings.Count(output, "context canceled") != 1 {
+		t.Errorf("Expected the matching entries at or below the rule levels dropped, got: %s", output)
+	}
+	if !strings.Contains(output, "[ERROR") || !strings.Contains(output, "request done") {
+		t.Errorf("Expected the other entries kept, got: %s", output)
+	}
+	if got := l.WithContext("child", true).SuppressedCount(); got != 3 {
+		t.Errorf("Expected 3 suppressed entries shared with children, got %d", got)
+	}
+}
+
+func TestWithSuppressFieldPattern(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false),
+		WithSuppressFieldPattern(InfoLevel, "path", regexp.MustCompile(`^/healthz`)),
+		WithSuppressFieldPattern(InfoLevel, "status", regexp.MustCompile(`^2\d\d$`)),
+	)
+
+	l.WithContext("path", "/healthz/live").Info("request")
+	l.WithContext("path", "/api/users").Info("request users")
+	l.WithContext("status", 204).Info("status no content")
+	l.WithContext("status", 500).Info("status failed")
+	l.Info("no fields")
+
+	output := buf.String()
+	for _, want := range []string{"request users", "status failed", "no fields"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output, got: %s", want, output)
+		}
+	}
+	if strings.Contains(output, "healthz") || strings.Contains(output, "no content") {
+		t.Errorf("Expected entries with matching fields dropped, got: %s", output)
+	}
+	if got := l.SuppressedCount(); got != 2 {
+		t.Errorf("Expected 2 suppressed entries, got %d", got)
+	}
+}
+
+func TestWithDemotePattern(t *testing.T) {
+	re := regexp.MustCompile(`context canceled`)
+
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false), WithDemotePattern(WarnLevel, re))
+	l.Warn("stream closed: context canceled")
+	if buf.Len() != 0 {
+		t.Errorf("Expected the demoted entry below InfoLevel dropped, got: %s", buf.String())
+	}
+
+	var debug bytes.Buffer
+	d := New(WithOutput(&debug), WithTimestamp(false), WithLevel(DebugLevel), WithJSONFormat(true), WithDemotePattern(WarnLevel, re))
+	d.Warn("stream closed: context canceled")
+	if !strings.Contains(debug.String(), `"level":"DEBUG"`) {
+		t.Errorf("Expected the entry demoted to DEBUG, got: %s", debug.String())
+	}
+	if got := d.SuppressedCount(); got != 1 {
+		t.Errorf("Expected 1 demoted entry, got %d", got)
+	}
+}
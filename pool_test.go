@@ -0,0 +1,59 @@
+package dy
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestWithContextPool(t *testing.T) {
+	var buf syncBuffer
+	pool := NewContextFieldPool(8)
+	base := New(WithOutput(&buf), WithTimestamp(false)).WithContextPool(pool)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			l := base.WithFields(map[string]interface{}{"worker": i, "job": "sync"})
+			for j := 0; j < 50; j++ {
+				l.Info("working")
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 400 {
+		t.Fatalf("Expected 400 entries, got %d", len(lines))
+	}
+	for _, line := range lines {
+		if !strings.Contains(line, "job: sync") || !strings.Contains(line, "worker: ") {
+			t.Fatalf("Expected every entry to keep its own fields, got: %s", line)
+		}
+	}
+}
+
+func TestContextFieldPoolPut(t *testing.T) {
+	pool := NewContextFieldPool(2)
+
+	fields := append(pool.get(), ContextField{Key: "a", Value: &bytes.Buffer{}})
+	pool.put(fields)
+	if fields[:1][0].Value != nil {
+		t.Error("Expected returned slices to drop their values")
+	}
+
+	// Oversized slices are not kept
+	pool.put(make([]ContextField, 0, 16))
+	if got := pool.get(); cap(got) > 2 {
+		t.Errorf("Expected oversized slice to be dropped, got cap %d", cap(got))
+	}
+
+	var nilPool *ContextFieldPool
+	if nilPool.get() != nil {
+		t.Error("Expected nil pool to return nil")
+	}
+	nilPool.put(fields)
+}
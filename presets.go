@@ -0,0 +1,97 @@
+package dy
+
+import "time"
+
+// NewDevelopment creates a logger suited to local development: colored text
+// output at DebugLevel with caller info, local timestamps, multi-line error
+// details and development mode (see DPanic) enabled. The given options are
+// applied last, so any of these settings can be overridden.
+func NewDevelopment(options ...Option) *Logger {
+	defaults := []Option{
+		WithLevel(DebugLevel),
+		WithColor(true),
+		WithCallerInfo(true),
+		WithTimestamp(true),
+		WithDevelopment(true),
+	}
+	return New(append(defaults, options...)...)
+}
+
+// NewProduction creates a logger suited to production: JSON output at
+// InfoLevel with UTC RFC3339 timestamps, error stack traces only on entries at
+// ErrorLevel or above, and sampling of repeated entries (the first 100 per
+// second, then every 100th). The given options are applied last, so any of
+// these settings can be overridden.
+func NewProduction(options ...Option) *Logger {
+	defaults := []Option{
+		WithLevel(InfoLevel),
+		WithJSONFormat(true),
+		WithColor(false),
+		WithTimestamp(true),
+		WithTimeFormat(time.RFC3339),
+		WithUTC(true),
+		WithErrorStackLevel(ErrorLevel),
+		WithSampling(100, 100, time.Second),
+	}
+	return New(append(defaults, options...)...)
+}
+
+// NewFileRotateByHour creates a logger writing to hourly files named
+// "<basePath>-2006-01-02-15.log", keeping the files of the last 24 hours
+// gzip-compressed. The given options are applied after the output is set.
+//
+// Example usage:
+//
+//	log, err := dy.NewFileRotateByHour("logs/app")
+//	if err != nil {
+//	    return err
+//	}
+//	defer log.Close()
+func NewFileRotateByHour(basePath string, opts ...Option) (*Logger, error) {
+	return newFileRotateByPeriod(basePath, WithRotateByDate("2006-01-02-15"), 24, opts)
+}
+
+// NewFileRotateByDay creates a logger writing to daily files named
+// "<basePath>-2006-01-02.log", keeping the files of the last 7 days
+// gzip-compressed. The given options are applied after the output is set.
+//
+// Example usage:
+//
+//	log, err := dy.NewFileRotateByDay("logs/app")
+//	if err != nil {
+//	    return err
+//	}
+//	defer log.Close()
+func NewFileRotateByDay(basePath string, opts ...Option) (*Logger, error) {
+	return newFileRotateByPeriod(basePath, WithRotateByDate("2006-01-02"), 7, opts)
+}
+
+// NewFileRotateByWeek creates a logger writing to weekly files named after
+// the ISO week, "<basePath>-2024-W01.log", keeping the files of the last 4
+// weeks gzip-compressed. The given options are applied after the output is
+// set.
+//
+// Example usage:
+//
+//	log, err := dy.NewFileRotateByWeek("logs/app")
+//	if err != nil {
+//	    return err
+//	}
+//	defer log.Close()
+func NewFileRotateByWeek(basePath string, opts ...Option) (*Logger, error) {
+	return newFileRotateByPeriod(basePath, withRotateByWeek(), 4, opts)
+}
+
+// newFileRotateByPeriod creates a logger writing to dated files
+func newFileRotateByPeriod(basePath string, period RotateOption, backups int, opts []Option) (*Logger, error) {
+	rw, err := NewRotateWriter(basePath+".log", period, WithMaxBackups(backups), WithCompress(true))
+	if err != nil {
+		return nil, err
+	}
+
+	output := func(l *Logger) {
+		l.out = rw
+		l.closer = rw.Close
+	}
+	return NewE(append([]Option{output}, opts...)...)
+}
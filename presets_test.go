@@ -0,0 +1,105 @@
+package dy
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+)
+
+// normalizeOutput replaces values that change between runs so output can be
+// compared against a golden file
+func normalizeOutput(s string) string {
+	replacements := []struct {
+		pattern *regexp.Regexp
+		repl    string
+	}{
+		{regexp.MustCompile(`\d{4}-\d{2}-\d{2}[ T]\d{2}:\d{2}:\d{2}(\.\d+)?Z?`), "<timestamp>"},
+		{regexp.MustCompile(`\.go:\d+`), ".go:<line>"},
+		{regexp.MustCompile(`"line":\d+`), `"line":<line>`},
+	}
+	for _, r := range replacements {
+		s = r.pattern.ReplaceAllString(s, r.repl)
+	}
+	return s
+}
+
+var updateGolden = flag.Bool("update", false, "update golden files in testdata")
+
+// checkGolden compares output with testdata/<name>.golden
+func checkGolden(t *testing.T, name, output string) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if *updateGolden {
+		if err := os.WriteFile(path, []byte(normalizeOutput(output)), 0644); err != nil {
+			t.Fatalf("Failed to update golden file: %v", err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read golden file: %v", err)
+	}
+	if got := normalizeOutput(output); got != string(want) {
+		t.Errorf("Output does not match %s\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+func TestNewDevelopment(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewDevelopment(WithOutput(&buf))
+
+	l.Debug("cache warmed")
+	l.WithContext("user_id", 42).Info("user signed in")
+	l.WithError(errors.New("connection refused")).Error("request failed")
+
+	checkGolden(t, "development", buf.String())
+}
+
+func TestNewProduction(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewProduction(WithOutput(&buf), WithCallerInfo(false))
+
+	l.Debug("cache warmed")
+	l.WithContext("user_id", 42).Info("user signed in")
+	withErr := l.WithError(errors.New("connection refused"))
+	withErr.Warn("retrying")
+	withErr.Error("request failed")
+
+	checkGolden(t, "production", buf.String())
+}
+
+func TestProductionTimestampsAreUTC(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewProduction(WithOutput(&buf))
+	l.Info("hello")
+
+	ts := regexp.MustCompile(`"timestamp":"([^"]+)"`).FindStringSubmatch(buf.String())
+	if ts == nil {
+		t.Fatalf("Expected timestamp in output, got: %s", buf.String())
+	}
+	parsed, err := time.Parse(time.RFC3339, ts[1])
+	if err != nil {
+		t.Fatalf("Expected RFC3339 timestamp, got %q: %v", ts[1], err)
+	}
+	if _, offset := parsed.Zone(); offset != 0 {
+		t.Errorf("Expected UTC timestamp, got %q", ts[1])
+	}
+}
+
+func TestProductionSampling(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewProduction(WithOutput(&buf), WithSampling(2, 3, time.Minute))
+
+	for i := 0; i < 10; i++ {
+		l.Info("repeated %d", i)
+	}
+
+	// First 2, then every 3rd after that: entries 1, 2, 5, 8
+	if got := bytes.Count(buf.Bytes(), []byte("\n")); got != 4 {
+		t.Errorf("Expected 4 sampled entries, got %d:\n%s", got, buf.String())
+	}
+}
@@ -0,0 +1,10 @@
+module github.com/zakirkun/dy/protody
+
+go 1.23.1
+
+require (
+	github.com/zakirkun/dy v0.0.0-00010101000000-000000000000
+	google.golang.org/protobuf v1.36.6
+)
+
+replace github.com/zakirkun/dy => ../
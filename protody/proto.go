@@ -0,0 +1,83 @@
+// Package protody adds protobuf messages to dy loggers. It is kept out of the
+// dy package so that only programs using protobuf depend on it.
+package protody
+
+import (
+	"github.com/zakirkun/dy"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// WithMessage creates a new logger with the populated fields of a
+// protobuf message as context fields, keyed by their proto field names.
+// Scalars keep their Go types, enums use their value names, and nested
+// messages become maps down to maxDepth levels; deeper messages are replaced
+// by their type name. For a oneof, only the active case is added, under its
+// own field name.
+func WithMessage(l *dy.Logger, msg proto.Message, maxDepth int) *dy.Logger {
+	if msg == nil {
+		return l
+	}
+
+	fields := make(map[string]interface{})
+	msg.ProtoReflect().Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		fields[string(fd.Name())] = protoFieldValue(fd, v, maxDepth)
+		return true
+	})
+
+	if len(fields) == 0 {
+		return l
+	}
+	return l.WithFields(fields)
+}
+
+// protoMessageValue converts a message to a map of its populated fields
+func protoMessageValue(m protoreflect.Message, depth int) interface{} {
+	if depth <= 0 {
+		return "<" + string(m.Descriptor().FullName()) + ">"
+	}
+
+	fields := make(map[string]interface{})
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		fields[string(fd.Name())] = protoFieldValue(fd, v, depth-1)
+		return true
+	})
+	return fields
+}
+
+// protoFieldValue converts a field value, including lists and maps, to a plain Go value
+func protoFieldValue(fd protoreflect.FieldDescriptor, v protoreflect.Value, depth int) interface{} {
+	switch {
+	case fd.IsList():
+		list := v.List()
+		values := make([]interface{}, list.Len())
+		for i := range values {
+			values[i] = protoScalarValue(fd, list.Get(i), depth)
+		}
+		return values
+	case fd.IsMap():
+		values := make(map[string]interface{})
+		v.Map().Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+			values[k.String()] = protoScalarValue(fd.MapValue(), mv, depth)
+			return true
+		})
+		return values
+	default:
+		return protoScalarValue(fd, v, depth)
+	}
+}
+
+// protoScalarValue converts a single (non-repeated) value to a plain Go value
+func protoScalarValue(fd protoreflect.FieldDescriptor, v protoreflect.Value, depth int) interface{} {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return protoMessageValue(v.Message(), depth)
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name())
+		}
+		return int32(v.Enum())
+	default:
+		return v.Interface()
+	}
+}
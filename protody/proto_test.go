@@ -0,0 +1,66 @@
+package protody
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/zakirkun/dy"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestWithMessage(t *testing.T) {
+	var buf bytes.Buffer
+	l := dy.New(dy.WithOutput(&buf), dy.WithTimestamp(false), dy.WithJSONFormat(true))
+
+	msg, err := structpb.NewStruct(map[string]interface{}{
+		"name": "alice",
+		"address": map[string]interface{}{
+			"city": "Berlin",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to build message: %v", err)
+	}
+
+	WithMessage(l, msg, 3).Info("request")
+
+	var entry struct {
+		Context map[string]interface{} `json:"context"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v", err)
+	}
+
+	// Struct.fields is a map of Value messages whose oneof "kind" holds the data
+	fields, ok := entry.Context["fields"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Expected fields map in context, got: %v", entry.Context)
+	}
+	name, _ := fields["name"].(map[string]interface{})
+	if name["string_value"] != "alice" {
+		t.Errorf("Expected oneof case name as key, got: %v", fields["name"])
+	}
+	address, _ := fields["address"].(map[string]interface{})
+	if _, ok := address["struct_value"].(map[string]interface{}); !ok {
+		t.Errorf("Expected nested message as map, got: %v", fields["address"])
+	}
+}
+
+func TestWithMessageMaxDepth(t *testing.T) {
+	l := dy.New(dy.WithOutput(&bytes.Buffer{}))
+
+	msg := structpb.NewStringValue("alice")
+	child := WithMessage(l, msg, 0)
+	if got, _ := child.ContextValue("string_value"); got != "alice" {
+		t.Errorf("Expected top-level scalar field, got: %v", got)
+	}
+
+	nested, _ := structpb.NewStruct(map[string]interface{}{"name": "alice"})
+	child = WithMessage(l, nested, 0)
+	value, _ := child.ContextValue("fields")
+	fields, _ := value.(map[string]interface{})
+	if fields["name"] != "<google.protobuf.Value>" {
+		t.Errorf("Expected message beyond maxDepth to be replaced by its type, got: %v", fields["name"])
+	}
+}
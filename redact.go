@@ -0,0 +1,287 @@
+package dy
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// defaultRedactionDepth is the nesting depth walked by deep redaction unless
+// WithDeepRedaction sets another
+const defaultRedactionDepth = 10
+
+// Placeholders written by deep redaction in place of values it cannot walk
+const (
+	maxDepthValue = "[MAX DEPTH]"
+	cycleValue    = "[CYCLE]"
+)
+
+// redactConfig controls which field values are redacted
+type redactConfig struct {
+	keys     map[string]bool // Lowercased keys whose values are redacted
+	deep     bool            // Walk nested maps, slices and structs
+	maxDepth int             // Nesting depth walked in deep mode
+}
+
+// WithRedactKeys sets the context field and error attribute keys whose values
+// are replaced by "[REDACTED]". Keys match case-insensitively. By default only
+// top-level keys are checked, see WithDeepRedaction.
+func WithRedactKeys(keys ...string) Option {
+	return func(l *Logger) {
+		l.redaction.keys = make(map[string]bool, len(keys))
+		for _, key := range keys {
+			l.redaction.keys[strings.ToLower(key)] = true
+		}
+	}
+}
+
+// WithDeepRedaction makes redaction walk into maps, slices, arrays, pointers
+// and exported struct fields, down to maxDepth levels (10 if maxDepth is not
+// positive). Map keys and struct field names, or their JSON names, are matched
+// against the WithRedactKeys list. Values containing a redacted key are logged
+// as a redacted copy, with structs rendered as maps, and the caller's data is
+// never modified. Values nested deeper than maxDepth are written as
+// "[MAX DEPTH]" and reference cycles as "[CYCLE]".
+func WithDeepRedaction(maxDepth int) Option {
+	return func(l *Logger) {
+		if maxDepth <= 0 {
+			maxDepth = defaultRedactionDepth
+		}
+		l.redaction.deep = true
+		l.redaction.maxDepth = maxDepth
+	}
+}
+
+// enabled reports whether any key is redacted
+func (c redactConfig) enabled() bool {
+	return len(c.keys) > 0
+}
+
+// redacts reports whether the value under key is redacted
+func (c redactConfig) redacts(key string) bool {
+	return c.keys[strings.ToLower(key)]
+}
+
+// redactFields returns fields with sensitive values redacted. fields is
+// returned unchanged when nothing is redacted.
+func (c redactConfig) redactFields(fields []ContextField) []ContextField {
+	var result []ContextField
+	for i, field := range fields {
+		value, changed := c.redactField(field.Key, field.Value)
+		if !changed {
+			continue
+		}
+		if result == nil {
+			result = make([]ContextField, len(fields))
+			copy(result, fields)
+		}
+		result[i].Value = value
+	}
+
+	if result == nil {
+		return fields
+	}
+	return result
+}
+
+// redactField redacts a single field value, reporting whether it changed
+func (c redactConfig) redactField(key string, v interface{}) (interface{}, bool) {
+	if c.redacts(key) {
+		return redactedValue, true
+	}
+	if data, ok := v.(ErrorData); ok {
+		return c.redactErrorData(data)
+	}
+	if !c.deep {
+		return v, false
+	}
+
+	r := redactor{config: c, visiting: make(map[uintptr]bool)}
+	return r.walk(reflect.ValueOf(v), 1)
+}
+
+// redactErrorData redacts the attributes of data and its causes
+func (c redactConfig) redactErrorData(data ErrorData) (ErrorData, bool) {
+	changed := false
+	if len(data.Attributes) > 0 {
+		attrs := make(map[string]interface{}, len(data.Attributes))
+		for k, v := range data.Attributes {
+			value, ok := c.redactField(k, v)
+			attrs[k] = value
+			changed = changed || ok
+		}
+		if changed {
+			data.Attributes = attrs
+		}
+	}
+	if data.Cause != nil {
+		if cause, ok := c.redactErrorData(*data.Cause); ok {
+			data.Cause = &cause
+			changed = true
+		}
+	}
+	return data, changed
+}
+
+// redactor walks a single value for deep redaction
+type redactor struct {
+	config   redactConfig
+	visiting map[uintptr]bool // Pointers on the current path, for cycle detection
+}
+
+// walk returns a redacted copy of v, or v itself and false when nothing below
+// it is redacted
+func (r *redactor) walk(v reflect.Value, depth int) (interface{}, bool) {
+	if !v.IsValid() {
+		return nil, false
+	}
+	original := v.Interface()
+	if leafValue(v) {
+		return original, false
+	}
+	if depth > r.config.maxDepth {
+		return maxDepthValue, true
+	}
+
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return original, false
+		}
+		return r.walk(v.Elem(), depth)
+	case reflect.Ptr, reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			return original, false
+		}
+		ptr := v.Pointer()
+		if r.visiting[ptr] {
+			return cycleValue, true
+		}
+		r.visiting[ptr] = true
+		defer delete(r.visiting, ptr)
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		value, changed := r.walk(v.Elem(), depth)
+		if !changed {
+			return original, false
+		}
+		return value, true
+	case reflect.Map:
+		return r.walkMap(v, depth)
+	case reflect.Slice, reflect.Array:
+		return r.walkSlice(v, depth)
+	case reflect.Struct:
+		return r.walkStruct(v, depth)
+	}
+	return original, false
+}
+
+// walkMap redacts the entries of a map, rendering a changed map with string keys
+func (r *redactor) walkMap(v reflect.Value, depth int) (interface{}, bool) {
+	result := make(map[string]interface{}, v.Len())
+	changed := false
+	iter := v.MapRange()
+	for iter.Next() {
+		key := fmt.Sprint(iter.Key().Interface())
+		if r.config.redacts(key) {
+			result[key] = redactedValue
+			changed = true
+			continue
+		}
+		value, ok := r.walk(iter.Value(), depth+1)
+		result[key] = value
+		changed = changed || ok
+	}
+
+	if !changed {
+		return v.Interface(), false
+	}
+	return result, true
+}
+
+// walkSlice redacts the elements of a slice or array
+func (r *redactor) walkSlice(v reflect.Value, depth int) (interface{}, bool) {
+	result := make([]interface{}, v.Len())
+	changed := false
+	for i := range result {
+		value, ok := r.walk(v.Index(i), depth+1)
+		result[i] = value
+		changed = changed || ok
+	}
+
+	if !changed {
+		return v.Interface(), false
+	}
+	return result, true
+}
+
+// walkStruct redacts the exported fields of a struct, rendering a changed
+// struct as a map keyed by the fields' JSON names
+func (r *redactor) walkStruct(v reflect.Value, depth int) (interface{}, bool) {
+	t := v.Type()
+	result := make(map[string]interface{}, t.NumField())
+	changed := false
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, ok := jsonFieldName(field)
+		if !ok {
+			continue
+		}
+
+		if r.config.redacts(name) || r.config.redacts(field.Name) {
+			result[name] = redactedValue
+			changed = true
+			continue
+		}
+		value, ok := r.walk(v.Field(i), depth+1)
+		result[name] = value
+		changed = changed || ok
+	}
+
+	if !changed {
+		return v.Interface(), false
+	}
+	return result, true
+}
+
+// jsonFieldName returns the name a struct field is encoded under in JSON, and
+// false for fields left out of the encoding
+func jsonFieldName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() {
+		return "", false
+	}
+	switch tag := strings.Split(field.Tag.Get("json"), ",")[0]; tag {
+	case "-":
+		return "", false
+	case "":
+		return field.Name, true
+	default:
+		return tag, true
+	}
+}
+
+// leafValue reports whether v is rendered as a whole rather than walked:
+// scalars and types with their own JSON or text encoding
+func leafValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Interface, reflect.Ptr, reflect.Map, reflect.Slice, reflect.Array, reflect.Struct:
+	default:
+		return true
+	}
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+		return true
+	}
+	if v.Kind() == reflect.Interface {
+		return false
+	}
+
+	switch v.Interface().(type) {
+	case json.Marshaler, encoding.TextMarshaler:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,121 @@
+package dy
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+type redactCredentials struct {
+	Password string
+	APIKey   string `json:"api_key"`
+}
+
+type redactRequest struct {
+	User struct {
+		Name        string
+		Credentials *redactCredentials
+	}
+	Headers map[string]string
+	Items   []map[string]interface{}
+	secret  string
+}
+
+func TestWithRedactKeys(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false), WithRedactKeys("Password"))
+
+	l.WithFields(map[string]interface{}{"password": "hunter2"}).
+		WithError(NewError("login failed", "AUTH", map[string]interface{}{"PASSWORD": "hunter2"})).
+		Error("login")
+
+	if output := buf.String(); strings.Contains(output, "hunter2") || !strings.Contains(output, "password: [REDACTED]") {
+		t.Errorf("Expected password to be redacted, got: %s", output)
+	}
+}
+
+func TestWithRedactKeysShallow(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false), WithRedactKeys("password"))
+
+	l.WithContext("req", map[string]string{"password": "hunter2"}).Info("login")
+
+	if !strings.Contains(buf.String(), "hunter2") {
+		t.Errorf("Expected nested values to be left alone without deep redaction, got: %s", buf.String())
+	}
+}
+
+func TestWithDeepRedaction(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithJSONFormat(true), WithTimestamp(false),
+		WithRedactKeys("password", "api_key", "authorization", "card"), WithDeepRedaction(0))
+
+	req := redactRequest{
+		Headers: map[string]string{"Authorization": "Bearer abc", "Accept": "json"},
+		Items:   []map[string]interface{}{{"card": "4111", "sku": "X1"}},
+		secret:  "hidden",
+	}
+	req.User.Name = "alice"
+	req.User.Credentials = &redactCredentials{Password: "hunter2", APIKey: "k-123"}
+	l.WithContext("req", req).Info("request")
+
+	output := buf.String()
+	for _, leaked := range []string{"hunter2", "k-123", "Bearer abc", "4111", "hidden"} {
+		if strings.Contains(output, leaked) {
+			t.Errorf("Expected %q to be redacted, got: %s", leaked, output)
+		}
+	}
+	for _, kept := range []string{`"Name":"alice"`, `"Accept":"json"`, `"sku":"X1"`, `"api_key":"[REDACTED]"`} {
+		if !strings.Contains(output, kept) {
+			t.Errorf("Expected %s in output, got: %s", kept, output)
+		}
+	}
+
+	// The caller's data is not modified
+	if req.User.Credentials.Password != "hunter2" || req.Headers["Authorization"] != "Bearer abc" {
+		t.Error("Expected deep redaction to leave the original value untouched")
+	}
+}
+
+func TestDeepRedactionUnchangedValue(t *testing.T) {
+	config := redactConfig{keys: map[string]bool{"password": true}, deep: true, maxDepth: defaultRedactionDepth}
+	fields := []ContextField{{Key: "req", Value: map[string]string{"user": "alice"}}}
+	if got := config.redactFields(fields); &got[0] != &fields[0] {
+		t.Error("Expected fields without sensitive values to be returned unchanged")
+	}
+}
+
+func TestDeepRedactionCycle(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithJSONFormat(true), WithTimestamp(false),
+		WithRedactKeys("password"), WithDeepRedaction(0))
+
+	m := map[string]interface{}{"password": "hunter2"}
+	m["self"] = m
+	l.WithContext("req", m).Info("request")
+
+	var entry LogEntry
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to parse output %q: %v", buf.String(), err)
+	}
+	req := entry.Context["req"].(map[string]interface{})
+	if req["self"] != cycleValue || req["password"] != redactedValue {
+		t.Errorf("Unexpected redacted value: %v", req)
+	}
+}
+
+func TestDeepRedactionMaxDepth(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithJSONFormat(true), WithTimestamp(false),
+		WithRedactKeys("password"), WithDeepRedaction(2))
+
+	nested := map[string]interface{}{
+		"a": map[string]interface{}{"b": map[string]interface{}{"password": "hunter2"}},
+	}
+	l.WithContext("req", nested).Info("request")
+
+	if output := buf.String(); strings.Contains(output, "hunter2") || !strings.Contains(output, maxDepthValue) {
+		t.Errorf("Expected values below the maximum depth to be cut, got: %s", output)
+	}
+}
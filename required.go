@@ -0,0 +1,90 @@
+package dy
+
+import "slices"
+
+// requiredField is a context key every written entry must have
+type requiredField struct {
+	key    string
+	strict bool // Drop entries without the key
+}
+
+// WithRequiredFields creates a new logger requiring the given context keys,
+// e.g. "request_id" for audit trails. Before each written entry missing one
+// of them, a WARN entry "required log field missing: <key>" is logged for
+// every missing key. Child loggers inherit the requirement.
+func (l *Logger) WithRequiredFields(keys ...string) *Logger {
+	return l.withRequiredFields(keys, false)
+}
+
+// WithRequiredFieldsStrict is like WithRequiredFields but also drops the
+// entries missing a required key. Dropped entries are included in
+// FilteredCount.
+func (l *Logger) WithRequiredFieldsStrict(keys ...string) *Logger {
+	return l.withRequiredFields(keys, true)
+}
+
+// withRequiredFields creates a new logger requiring keys
+func (l *Logger) withRequiredFields(keys []string, strict bool) *Logger {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	child := l.newChild()
+	child.context = l.context.Clone()
+
+	// Requiring a key again updates its strictness
+	required := make([]requiredField, 0, len(l.required)+len(keys))
+	for _, field := range l.required {
+		if !slices.Contains(keys, field.key) {
+			required = append(required, field)
+		}
+	}
+	for _, key := range keys {
+		required = append(required, requiredField{key: key, strict: strict})
+	}
+	child.required = required
+	return child
+}
+
+// checkRequired logs a warning for every required key entry lacks and
+// reports whether entry may still be written
+func (l *Logger) checkRequired(entry *LogEntry, required []requiredField) bool {
+	keep := true
+	var warner *Logger
+	for _, field := range required {
+		if _, ok := entry.Context[field.key]; ok {
+			continue
+		}
+		if warner == nil {
+			warner = l.requiredWarner()
+		}
+		warning, fields := warner.buildEntry(WarnLevel, "required log field missing: "+field.key, 0)
+		warning.Caller = entry.Caller
+		warner.output(WarnLevel, warning, fields)
+		warner.pool.put(fields)
+
+		if field.strict {
+			keep = false
+		}
+	}
+	if !keep {
+		l.filtered.Add(1)
+	}
+	return keep
+}
+
+// requiredWarner returns a logger for the missing field warnings, without
+// requirements of its own and reporting the caller of the checked entry
+func (l *Logger) requiredWarner() *Logger {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	warner := l.newChild()
+	warner.context = l.context.Clone()
+	warner.required = nil
+	warner.callerInfo = false
+	return warner
+}
@@ -0,0 +1,69 @@
+package dy
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWithRequiredFields(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false)).WithRequiredFields("request_id", "user_id")
+
+	l.WithContext("request_id", "r1").Info("partial")
+	l.WithFields(map[string]interface{}{"request_id": "r2", "user_id": "u2"}).Info("complete")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected a warning and 2 entries, got: %s", buf.String())
+	}
+	if !strings.Contains(lines[0], "[WARN") || !strings.Contains(lines[0], "required log field missing: user_id") {
+		t.Errorf("Expected the warning before the entry, got: %s", lines[0])
+	}
+	if !strings.Contains(lines[0], "request_id: r1") {
+		t.Errorf("Expected the warning to keep the logger's context, got: %s", lines[0])
+	}
+	if !strings.Contains(lines[1], "partial") || !strings.Contains(lines[2], "complete") {
+		t.Errorf("Expected both entries written, got: %s", buf.String())
+	}
+}
+
+func TestWithRequiredFieldsStrict(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false), WithCallerInfo(true)).WithRequiredFieldsStrict("request_id")
+
+	l.Info("dropped")
+	l.WithContext("request_id", "r1").Info("kept")
+	l.Debug("below the level") // not checked
+
+	output := buf.String()
+	if strings.Contains(output, "dropped") || !strings.Contains(output, "kept") {
+		t.Errorf("Expected only the entry with the field written, got: %s", output)
+	}
+	if strings.Count(output, "required log field missing: request_id") != 1 {
+		t.Errorf("Expected one warning, got: %s", output)
+	}
+	if !strings.Contains(output, "required_test.go") {
+		t.Errorf("Expected the warning to report the caller, got: %s", output)
+	}
+	if got := l.FilteredCount(); got != 1 {
+		t.Errorf("Expected 1 filtered entry, got %d", got)
+	}
+}
+
+func TestWithRequiredFieldsInherited(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false), WithJSONFormat(true)).WithRequiredFields("tenant")
+
+	// Requiring the key again as strict makes the child drop entries
+	l.WithContext("id", 1).WithRequiredFieldsStrict("tenant").Warn("strict child")
+	l.WithContext("id", 2).Info("lenient child")
+
+	output := buf.String()
+	if strings.Contains(output, "strict child") || !strings.Contains(output, "lenient child") {
+		t.Errorf("Expected only the lenient child's entry, got: %s", output)
+	}
+	if strings.Count(output, `"message":"required log field missing: tenant"`) != 2 {
+		t.Errorf("Expected a warning for each entry, got: %s", output)
+	}
+}
@@ -0,0 +1,82 @@
+package dy
+
+import "sync"
+
+// RingBuffer keeps the most recent log entries in memory so tests and live
+// dashboards can inspect them without parsing log files
+type RingBuffer struct {
+	mu      sync.RWMutex
+	entries []*LogEntry
+	next    int  // Index the next entry is written to
+	full    bool // Whether the buffer has wrapped around
+}
+
+// NewRingBuffer creates a ring buffer holding up to size entries
+func NewRingBuffer(size int) *RingBuffer {
+	if size < 1 {
+		size = 1
+	}
+	return &RingBuffer{
+		entries: make([]*LogEntry, size),
+	}
+}
+
+// add records an entry, overwriting the oldest one when the buffer is full
+func (rb *RingBuffer) add(entry *LogEntry) {
+	if rb == nil {
+		return
+	}
+
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	rb.entries[rb.next] = entry
+	rb.next = (rb.next + 1) % len(rb.entries)
+	if rb.next == 0 {
+		rb.full = true
+	}
+}
+
+// Entries returns a snapshot of the buffered entries, oldest first
+func (rb *RingBuffer) Entries() []*LogEntry {
+	rb.mu.RLock()
+	defer rb.mu.RUnlock()
+
+	if !rb.full {
+		return append([]*LogEntry(nil), rb.entries[:rb.next]...)
+	}
+
+	entries := make([]*LogEntry, 0, len(rb.entries))
+	entries = append(entries, rb.entries[rb.next:]...)
+	return append(entries, rb.entries[:rb.next]...)
+}
+
+// Last returns up to n of the most recent entries, oldest first
+func (rb *RingBuffer) Last(n int) []*LogEntry {
+	entries := rb.Entries()
+	if n < 0 {
+		n = 0
+	}
+	if n < len(entries) {
+		entries = entries[len(entries)-n:]
+	}
+	return entries
+}
+
+// WithInMemoryRingBuffer creates a new logger that records every entry in rb
+// after writing it to the normal output. Entries below the logger's level are
+// recorded too, even though they are not written.
+func (l *Logger) WithInMemoryRingBuffer(rb *RingBuffer) *Logger {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	child := l.newChild()
+	child.context = l.context.Clone()
+	child.ring = rb
+
+	return child
+}
@@ -0,0 +1,60 @@
+package dy
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRingBuffer(t *testing.T) {
+	rb := NewRingBuffer(3)
+	if got := rb.Entries(); len(got) != 0 {
+		t.Fatalf("Expected empty buffer, got %d entries", len(got))
+	}
+
+	for _, msg := range []string{"a", "b", "c", "d"} {
+		rb.add(&LogEntry{Message: msg})
+	}
+
+	entries := rb.Entries()
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(entries))
+	}
+	for i, want := range []string{"b", "c", "d"} {
+		if entries[i].Message != want {
+			t.Errorf("Entries()[%d] = %q, want %q", i, entries[i].Message, want)
+		}
+	}
+
+	last := rb.Last(2)
+	if len(last) != 2 || last[0].Message != "c" || last[1].Message != "d" {
+		t.Errorf("Expected last 2 entries to be c, d, got: %v", last)
+	}
+	if got := rb.Last(10); len(got) != 3 {
+		t.Errorf("Expected Last to cap at buffer size, got %d", len(got))
+	}
+}
+
+func TestWithInMemoryRingBuffer(t *testing.T) {
+	var buf bytes.Buffer
+	rb := NewRingBuffer(10)
+	l := New(WithOutput(&buf), WithTimestamp(false), WithLevel(InfoLevel)).WithInMemoryRingBuffer(rb)
+
+	l.Debug("hidden")
+	l.WithContext("user_id", 42).Info("visible")
+
+	if strings.Contains(buf.String(), "hidden") {
+		t.Errorf("Expected debug entry not to be written, got: %s", buf.String())
+	}
+
+	entries := rb.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 recorded entries, got %d", len(entries))
+	}
+	if entries[0].Level != "DEBUG" || entries[0].Message != "hidden" {
+		t.Errorf("Expected debug entry to be recorded, got: %+v", entries[0])
+	}
+	if entries[1].Context["user_id"] != 42 {
+		t.Errorf("Expected context in recorded entry, got: %+v", entries[1])
+	}
+}
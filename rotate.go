@@ -7,6 +7,8 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
@@ -23,6 +25,22 @@ type RotateWriter struct {
 	backupInterval time.Duration // Time interval for rotation regardless of size
 	lastRotate     time.Time     // Time of last rotation
 	compress       bool          // Whether to compress backup files
+	consoleText    bool          // Text console output in WithRotateWriterTee
+	period         *datePeriod   // Names files by date, see WithRotateByDate
+	base           string        // Filename the dated names derive from
+	current        string        // Date suffix of the current file
+	fileMode       os.FileMode   // Permissions of log files, 0 for the default
+	dirMode        os.FileMode   // Permissions of created directories, 0 for the default
+	lazyOpen       bool          // Open the file on the first Write
+	createDirs     bool          // Create a missing log directory
+
+	backupMu  sync.Mutex     // Serializes compression and cleanup of backups
+	pending   backgroundWork // Tracks backup processing still running
+	lastStamp string         // Timestamp of the latest backup name
+	lastSeq   int            // Counter of the latest backup name within lastStamp
+
+	failurePolicy WriteFailurePolicy // What to do when writing the file fails
+	stats         rotateCounters     // Counters reported by Stats
 }
 
 // RotateOption defines options for the RotateWriter
@@ -64,6 +82,7 @@ func NewRotateWriter(filename string, options ...RotateOption) (*RotateWriter, e
 		maxBackups:     5,                 // Default: keep 5 backup files
 		backupInterval: 24 * time.Hour,    // Default: rotate daily
 		compress:       true,              // Default: compress backups
+		createDirs:     true,              // Default: create the log directory
 		lastRotate:     time.Now(),
 	}
 
@@ -72,24 +91,97 @@ func NewRotateWriter(filename string, options ...RotateOption) (*RotateWriter, e
 		option(rw)
 	}
 
-	// Open or create the log file
-	if err := rw.openFile(); err != nil {
-		return nil, err
+	// Dated files are named after the current period
+	if rw.period != nil {
+		rw.base = filename
+		rw.current = rw.period.format(time.Now())
+		rw.filename = rw.datedName(rw.current)
+	}
+
+	// Open or create the log file now, so an unwritable path fails early
+	if !rw.lazyOpen {
+		if err := rw.openFile(); err != nil {
+			return nil, err
+		}
 	}
 
 	return rw, nil
 }
 
+// WithLazyOpen defers creating the log directory and opening the file until
+// the first Write, so a program that never logs leaves no empty file behind.
+// Errors opening the file are then returned by Write instead of
+// NewRotateWriter.
+func WithLazyOpen(lazy bool) RotateOption {
+	return func(rw *RotateWriter) {
+		rw.lazyOpen = lazy
+	}
+}
+
+// WithCreateDirs sets whether a missing log directory is created. When
+// disabled, opening the file fails unless the directory already exists.
+func WithCreateDirs(create bool) RotateOption {
+	return func(rw *RotateWriter) {
+		rw.createDirs = create
+	}
+}
+
+// Default permissions of log files and directories
+const (
+	defaultFileMode os.FileMode = 0644
+	defaultDirMode  os.FileMode = 0755
+)
+
+// WithFileMode sets the permissions of the log file and its backups. They are
+// applied when the file is created, and an existing file allowing more than
+// mode is restricted to it when opened. The default is 0644, subject to the
+// umask.
+func WithFileMode(mode os.FileMode) RotateOption {
+	return func(rw *RotateWriter) {
+		rw.fileMode = mode.Perm()
+	}
+}
+
+// WithDirMode sets the permissions of the log directory when the writer
+// creates it. Existing directories are left as they are. The default is 0755,
+// subject to the umask.
+func WithDirMode(mode os.FileMode) RotateOption {
+	return func(rw *RotateWriter) {
+		rw.dirMode = mode.Perm()
+	}
+}
+
 // openFile opens or creates the log file
 func (rw *RotateWriter) openFile() error {
 	// Ensure directory exists
 	dir := filepath.Dir(rw.filename)
-	if err := os.MkdirAll(dir, 0755); err != nil {
+	dirMode := rw.dirMode
+	if dirMode == 0 {
+		dirMode = defaultDirMode
+	}
+	_, statErr := os.Stat(dir)
+	if !rw.createDirs {
+		if statErr != nil {
+			return fmt.Errorf("log directory is not accessible: %w", statErr)
+		}
+	} else if err := os.MkdirAll(dir, dirMode); err != nil {
 		return fmt.Errorf("failed to create log directory: %w", err)
 	}
+	// MkdirAll applies the umask, so a configured mode is set explicitly
+	if os.IsNotExist(statErr) && rw.dirMode != 0 {
+		if err := os.Chmod(dir, rw.dirMode); err != nil {
+			return fmt.Errorf("failed to set log directory permissions: %w", err)
+		}
+	}
 
 	// Open the file with append mode
-	file, err := os.OpenFile(rw.filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	fileMode := rw.fileMode
+	if fileMode == 0 {
+		fileMode = defaultFileMode
+	}
+	_, statErr = os.Stat(rw.filename)
+	created := os.IsNotExist(statErr)
+	file, err := os.OpenFile(rw.filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, fileMode)
 	if err != nil {
 		return fmt.Errorf("failed to open log file: %w", err)
 	}
@@ -101,6 +193,15 @@ func (rw *RotateWriter) openFile() error {
 		return fmt.Errorf("failed to stat log file: %w", err)
 	}
 
+	// Set a configured mode exactly on creation, as OpenFile applies the
+	// umask, and restrict existing files allowing more
+	if perm := info.Mode().Perm(); rw.fileMode != 0 && perm != rw.fileMode && (created || perm&^rw.fileMode != 0) {
+		if err := file.Chmod(rw.fileMode); err != nil {
+			file.Close()
+			return fmt.Errorf("failed to set log file permissions: %w", err)
+		}
+	}
+
 	rw.file = file
 	rw.size = info.Size()
 	return nil
@@ -118,22 +219,60 @@ func (rw *RotateWriter) Write(p []byte) (n int, err error) {
 		}
 	}
 
+	// Dated files move on to a new file when the period changes, other files
+	// rotate at the backup interval
+	if rw.period != nil {
+		if err := rw.checkPeriod(time.Now()); err != nil {
+			return 0, err
+		}
+	}
+
 	// Check if we need to rotate based on size or time
 	if (rw.maxSize > 0 && rw.size+int64(len(p)) > rw.maxSize) ||
-		(rw.backupInterval > 0 && time.Since(rw.lastRotate) > rw.backupInterval) {
+		(rw.period == nil && rw.backupInterval > 0 && time.Since(rw.lastRotate) > rw.backupInterval) {
 		if err := rw.rotate(); err != nil {
 			return 0, err
 		}
 	}
 
-	// Write to the file
-	n, err = rw.file.Write(p)
-	rw.size += int64(n)
-	return n, err
+	// Write to the file, applying the write failure policy on errors
+	return rw.writeFile(p)
 }
 
-// Close closes the current file
+// Close closes the current file and waits for the compression and cleanup of
+// rotated backups to finish
 func (rw *RotateWriter) Close() error {
+	err := rw.closeFile()
+	rw.Wait()
+	return err
+}
+
+// CloseWithTimeout closes the current file like Close, but waits at most
+// timeout for backup compression and cleanup. An error is returned if they
+// are still running; they then finish in the background.
+func (rw *RotateWriter) CloseWithTimeout(timeout time.Duration) error {
+	err := rw.closeFile()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-rw.pending.done():
+	case <-timer.C:
+		if err == nil {
+			err = fmt.Errorf("timed out after %s waiting for backup processing", timeout)
+		}
+	}
+	return err
+}
+
+// Wait blocks until the compression and cleanup of backups rotated so far
+// have finished, without closing the writer
+func (rw *RotateWriter) Wait() {
+	<-rw.pending.done()
+}
+
+// closeFile closes the current file, if open
+func (rw *RotateWriter) closeFile() error {
 	rw.mu.Lock()
 	defer rw.mu.Unlock()
 
@@ -157,25 +296,16 @@ func (rw *RotateWriter) rotate() error {
 	}
 
 	// Generate backup filename with timestamp
-	timestamp := time.Now().Format("20060102-150405")
-	backupName := fmt.Sprintf("%s.%s", rw.filename, timestamp)
+	backupName := rw.backupName(time.Now())
 
 	// Rename the current log file to backup name
+	renamed := true
 	if err := os.Rename(rw.filename, backupName); err != nil {
 		if !os.IsNotExist(err) {
 			return fmt.Errorf("failed to rename log file: %w", err)
 		}
 		// If the file doesn't exist, just continue with creating a new one
-	} else {
-		// Compress the backup if enabled
-		if rw.compress {
-			go func(name string) {
-				if err := compressFile(name); err != nil {
-					// Log error but continue - don't want to block main thread
-					fmt.Fprintf(os.Stderr, "Failed to compress backup: %v\n", err)
-				}
-			}(backupName)
-		}
+		renamed = false
 	}
 
 	// Reopen the log file
@@ -186,14 +316,116 @@ func (rw *RotateWriter) rotate() error {
 	// Update last rotation time
 	rw.lastRotate = time.Now()
 
-	// Clean up old backups
-	if rw.maxBackups > 0 {
-		go rw.cleanupOldBackups()
+	// Compress the backup and clean up old ones in the background, so as not
+	// to block writers
+	if (renamed && rw.compress) || rw.maxBackups > 0 {
+		if !renamed {
+			backupName = ""
+		}
+		rw.pending.start()
+		go rw.processBackups(backupName)
 	}
 
 	return nil
 }
 
+// backupName returns an unused backup filename for a rotation at t. Rotations
+// within the same second get an increasing numbered suffix, so that a backup
+// still being compressed is never replaced and newer backups sort last.
+func (rw *RotateWriter) backupName(t time.Time) string {
+	stamp := t.Format(backupTimeFormat)
+	seq := 0
+	if stamp == rw.lastStamp {
+		seq = rw.lastSeq + 1
+	}
+	name := fmt.Sprintf("%s.%s", rw.filename, stamp)
+	candidate := name
+	for {
+		if seq > 0 {
+			candidate = fmt.Sprintf("%s-%d", name, seq)
+		}
+		if !backupExists(candidate) {
+			break
+		}
+		seq++
+	}
+	rw.lastStamp, rw.lastSeq = stamp, seq
+	return candidate
+}
+
+// backupExists reports whether a backup exists as name, compressed or not
+func backupExists(name string) bool {
+	for _, path := range []string{name, name + ".gz"} {
+		if _, err := os.Lstat(path); err == nil || !os.IsNotExist(err) {
+			return true
+		}
+	}
+	return false
+}
+
+// processBackups compresses the new backup, if any, and then removes old
+// backups. Backups are processed one rotation at a time, so cleanup never
+// sees a backup that is half compressed.
+func (rw *RotateWriter) processBackups(backupName string) {
+	defer rw.pending.finish()
+	rw.backupMu.Lock()
+	defer rw.backupMu.Unlock()
+
+	if backupName != "" && rw.compress {
+		// The backup may already have been removed by the cleanup of a
+		// later rotation
+		if err := compressFile(backupName); err != nil && !os.IsNotExist(err) {
+			// Log error but continue - the uncompressed backup is kept
+			fmt.Fprintf(os.Stderr, "Failed to compress backup: %v\n", err)
+		}
+	}
+
+	if rw.maxBackups > 0 {
+		rw.cleanupOldBackups()
+	}
+}
+
+// backgroundWork counts goroutines still running. Unlike a sync.WaitGroup it
+// can be waited on while new work starts.
+type backgroundWork struct {
+	mu      sync.Mutex
+	running int
+	idle    chan struct{} // Closed once running drops to zero
+}
+
+// start records a goroutine about to run
+func (w *backgroundWork) start() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.running == 0 {
+		w.idle = make(chan struct{})
+	}
+	w.running++
+}
+
+// finish records a goroutine that has returned
+func (w *backgroundWork) finish() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.running--
+	if w.running == 0 {
+		close(w.idle)
+	}
+}
+
+// done returns a channel closed once the goroutines running now, and any
+// started before they finish, have returned
+func (w *backgroundWork) done() <-chan struct{} {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.running == 0 {
+		closed := make(chan struct{})
+		close(closed)
+		return closed
+	}
+	return w.idle
+}
+
 // compressFile compresses a file and removes the original
 func compressFile(filename string) error {
 	// Open the original file
@@ -203,89 +435,247 @@ func compressFile(filename string) error {
 	}
 	defer file.Close()
 
-	// Create the compressed file
+	// Create the compressed file with the permissions of the original
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
 	compressedName := filename + ".gz"
-	compressed, err := os.Create(compressedName)
+	compressed, err := os.OpenFile(compressedName, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm())
 	if err != nil {
 		return err
 	}
 	defer compressed.Close()
+	if err := compressed.Chmod(info.Mode().Perm()); err != nil {
+		compressed.Close()
+		os.Remove(compressedName)
+		return err
+	}
 
 	// Create a gzip writer
 	gzipWriter := gzip.NewWriter(compressed)
 	defer gzipWriter.Close()
 
-	// Copy the file contents to the gzip writer
-	_, err = io.Copy(gzipWriter, file)
+	// Copy the file contents to the gzip writer. A partial compressed file
+	// is removed so that it is not mistaken for a complete backup.
+	if _, err = io.Copy(gzipWriter, file); err == nil {
+		err = gzipWriter.Close()
+	}
+	if err == nil {
+		err = compressed.Close()
+	}
 	if err != nil {
+		compressed.Close()
+		os.Remove(compressedName)
 		return err
 	}
-
-	// Close both writers before removing the original
-	gzipWriter.Close()
-	compressed.Close()
 	file.Close()
 
 	// Remove the original file
 	return os.Remove(filename)
 }
 
-// cleanupOldBackups removes old backup files exceeding maxBackups
-func (rw *RotateWriter) cleanupOldBackups() {
-	// Get the base path without extension
-	dir := filepath.Dir(rw.filename)
-	base := filepath.Base(rw.filename)
+// backupTimeFormat is the layout of the timestamp in backup filenames
+const backupTimeFormat = "20060102-150405"
+
+// backupTimeFormats are the timestamp layouts recognized in the names of
+// existing backups
+var backupTimeFormats = []string{backupTimeFormat}
+
+// backupFile is a backup found in the log directory
+type backupFile struct {
+	period time.Time // Period of a dated file, zero otherwise
+	final  bool      // The whole dated file of its period, newer than its size rotations
+	time   time.Time // Rotation time parsed from the name
+	seq    int       // Counter of rotations within the same second
+	files  []string  // Paths of the backup, compressed or not
+}
+
+// before reports whether b is older than other
+func (b *backupFile) before(other *backupFile) bool {
+	if !b.period.Equal(other.period) {
+		return b.period.Before(other.period)
+	}
+	if b.final != other.final {
+		return other.final
+	}
+	if !b.time.Equal(other.time) {
+		return b.time.Before(other.time)
+	}
+	return b.seq < other.seq
+}
+
+// parseBackupName parses the suffix following the log filename and a dot in
+// the name of a backup: a rotation timestamp, an optional "-N" counter and an
+// optional ".gz" extension. It reports false for names of any other form.
+func parseBackupName(suffix string) (t time.Time, seq int, ok bool) {
+	suffix = strings.TrimSuffix(suffix, ".gz")
+	for _, layout := range backupTimeFormats {
+		if len(suffix) < len(layout) {
+			continue
+		}
+		stamp, err := time.ParseInLocation(layout, suffix[:len(layout)], time.Local)
+		if err != nil {
+			continue
+		}
+		rest := suffix[len(layout):]
+		if rest == "" {
+			return stamp, 0, true
+		}
+		if !strings.HasPrefix(rest, "-") {
+			continue
+		}
+		n, err := strconv.Atoi(rest[1:])
+		if err != nil || n < 1 || strconv.Itoa(n) != rest[1:] {
+			continue
+		}
+		return stamp, n, true
+	}
+	return time.Time{}, 0, false
+}
 
-	// Get all backup files
-	pattern := filepath.Join(dir, base+".????????-??????*")
-	matches, err := filepath.Glob(pattern)
+// cleanupOldBackups removes the oldest backups exceeding maxBackups
+func (rw *RotateWriter) cleanupOldBackups() {
+	backups, err := rw.listBackups()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to find backup files: %v\n", err)
 		return
 	}
 
-	// Add .gz files too
-	gzPattern := filepath.Join(dir, base+".????????-??????*.gz")
-	gzMatches, err := filepath.Glob(gzPattern)
-	if err == nil {
-		matches = append(matches, gzMatches...)
-	}
-
 	// If we don't have too many backups, nothing to do
-	if len(matches) <= rw.maxBackups {
+	if len(backups) <= rw.maxBackups {
 		return
 	}
 
-	// Sort the backups by modification time (oldest first).
-	// os.Stat can return nil if a file disappears between Glob and Stat
-	// (e.g. the compress goroutine renamed/removed it), so guard against
-	// nil FileInfo before calling ModTime to avoid a nil pointer panic.
-	sort.Slice(matches, func(i, j int) bool {
-		infoI, errI := os.Stat(matches[i])
-		infoJ, errJ := os.Stat(matches[j])
-		// If either stat failed, treat that file as "newer" so it won't
-		// be chosen for deletion — return false when i is the bad one.
-		if errI != nil {
-			return false
+	// Remove excess backups
+	for _, backup := range backups[:len(backups)-rw.maxBackups] {
+		backup.remove()
+	}
+}
+
+// listBackups returns the backups of the log file, oldest first. Only files
+// named after the log file and a valid rotation timestamp are treated as
+// backups, and a backup and its compressed copy count as a single backup.
+// With WithRotateByDate the files of past periods are backups too.
+func (rw *RotateWriter) listBackups() ([]*backupFile, error) {
+	dir := filepath.Dir(rw.filename)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Group the files by backup
+	backups := make(map[string]*backupFile)
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.Type().IsRegular() {
+			continue
 		}
-		if errJ != nil {
-			return true
+		backup, ok := rw.parseBackup(name)
+		if !ok {
+			continue
+		}
+		key := strings.TrimSuffix(name, ".gz")
+		if backups[key] == nil {
+			backups[key] = backup
 		}
-		return infoI.ModTime().Before(infoJ.ModTime())
+		backups[key].files = append(backups[key].files, filepath.Join(dir, name))
+	}
+
+	// Sort the backups by their rotation time (oldest first)
+	sorted := make([]*backupFile, 0, len(backups))
+	for _, backup := range backups {
+		sorted = append(sorted, backup)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].before(sorted[j])
 	})
+	return sorted, nil
+}
 
-	// Remove excess backups
-	for i := 0; i < len(matches)-rw.maxBackups; i++ {
-		if err := os.Remove(matches[i]); err != nil {
+// parseBackup parses the name of a file in the log directory, reporting
+// whether it is a backup
+func (rw *RotateWriter) parseBackup(name string) (*backupFile, bool) {
+	logName := filepath.Base(rw.filename)
+	var period time.Time
+	if rw.period != nil {
+		var ok bool
+		if period, logName, ok = rw.parseDatedName(name); !ok {
+			return nil, false
+		}
+		// The dated file of a past period is a backup as a whole
+		if name == logName+".gz" || (name == logName && name != filepath.Base(rw.filename)) {
+			return &backupFile{period: period, final: true}, true
+		}
+	}
+
+	prefix := logName + "."
+	if !strings.HasPrefix(name, prefix) {
+		return nil, false
+	}
+	t, seq, ok := parseBackupName(name[len(prefix):])
+	if !ok {
+		return nil, false
+	}
+	return &backupFile{period: period, time: t, seq: seq}, true
+}
+
+// remove deletes the files of a backup
+func (b *backupFile) remove() {
+	for _, file := range b.files {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
 			fmt.Fprintf(os.Stderr, "Failed to remove old backup: %v\n", err)
 		}
 	}
 }
 
+// HealthCheck verifies that the current log file is open and writable. It
+// reopens the file if it is closed and returns an error if the file has been
+// removed or renamed behind the writer's back, or if writing or syncing fails
+// (e.g. because the disk is full).
+func (rw *RotateWriter) HealthCheck() error {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+
+	if rw.file == nil {
+		if err := rw.openFile(); err != nil {
+			return err
+		}
+	}
+
+	// Make sure the open handle still refers to the file at our path
+	openInfo, err := rw.file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat open log file: %w", err)
+	}
+	pathInfo, err := os.Stat(rw.filename)
+	if err != nil {
+		return fmt.Errorf("log file %s is no longer accessible: %w", rw.filename, err)
+	}
+	if !os.SameFile(openInfo, pathInfo) {
+		return fmt.Errorf("log file %s has been replaced", rw.filename)
+	}
+
+	if _, err := rw.file.Write(nil); err != nil {
+		return fmt.Errorf("failed to write log file: %w", err)
+	}
+	if err := rw.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync log file: %w", err)
+	}
+	return nil
+}
+
 // ForceRotate forces an immediate log rotation regardless of size or time
 func (rw *RotateWriter) ForceRotate() error {
 	rw.mu.Lock()
 	defer rw.mu.Unlock()
+
+	// A lazily opened file that was never written has nothing to rotate
+	if rw.lazyOpen && rw.file == nil {
+		if _, err := os.Stat(rw.filename); os.IsNotExist(err) {
+			return nil
+		}
+	}
 	return rw.rotate()
 }
 
@@ -307,7 +697,7 @@ func WithRotateWriter(filename string, options ...RotateOption) Option {
 	return func(l *Logger) {
 		rotateWriter, err := NewRotateWriter(filename, options...)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to create rotate writer: %v, falling back to stderr\n", err)
+			l.optionError(fmt.Errorf("failed to create rotate writer, falling back to stderr: %w", err))
 			l.out = os.Stderr
 			return
 		}
@@ -0,0 +1,124 @@
+package dy
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// datePeriod names the files of a RotateWriter after the period they cover
+type datePeriod struct {
+	format func(t time.Time) string              // Suffix of the file covering t
+	parse  func(suffix string) (time.Time, bool) // Start of the period of a suffix
+}
+
+// WithRotateByDate names the log file after the current date formatted with
+// layout, and moves on to a new file whenever the formatted date changes.
+// A filename of "logs/app.log" with the layout "2006-01-02" writes to
+// "logs/app-2024-01-02.log". Files of past periods are compressed and count
+// as backups for WithMaxBackups; rotation by size still applies within a
+// period, while the backup interval does not.
+func WithRotateByDate(layout string) RotateOption {
+	return func(rw *RotateWriter) {
+		rw.period = &datePeriod{
+			format: func(t time.Time) string {
+				return t.Format(layout)
+			},
+			parse: func(suffix string) (time.Time, bool) {
+				t, err := time.ParseInLocation(layout, suffix, time.Local)
+				return t, err == nil && t.Format(layout) == suffix
+			},
+		}
+	}
+}
+
+// withRotateByWeek names the log file after the current ISO week, such as
+// "logs/app-2024-W01.log"
+func withRotateByWeek() RotateOption {
+	return func(rw *RotateWriter) {
+		rw.period = &datePeriod{
+			format: func(t time.Time) string {
+				year, week := t.ISOWeek()
+				return fmt.Sprintf("%d-W%02d", year, week)
+			},
+			parse: func(suffix string) (time.Time, bool) {
+				var year, week int
+				if _, err := fmt.Sscanf(suffix, "%4d-W%2d", &year, &week); err != nil {
+					return time.Time{}, false
+				}
+				// Monday of the week: January 4th is always in week 1
+				jan4 := time.Date(year, 1, 4, 0, 0, 0, 0, time.Local)
+				monday := jan4.AddDate(0, 0, -(int(jan4.Weekday())+6)%7+(week-1)*7)
+				y, w := monday.ISOWeek()
+				return monday, y == year && w == week && fmt.Sprintf("%d-W%02d", year, week) == suffix
+			},
+		}
+	}
+}
+
+// datedName returns the name of the file for the period suffix
+func (rw *RotateWriter) datedName(suffix string) string {
+	stem, ext := splitLogExt(rw.base)
+	return stem + "-" + suffix + ext
+}
+
+// splitLogExt splits a filename into its stem and extension, ".log" if it
+// has none
+func splitLogExt(filename string) (string, string) {
+	ext := filepath.Ext(filename)
+	if ext == "" {
+		return filename, ".log"
+	}
+	return strings.TrimSuffix(filename, ext), ext
+}
+
+// parseDatedName parses the name of a file in the log directory written by
+// a dated writer. It returns the start of the file's period and the base
+// name of the dated file, which name is or is a backup of.
+func (rw *RotateWriter) parseDatedName(name string) (time.Time, string, bool) {
+	stem, ext := splitLogExt(filepath.Base(rw.base))
+	rest, ok := strings.CutPrefix(name, stem+"-")
+	if !ok {
+		return time.Time{}, "", false
+	}
+	i := strings.Index(rest, ext)
+	if i < 0 {
+		return time.Time{}, "", false
+	}
+	period, ok := rw.period.parse(rest[:i])
+	if !ok {
+		return time.Time{}, "", false
+	}
+	return period, stem + "-" + rest[:i] + ext, true
+}
+
+// checkPeriod moves on to the file of the period of now if it differs from
+// the current one, compressing the finished file and cleaning up old ones
+// in the background. rw.mu must be held.
+func (rw *RotateWriter) checkPeriod(now time.Time) error {
+	suffix := rw.period.format(now)
+	if suffix == rw.current {
+		return nil
+	}
+
+	if rw.file != nil {
+		if err := rw.file.Close(); err != nil {
+			return err
+		}
+		rw.file = nil
+	}
+	finished := rw.filename
+	rw.current = suffix
+	rw.filename = rw.datedName(suffix)
+	if err := rw.openFile(); err != nil {
+		return err
+	}
+	rw.lastRotate = now
+
+	if rw.compress || rw.maxBackups > 0 {
+		rw.pending.start()
+		go rw.processBackups(finished)
+	}
+	return nil
+}
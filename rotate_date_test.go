@@ -0,0 +1,120 @@
+package dy
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewFileRotateByHour(t *testing.T) {
+	base := filepath.Join(t.TempDir(), "app")
+	log, err := NewFileRotateByHour(base, WithTimestamp(false))
+	if err != nil {
+		t.Fatalf("NewFileRotateByHour failed: %v", err)
+	}
+	defer log.Close()
+
+	log.Info("hourly entry")
+	rw := log.GetOutput().(*RotateWriter)
+	want := base + "-" + time.Now().Format("2006-01-02-15") + ".log"
+	if rw.filename != want || rw.maxBackups != 24 || !rw.compress {
+		t.Errorf("Unexpected writer: %s, %d backups, compress %v", rw.filename, rw.maxBackups, rw.compress)
+	}
+	// The hour may have changed since the writer was created
+	if content, err := os.ReadFile(rw.filename); err != nil || !strings.Contains(string(content), "hourly entry") {
+		t.Errorf("Expected the entry in %s, got %q (%v)", rw.filename, content, err)
+	}
+}
+
+func TestNewFileRotateByDayAndWeek(t *testing.T) {
+	dir := t.TempDir()
+	day, err := NewFileRotateByDay(filepath.Join(dir, "daily"))
+	if err != nil {
+		t.Fatalf("NewFileRotateByDay failed: %v", err)
+	}
+	defer day.Close()
+	if rw := day.GetOutput().(*RotateWriter); !strings.HasSuffix(rw.filename, "daily-"+time.Now().Format("2006-01-02")+".log") || rw.maxBackups != 7 {
+		t.Errorf("Unexpected daily writer: %s, %d backups", rw.filename, rw.maxBackups)
+	}
+
+	week, err := NewFileRotateByWeek(filepath.Join(dir, "weekly"))
+	if err != nil {
+		t.Fatalf("NewFileRotateByWeek failed: %v", err)
+	}
+	defer week.Close()
+	year, w := time.Now().ISOWeek()
+	rw := week.GetOutput().(*RotateWriter)
+	if name := filepath.Base(rw.filename); name != fmt.Sprintf("weekly-%d-W%02d.log", year, w) || rw.maxBackups != 4 {
+		t.Errorf("Unexpected weekly writer: %s, %d backups", name, rw.maxBackups)
+	}
+}
+
+func TestRotateByDatePeriodChange(t *testing.T) {
+	dir := t.TempDir()
+	rw, err := NewRotateWriter(filepath.Join(dir, "app.log"), WithRotateByDate("2006-01-02-15"), WithMaxBackups(2), WithCompress(true))
+	if err != nil {
+		t.Fatalf("Failed to create rotate writer: %v", err)
+	}
+	defer rw.Close()
+
+	// Move through four hours, writing an entry in each
+	now := time.Now()
+	hour := func(i int) string {
+		return "app-" + now.Add(time.Duration(i)*time.Hour).Format("2006-01-02-15") + ".log"
+	}
+	for i := 1; i <= 4; i++ {
+		rw.mu.Lock()
+		err := rw.checkPeriod(now.Add(time.Duration(i) * time.Hour))
+		if err == nil {
+			_, err = rw.file.Write([]byte("entry\n"))
+		}
+		rw.mu.Unlock()
+		if err != nil {
+			t.Fatalf("Failed to write hour %d: %v", i, err)
+		}
+	}
+	rw.Wait()
+
+	entries, _ := os.ReadDir(dir)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	want := []string{hour(2) + ".gz", hour(3) + ".gz", hour(4)}
+	sort.Strings(want)
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected files %v, got %v", want, names)
+	}
+}
+
+func TestParseDatedName(t *testing.T) {
+	rw, err := NewRotateWriter(filepath.Join(t.TempDir(), "app.log"), withRotateByWeek())
+	if err != nil {
+		t.Fatalf("Failed to create rotate writer: %v", err)
+	}
+	defer rw.Close()
+
+	tests := []struct {
+		name   string
+		period string
+		ok     bool
+	}{
+		{"app-2024-W01.log", "2024-01-01", true},
+		{"app-2024-W01.log.gz", "2024-01-01", true},
+		{"app-2021-W01.log.20210105-101500", "2021-01-04", true},
+		{"app-2024-W1.log", "", false},
+		{"app-2024-W60.log", "", false},
+		{"app-notes.log", "", false},
+		{"other-2024-W01.log", "", false},
+	}
+	for _, tt := range tests {
+		period, _, ok := rw.parseDatedName(tt.name)
+		if ok != tt.ok || (ok && period.Format("2006-01-02") != tt.period) {
+			t.Errorf("parseDatedName(%q) = %v, %v; expected %s, %v", tt.name, period, ok, tt.period, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package dy
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// writeFailureMode selects how a RotateWriter handles failed writes
+type writeFailureMode int
+
+const (
+	failReturn   writeFailureMode = iota // Return the error to the caller
+	failDrop                             // Discard the entry
+	failRetry                            // Retry with backoff, then discard
+	failFallback                         // Write the entry elsewhere
+)
+
+// WriteFailurePolicy decides what a RotateWriter does with an entry it could
+// not write to its file, for example because the disk is full. Without a
+// policy the error is returned to the caller.
+type WriteFailurePolicy struct {
+	mode       writeFailureMode
+	interval   time.Duration // First retry delay, doubled for each retry
+	maxRetries int           // Retries before the entry is discarded
+	fallback   io.Writer     // Receives entries in fallback mode
+}
+
+// Drop discards entries that cannot be written, counting them in
+// RotateStats.Dropped
+var Drop = WriteFailurePolicy{mode: failDrop}
+
+// BlockRetry retries failed writes up to max times, reopening the file before
+// each retry and waiting interval before the first retry and twice as long
+// before each following one. Writers block meanwhile. Entries still failing
+// after the last retry are discarded and the error is returned.
+func BlockRetry(interval time.Duration, max int) WriteFailurePolicy {
+	return WriteFailurePolicy{mode: failRetry, interval: interval, maxRetries: max}
+}
+
+// Fallback writes entries that cannot be written to the file to w instead, or
+// to stderr when w is nil
+func Fallback(w io.Writer) WriteFailurePolicy {
+	if w == nil {
+		w = os.Stderr
+	}
+	return WriteFailurePolicy{mode: failFallback, fallback: w}
+}
+
+// WithWriteFailurePolicy sets what the writer does with entries it fails to
+// write. On a full disk the oldest backup is removed and the write retried
+// once before the policy applies.
+func WithWriteFailurePolicy(policy WriteFailurePolicy) RotateOption {
+	return func(rw *RotateWriter) {
+		rw.failurePolicy = policy
+	}
+}
+
+// RotateStats counts the write failures of a RotateWriter
+type RotateStats struct {
+	Failures  uint64 // Writes that failed at first
+	Recovered uint64 // Failed writes that succeeded on a retry
+	Dropped   uint64 // Entries discarded after failing
+	Fallbacks uint64 // Entries written to the fallback writer
+}
+
+// rotateCounters holds the counters reported by Stats
+type rotateCounters struct {
+	failures  atomic.Uint64
+	recovered atomic.Uint64
+	dropped   atomic.Uint64
+	fallbacks atomic.Uint64
+}
+
+// Stats returns the write failure counters of the writer
+func (rw *RotateWriter) Stats() RotateStats {
+	return RotateStats{
+		Failures:  rw.stats.failures.Load(),
+		Recovered: rw.stats.recovered.Load(),
+		Dropped:   rw.stats.dropped.Load(),
+		Fallbacks: rw.stats.fallbacks.Load(),
+	}
+}
+
+// writeFile writes p to the current file, applying the write failure policy
+// when that fails. rw.mu must be held.
+func (rw *RotateWriter) writeFile(p []byte) (int, error) {
+	n, err := rw.file.Write(p)
+	rw.size += int64(n)
+	if err == nil {
+		return n, nil
+	}
+	rw.stats.failures.Add(1)
+
+	// Make room on a full disk and try again right away
+	if isDiskFull(err) && rw.removeOldestBackup() {
+		if n, err = rw.retryWrite(p, n); err == nil {
+			rw.stats.recovered.Add(1)
+			return len(p), nil
+		}
+	}
+
+	policy := rw.failurePolicy
+	switch policy.mode {
+	case failDrop:
+		rw.stats.dropped.Add(1)
+		return len(p), nil
+	case failRetry:
+		delay := policy.interval
+		for i := 0; i < policy.maxRetries; i++ {
+			time.Sleep(delay)
+			delay *= 2
+			if n, err = rw.retryWrite(p, n); err == nil {
+				rw.stats.recovered.Add(1)
+				return len(p), nil
+			}
+		}
+		rw.stats.dropped.Add(1)
+		return n, err
+	case failFallback:
+		rw.stats.fallbacks.Add(1)
+		if _, ferr := policy.fallback.Write(p[n:]); ferr != nil {
+			return n, fmt.Errorf("failed to write log file: %w; fallback failed: %v", err, ferr)
+		}
+		return len(p), nil
+	}
+	return n, err
+}
+
+// retryWrite reopens the file and writes the part of p after the written
+// bytes, returning the total written so far
+func (rw *RotateWriter) retryWrite(p []byte, written int) (int, error) {
+	if rw.file != nil {
+		rw.file.Close()
+		rw.file = nil
+	}
+	if err := rw.openFile(); err != nil {
+		return written, err
+	}
+	n, err := rw.file.Write(p[written:])
+	rw.size += int64(n)
+	return written + n, err
+}
+
+// removeOldestBackup deletes the oldest backup to free disk space, reporting
+// whether there was one
+func (rw *RotateWriter) removeOldestBackup() bool {
+	rw.backupMu.Lock()
+	defer rw.backupMu.Unlock()
+
+	backups, err := rw.listBackups()
+	if err != nil || len(backups) == 0 {
+		return false
+	}
+	backups[0].remove()
+	return true
+}
+
+// isDiskFull reports whether err means the disk or quota is full
+func isDiskFull(err error) bool {
+	return errors.Is(err, syscall.ENOSPC) || errors.Is(err, syscall.EDQUOT)
+}
@@ -0,0 +1,139 @@
+package dy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// brokenRotateWriter returns a writer whose file handle fails on write
+func brokenRotateWriter(t *testing.T, options ...RotateOption) (*RotateWriter, string) {
+	t.Helper()
+	logFile := filepath.Join(t.TempDir(), "app.log")
+	rw, err := NewRotateWriter(logFile, options...)
+	if err != nil {
+		t.Fatalf("Failed to create rotate writer: %v", err)
+	}
+	t.Cleanup(func() { rw.Close() })
+
+	// Close the handle behind the writer's back
+	rw.file.Close()
+	return rw, logFile
+}
+
+func TestWriteFailurePolicyDefault(t *testing.T) {
+	rw, _ := brokenRotateWriter(t)
+	if _, err := rw.Write([]byte("entry\n")); err == nil {
+		t.Error("Expected the write error without a policy")
+	}
+	if stats := rw.Stats(); stats.Failures != 1 || stats.Dropped != 0 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestWriteFailurePolicyDrop(t *testing.T) {
+	rw, _ := brokenRotateWriter(t, WithWriteFailurePolicy(Drop))
+	n, err := rw.Write([]byte("entry\n"))
+	if err != nil || n != len("entry\n") {
+		t.Errorf("Expected the entry to be dropped silently, got %d, %v", n, err)
+	}
+	if stats := rw.Stats(); stats.Failures != 1 || stats.Dropped != 1 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestWriteFailurePolicyBlockRetry(t *testing.T) {
+	rw, logFile := brokenRotateWriter(t, WithWriteFailurePolicy(BlockRetry(time.Millisecond, 3)))
+	if _, err := rw.Write([]byte("recovered entry\n")); err != nil {
+		t.Fatalf("Expected the retry to succeed, got %v", err)
+	}
+	if stats := rw.Stats(); stats.Failures != 1 || stats.Recovered != 1 || stats.Dropped != 0 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+
+	content, _ := os.ReadFile(logFile)
+	if string(content) != "recovered entry\n" {
+		t.Errorf("Expected the entry in the log file once, got %q", content)
+	}
+}
+
+func TestWriteFailurePolicyFallback(t *testing.T) {
+	var fallback bytes.Buffer
+	rw, _ := brokenRotateWriter(t, WithWriteFailurePolicy(Fallback(&fallback)))
+	if _, err := rw.Write([]byte("fallback entry\n")); err != nil {
+		t.Fatalf("Expected the fallback to take the entry, got %v", err)
+	}
+	if fallback.String() != "fallback entry\n" {
+		t.Errorf("Expected the entry in the fallback writer, got %q", fallback.String())
+	}
+	if stats := rw.Stats(); stats.Fallbacks != 1 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+
+	if Fallback(nil).fallback != os.Stderr {
+		t.Error("Expected Fallback(nil) to write to stderr")
+	}
+}
+
+func TestWriteFailurePolicyDiskFull(t *testing.T) {
+	// Writes to /dev/full fail with ENOSPC
+	if f, err := os.OpenFile("/dev/full", os.O_WRONLY, 0); err != nil {
+		t.Skip("/dev/full is not available")
+	} else {
+		f.Close()
+	}
+
+	rw, err := NewRotateWriter("/dev/full", WithMaxSize(0), WithBackupInterval(0),
+		WithWriteFailurePolicy(BlockRetry(time.Millisecond, 2)))
+	if err != nil {
+		t.Fatalf("Failed to create rotate writer: %v", err)
+	}
+	defer rw.Close()
+
+	_, err = rw.Write([]byte("entry\n"))
+	if !isDiskFull(err) {
+		t.Errorf("Expected a disk full error once retries are exhausted, got %v", err)
+	}
+	if stats := rw.Stats(); stats.Failures != 1 || stats.Dropped != 1 || stats.Recovered != 0 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestRemoveOldestBackup(t *testing.T) {
+	tempDir := t.TempDir()
+	rw := &RotateWriter{filename: filepath.Join(tempDir, "app.log")}
+	names := []string{"app.log.20240101-000000", "app.log.20240101-000000.gz", "app.log.20240102-000000.gz", "app.log.notes"}
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte("x"), 0644); err != nil {
+			t.Fatalf("Failed to create file: %v", err)
+		}
+	}
+
+	if !rw.removeOldestBackup() {
+		t.Fatal("Expected a backup to be removed")
+	}
+	left, _ := filepath.Glob(filepath.Join(tempDir, "app.log.*"))
+	if got := fmt.Sprint(left); strings.Contains(got, "20240101") || !strings.Contains(got, "20240102") || !strings.Contains(got, "notes") {
+		t.Errorf("Expected only the oldest backup to be removed, left %v", left)
+	}
+
+	rw.removeOldestBackup()
+	if rw.removeOldestBackup() {
+		t.Error("Expected no backup left to remove")
+	}
+}
+
+func TestIsDiskFull(t *testing.T) {
+	if !isDiskFull(&os.PathError{Op: "write", Path: "app.log", Err: syscall.ENOSPC}) {
+		t.Error("Expected ENOSPC to be a disk full error")
+	}
+	if isDiskFull(errors.New("other")) {
+		t.Error("Expected other errors not to be disk full errors")
+	}
+}
@@ -0,0 +1,78 @@
+package dy
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// assertMode fails t unless path has the permission bits mode
+func assertMode(t *testing.T, path string, mode os.FileMode) {
+	t.Helper()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Failed to stat %s: %v", path, err)
+	}
+	if got := info.Mode().Perm(); got != mode {
+		t.Errorf("Expected %s to have mode %v, got %v", path, mode, got)
+	}
+}
+
+func TestRotateWriterFileMode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permission bits are not supported on Windows")
+	}
+
+	dir := filepath.Join(t.TempDir(), "private")
+	logFile := filepath.Join(dir, "app.log")
+	rw, err := NewRotateWriter(logFile, WithFileMode(0600), WithDirMode(0700), WithCompress(true))
+	if err != nil {
+		t.Fatalf("Failed to create rotate writer: %v", err)
+	}
+	assertMode(t, dir, 0700)
+	assertMode(t, logFile, 0600)
+
+	rw.Write([]byte("entry\n"))
+	if err := rw.ForceRotate(); err != nil {
+		t.Fatalf("Failed to force rotation: %v", err)
+	}
+	rw.Close()
+
+	// The new file and the compressed backup keep the mode
+	assertMode(t, logFile, 0600)
+	backups, _ := filepath.Glob(filepath.Join(dir, "app.log.*.gz"))
+	if len(backups) != 1 {
+		t.Fatalf("Expected one compressed backup, found %v", backups)
+	}
+	assertMode(t, backups[0], 0600)
+}
+
+func TestRotateWriterFileModeExistingFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permission bits are not supported on Windows")
+	}
+
+	dir := t.TempDir()
+	loose := filepath.Join(dir, "loose.log")
+	strict := filepath.Join(dir, "strict.log")
+	for path, mode := range map[string]os.FileMode{loose: 0666, strict: 0600} {
+		if err := os.WriteFile(path, []byte("old\n"), mode); err != nil {
+			t.Fatalf("Failed to create file: %v", err)
+		}
+		if err := os.Chmod(path, mode); err != nil {
+			t.Fatalf("Failed to set mode: %v", err)
+		}
+	}
+
+	// More permissive files are restricted, stricter ones left alone
+	for _, path := range []string{loose, strict} {
+		rw, err := NewRotateWriter(path, WithFileMode(0640))
+		if err != nil {
+			t.Fatalf("Failed to create rotate writer: %v", err)
+		}
+		rw.Close()
+	}
+	assertMode(t, loose, 0640)
+	assertMode(t, strict, 0600)
+}
@@ -0,0 +1,127 @@
+package dy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// RotateTeeWriter writes every entry to a RotateWriter and to a console
+// writer. Each write goes to both sinks even if the other fails.
+type RotateTeeWriter struct {
+	file      *RotateWriter
+	console   io.Writer
+	formatter *Logger // Renders JSON entries as text for the console, if set
+}
+
+// WithConsoleText makes WithRotateWriterTee write entries to the console in
+// the text format while the file gets the logger's format, typically JSON.
+// It has no effect on a RotateWriter used on its own.
+func WithConsoleText(enable bool) RotateOption {
+	return func(rw *RotateWriter) {
+		rw.consoleText = enable
+	}
+}
+
+// NewRotateTeeWriter creates a writer sending every entry to a new rotating
+// file and to console
+func NewRotateTeeWriter(filename string, console io.Writer, options ...RotateOption) (*RotateTeeWriter, error) {
+	rw, err := NewRotateWriter(filename, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &RotateTeeWriter{file: rw, console: console}
+	if rw.consoleText {
+		// The formatter is only used for formatting, never for writing
+		t.formatter = New(WithOutput(console), WithColor(true))
+	}
+	return t, nil
+}
+
+// File returns the rotating file writer, e.g. for Wait or Stats
+func (t *RotateTeeWriter) File() *RotateWriter {
+	return t.file
+}
+
+// Unwrap returns the file and console writers
+func (t *RotateTeeWriter) Unwrap() []io.Writer {
+	return []io.Writer{t.file, t.console}
+}
+
+// ForceRotate rotates the file, see RotateWriter.ForceRotate
+func (t *RotateTeeWriter) ForceRotate() error {
+	return t.file.ForceRotate()
+}
+
+// Write writes p to the file and the console, returning the errors of both
+func (t *RotateTeeWriter) Write(p []byte) (int, error) {
+	_, fileErr := t.file.Write(p)
+	_, consoleErr := t.console.Write(t.consoleBytes(p))
+	if consoleErr != nil {
+		consoleErr = fmt.Errorf("failed to write console: %w", consoleErr)
+	}
+	return len(p), errors.Join(fileErr, consoleErr)
+}
+
+// consoleBytes returns p as written to the console, with JSON entries
+// rendered as text when console text is enabled
+func (t *RotateTeeWriter) consoleBytes(p []byte) []byte {
+	if t.formatter == nil {
+		return p
+	}
+
+	var buf bytes.Buffer
+	for _, line := range bytes.SplitAfter(p, []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		entry, err := decodeEntry(bytes.TrimSuffix(line, []byte("\n")))
+		if err != nil {
+			buf.Write(line)
+			continue
+		}
+		buf.WriteString(t.formatter.humanizeEntry(ParseLevel(entry.Level), entry))
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+// HealthCheck checks the rotating file, see RotateWriter.HealthCheck
+func (t *RotateTeeWriter) HealthCheck() error {
+	return t.file.HealthCheck()
+}
+
+// Close closes the file only, leaving the console open
+func (t *RotateTeeWriter) Close() error {
+	return t.file.Close()
+}
+
+// WithRotateWriterTee creates a log output option writing every entry both
+// to a rotating file and to console, such as os.Stdout in a container.
+// Logger.Close closes only the file. Add WithConsoleText(true) to the rotate
+// options to keep the console readable while the file is written in JSON.
+//
+// Example usage:
+//
+//	logger := dy.New(
+//	    dy.WithRotateWriterTee("logs/app.log", os.Stdout,
+//	        dy.WithMaxSize(10),
+//	        dy.WithConsoleText(true),
+//	    ),
+//	    dy.WithJSONFormat(true),
+//	)
+//	defer logger.Close()
+func WithRotateWriterTee(filename string, console io.Writer, rotateOpts ...RotateOption) Option {
+	return func(l *Logger) {
+		tee, err := NewRotateTeeWriter(filename, console, rotateOpts...)
+		if err != nil {
+			l.optionError(fmt.Errorf("failed to create rotate writer, falling back to the console only: %w", err))
+			l.out = console
+			return
+		}
+		l.out = tee
+		l.closer = tee.Close
+	}
+}
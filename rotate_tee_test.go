@@ -0,0 +1,81 @@
+package dy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// failingWriter fails every write
+type failingWriter struct{ closed bool }
+
+func (w *failingWriter) Write(p []byte) (int, error) { return 0, errors.New("console gone") }
+func (w *failingWriter) Close() error                { w.closed = true; return nil }
+
+func TestWithRotateWriterTee(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "app.log")
+	var console bytes.Buffer
+	l := New(
+		WithRotateWriterTee(logFile, &console, WithConsoleText(true)),
+		WithJSONFormat(true),
+		WithTimestamp(false),
+	)
+	l.WithContext("user_id", 42).Info("request handled")
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	content, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	var entry LogEntry
+	if err := json.Unmarshal(content, &entry); err != nil || entry.Message != "request handled" {
+		t.Errorf("Expected a JSON entry in the file, got %q (%v)", content, err)
+	}
+
+	if got := console.String(); got != "[INFO] request handled {user_id: 42}\n" {
+		t.Errorf("Expected a text entry on the console, got %q", got)
+	}
+}
+
+func TestWithRotateWriterTeeSameFormat(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "app.log")
+	var console bytes.Buffer
+	l := New(WithRotateWriterTee(logFile, &console), WithTimestamp(false))
+	l.Info("both sinks")
+	l.Close()
+
+	content, _ := os.ReadFile(logFile)
+	if string(content) != console.String() || !strings.Contains(console.String(), "both sinks") {
+		t.Errorf("Expected the same entry on both sinks, got %q and %q", content, console.String())
+	}
+}
+
+func TestRotateTeeWriterConsoleFailure(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "app.log")
+	console := &failingWriter{}
+	tee, err := NewRotateTeeWriter(logFile, console)
+	if err != nil {
+		t.Fatalf("Failed to create tee writer: %v", err)
+	}
+
+	if _, err := tee.Write([]byte("entry\n")); err == nil || !strings.Contains(err.Error(), "console gone") {
+		t.Errorf("Expected the console error, got %v", err)
+	}
+	if err := tee.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if console.closed {
+		t.Error("Expected Close to leave the console open")
+	}
+
+	// The file still got the entry
+	if content, _ := os.ReadFile(logFile); string(content) != "entry\n" {
+		t.Errorf("Expected the entry in the file, got %q", content)
+	}
+}
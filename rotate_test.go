@@ -1,10 +1,14 @@
 package dy
 
 import (
+	"compress/gzip"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
 	"testing"
 	"time"
@@ -56,8 +60,8 @@ func TestRotateWriter(t *testing.T) {
 		t.Errorf("Log file doesn't exist after rotation")
 	}
 
-	// Check for backup files (allow some time for compression)
-	time.Sleep(100 * time.Millisecond)
+	// Check for backup files once compression has finished
+	rw.Wait()
 	matches, err := filepath.Glob(filepath.Join(tempDir, "test.log.*"))
 	if err != nil {
 		t.Fatalf("Failed to glob backup files: %v", err)
@@ -156,16 +160,11 @@ func TestRotateWriterCleanup(t *testing.T) {
 		if err := rw.ForceRotate(); err != nil {
 			t.Fatalf("Failed to force rotation: %v", err)
 		}
-
-		// Small delay to ensure different timestamps
-		time.Sleep(100 * time.Millisecond)
 	}
 
+	// Close waits for the cleanup goroutines
 	rw.Close()
 
-	// Allow time for cleanup goroutine
-	time.Sleep(200 * time.Millisecond)
-
 	// Check number of backup files - should be 2 + current
 	matches, err := filepath.Glob(filepath.Join(tempDir, "cleanup.log*"))
 	if err != nil {
@@ -173,7 +172,386 @@ func TestRotateWriterCleanup(t *testing.T) {
 	}
 
 	// Should have the current log file plus 2 backups (3 total)
-	if len(matches) > 3 {
-		t.Errorf("Expected at most 3 log files, found %d: %v", len(matches), matches)
+	if len(matches) != 3 {
+		t.Errorf("Expected 3 log files, found %d: %v", len(matches), matches)
+	}
+}
+
+func TestRotateWriterHealthCheck(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "health.log")
+
+	rw, err := NewRotateWriter(logFile, WithCompress(false))
+	if err != nil {
+		t.Fatalf("Failed to create rotate writer: %v", err)
+	}
+	defer rw.Close()
+
+	if err := rw.HealthCheck(); err != nil {
+		t.Fatalf("Expected healthy writer, got: %v", err)
+	}
+
+	// Closed files are reopened
+	rw.Close()
+	if err := rw.HealthCheck(); err != nil {
+		t.Fatalf("Expected closed writer to reopen, got: %v", err)
+	}
+
+	// Simulate the file being deleted mid-write
+	rw.Write([]byte("before delete\n"))
+	if err := os.Remove(logFile); err != nil {
+		t.Fatalf("Failed to remove log file: %v", err)
+	}
+	if err := rw.HealthCheck(); err == nil {
+		t.Errorf("Expected error after log file was deleted")
+	}
+}
+
+func TestLoggerHealthCheck(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "app.log")
+	l := New(WithRotateWriter(logFile, WithCompress(false)))
+	defer l.Close()
+
+	if err := l.HealthCheck(); err != nil {
+		t.Errorf("Expected healthy logger, got: %v", err)
+	}
+
+	os.Remove(logFile)
+	if err := l.HealthCheck(); err == nil {
+		t.Errorf("Expected logger health check to fail after file deletion")
+	}
+
+	// Writers without a health check are always healthy
+	if err := New(WithOutput(&strings.Builder{})).HealthCheck(); err != nil {
+		t.Errorf("Expected nil for plain writers, got: %v", err)
+	}
+}
+
+func TestRotateWriterCompressionStress(t *testing.T) {
+	goroutines := runtime.NumGoroutine()
+	tempDir := t.TempDir()
+	logFile := filepath.Join(tempDir, "stress.log")
+
+	rw, err := NewRotateWriter(logFile,
+		WithMaxBackups(3),
+		WithCompress(true),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create rotate writer: %v", err)
+	}
+
+	// Rotate far faster than backups can be compressed, so that compression
+	// and cleanup of consecutive rotations overlap
+	for i := 0; i < 50; i++ {
+		data := strings.Repeat(fmt.Sprintf("stress data for rotation %d\n", i), 1000)
+		if _, err := rw.Write([]byte(data)); err != nil {
+			t.Fatalf("Failed to write data: %v", err)
+		}
+		if err := rw.ForceRotate(); err != nil {
+			t.Fatalf("Failed to force rotation: %v", err)
+		}
+	}
+
+	// Close waits for the remaining compression and cleanup
+	if err := rw.Close(); err != nil {
+		t.Fatalf("Failed to close rotate writer: %v", err)
+	}
+	assertNoGoroutineLeak(t, goroutines)
+
+	matches, err := filepath.Glob(filepath.Join(tempDir, "stress.log.*"))
+	if err != nil {
+		t.Fatalf("Failed to glob backup files: %v", err)
+	}
+	if len(matches) != 3 {
+		t.Fatalf("Expected 3 backups, found %d: %v", len(matches), matches)
+	}
+
+	for _, match := range matches {
+		if !strings.HasSuffix(match, ".gz") {
+			t.Errorf("Backup %s was not compressed", match)
+			continue
+		}
+		file, err := os.Open(match)
+		if err != nil {
+			t.Fatalf("Failed to open backup: %v", err)
+		}
+		reader, err := gzip.NewReader(file)
+		if err != nil {
+			t.Errorf("Backup %s is not valid gzip: %v", match, err)
+			file.Close()
+			continue
+		}
+		content, err := io.ReadAll(reader)
+		file.Close()
+		if err != nil {
+			t.Errorf("Failed to read backup %s: %v", match, err)
+		}
+		// The newest backups hold the last rotations
+		if !strings.Contains(string(content), "stress data for rotation 4") {
+			t.Errorf("Backup %s doesn't hold one of the last rotations", match)
+		}
+	}
+}
+
+func TestRotateWriterCleanupCountsCompressedPairs(t *testing.T) {
+	tempDir := t.TempDir()
+	logFile := filepath.Join(tempDir, "pair.log")
+
+	rw := &RotateWriter{filename: logFile, maxBackups: 2}
+	backups := []string{
+		"pair.log.20240101-000000",
+		"pair.log.20240101-000000.gz",
+		"pair.log.20240102-000000.gz",
+		"pair.log.20240103-000000",
+		"pair.log.20240103-000000.gz",
+	}
+	for _, name := range backups {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte("x"), 0644); err != nil {
+			t.Fatalf("Failed to create backup: %v", err)
+		}
+	}
+
+	// Three backups are kept as five files, so only the oldest pair goes
+	rw.cleanupOldBackups()
+
+	matches, _ := filepath.Glob(filepath.Join(tempDir, "pair.log.*"))
+	for i := range matches {
+		matches[i] = filepath.Base(matches[i])
+	}
+	sort.Strings(matches)
+	want := backups[2:]
+	if strings.Join(matches, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected backups %v, got %v", want, matches)
+	}
+}
+
+func TestRotateWriterCleanupKeepsDecoys(t *testing.T) {
+	tempDir := t.TempDir()
+	logFile := filepath.Join(tempDir, "app.log")
+
+	rw := &RotateWriter{filename: logFile, maxBackups: 1}
+	backups := []string{
+		"app.log.20240101-000000.gz",
+		"app.log.20240102-000000",
+		"app.log.20240102-000000-1.gz",
+	}
+	decoys := []string{
+		"app.log.important-notes",
+		"app.log.12345678-offsets",
+		"app.log.20241399-000000",
+		"app.log.20240101-000000.bak",
+		"app.log.20240101-000000-x",
+		"app.log.20240101-000000-01",
+		"other.log.20240101-000000",
+	}
+	for _, name := range append(append([]string{}, backups...), decoys...) {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte("x"), 0644); err != nil {
+			t.Fatalf("Failed to create file: %v", err)
+		}
+	}
+
+	rw.cleanupOldBackups()
+
+	for _, name := range decoys {
+		if _, err := os.Stat(filepath.Join(tempDir, name)); err != nil {
+			t.Errorf("Expected decoy %s to survive cleanup: %v", name, err)
+		}
+	}
+	// Only the newest backup is kept, ordered by the time in its name
+	for i, name := range backups {
+		_, err := os.Stat(filepath.Join(tempDir, name))
+		if kept := err == nil; kept != (i == len(backups)-1) {
+			t.Errorf("Backup %s: expected kept=%v, got %v", name, i == len(backups)-1, kept)
+		}
+	}
+}
+
+func TestParseBackupName(t *testing.T) {
+	tests := []struct {
+		suffix string
+		seq    int
+		ok     bool
+	}{
+		{"20240102-030405", 0, true},
+		{"20240102-030405.gz", 0, true},
+		{"20240102-030405-3", 3, true},
+		{"20240102-030405-12.gz", 12, true},
+		{"20240102-030405-0", 0, false},
+		{"20240102-030405.gz.gz", 0, false},
+		{"20241302-030405", 0, false},
+		{"important-notes", 0, false},
+		{"12345678-offsets", 0, false},
+	}
+	for _, tt := range tests {
+		stamp, seq, ok := parseBackupName(tt.suffix)
+		if ok != tt.ok || seq != tt.seq {
+			t.Errorf("parseBackupName(%q) = %v, %d, %v; expected seq %d, ok %v", tt.suffix, stamp, seq, ok, tt.seq, tt.ok)
+		}
+		if ok && stamp.Format(backupTimeFormat) != tt.suffix[:len(backupTimeFormat)] {
+			t.Errorf("parseBackupName(%q) parsed time %v", tt.suffix, stamp)
+		}
+	}
+}
+
+func TestRotateWriterWait(t *testing.T) {
+	goroutines := runtime.NumGoroutine()
+	tempDir := t.TempDir()
+	logFile := filepath.Join(tempDir, "wait.log")
+
+	rw, err := NewRotateWriter(logFile, WithMaxBackups(2), WithCompress(true))
+	if err != nil {
+		t.Fatalf("Failed to create rotate writer: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := rw.Write([]byte(strings.Repeat("wait test line\n", 10000))); err != nil {
+			t.Fatalf("Failed to write data: %v", err)
+		}
+		if err := rw.ForceRotate(); err != nil {
+			t.Fatalf("Failed to force rotation: %v", err)
+		}
+	}
+
+	// Wait leaves the writer open, with every backup compressed
+	rw.Wait()
+	matches, _ := filepath.Glob(filepath.Join(tempDir, "wait.log.*"))
+	if len(matches) != 2 {
+		t.Errorf("Expected 2 backups after Wait, found %v", matches)
+	}
+	for _, match := range matches {
+		if !strings.HasSuffix(match, ".gz") {
+			t.Errorf("Expected backup %s to be compressed after Wait", match)
+		}
+	}
+	if _, err := rw.Write([]byte("still open\n")); err != nil {
+		t.Errorf("Expected the writer to stay open after Wait: %v", err)
+	}
+
+	if err := rw.CloseWithTimeout(5 * time.Second); err != nil {
+		t.Errorf("CloseWithTimeout failed: %v", err)
+	}
+	assertNoGoroutineLeak(t, goroutines)
+}
+
+func TestRotateWriterCloseWithTimeout(t *testing.T) {
+	rw, err := NewRotateWriter(filepath.Join(t.TempDir(), "timeout.log"))
+	if err != nil {
+		t.Fatalf("Failed to create rotate writer: %v", err)
+	}
+
+	// Simulate backup processing that outlives the timeout
+	rw.pending.start()
+	if err := rw.CloseWithTimeout(10 * time.Millisecond); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("Expected a timeout error, got %v", err)
+	}
+
+	rw.pending.finish()
+	if err := rw.CloseWithTimeout(time.Second); err != nil {
+		t.Errorf("Expected no error once processing finished, got %v", err)
+	}
+}
+
+// assertNoGoroutineLeak fails t if more goroutines run than before, allowing
+// goroutines that have finished their work a moment to exit
+func assertNoGoroutineLeak(t *testing.T, before int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			t.Errorf("Expected at most %d goroutines, found %d", before, runtime.NumGoroutine())
+			return
+		}
+		runtime.Gosched()
+	}
+}
+
+func TestRotateWriterLazyOpen(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "logs")
+	logFile := filepath.Join(dir, "app.log")
+
+	rw, err := NewRotateWriter(logFile, WithLazyOpen(true))
+	if err != nil {
+		t.Fatalf("Failed to create rotate writer: %v", err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Fatalf("Expected no directory before the first write, got %v", err)
+	}
+
+	// Rotating or closing an unused writer creates nothing
+	if err := rw.ForceRotate(); err != nil {
+		t.Fatalf("Failed to force rotation: %v", err)
+	}
+	if err := rw.Close(); err != nil {
+		t.Fatalf("Failed to close: %v", err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Fatalf("Expected no directory after close, got %v", err)
+	}
+
+	rw, err = NewRotateWriter(logFile, WithLazyOpen(true))
+	if err != nil {
+		t.Fatalf("Failed to create rotate writer: %v", err)
+	}
+	defer rw.Close()
+	if _, err := rw.Write([]byte("entry\n")); err != nil {
+		t.Fatalf("Failed to write: %v", err)
+	}
+	data, err := os.ReadFile(logFile)
+	if err != nil || string(data) != "entry\n" {
+		t.Errorf("Expected the entry in the log file, got %q (%v)", data, err)
+	}
+}
+
+func TestRotateWriterEagerOpenFails(t *testing.T) {
+	// A regular file where the directory should be makes the path unwritable
+	blocker := filepath.Join(t.TempDir(), "blocker")
+	if err := os.WriteFile(blocker, nil, 0644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	logFile := filepath.Join(blocker, "app.log")
+
+	if _, err := NewRotateWriter(logFile); err == nil || !strings.Contains(err.Error(), "log directory") {
+		t.Errorf("Expected a log directory error from the constructor, got %v", err)
+	}
+
+	// The lazy writer reports the same problem on the first write
+	rw, err := NewRotateWriter(logFile, WithLazyOpen(true))
+	if err != nil {
+		t.Fatalf("Expected the lazy constructor to succeed, got %v", err)
+	}
+	defer rw.Close()
+	if _, err := rw.Write([]byte("entry\n")); err == nil {
+		t.Error("Expected the first write to fail")
+	}
+}
+
+func TestRotateWriterCreateDirs(t *testing.T) {
+	tempDir := t.TempDir()
+	missing := filepath.Join(tempDir, "missing", "app.log")
+
+	_, err := NewRotateWriter(missing, WithCreateDirs(false))
+	if err == nil || !strings.Contains(err.Error(), "not accessible") {
+		t.Errorf("Expected an error for the missing directory, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Dir(missing)); !os.IsNotExist(err) {
+		t.Errorf("Expected the directory not to be created, got %v", err)
+	}
+
+	rw, err := NewRotateWriter(missing, WithCreateDirs(false), WithLazyOpen(true))
+	if err != nil {
+		t.Fatalf("Expected the lazy constructor to succeed, got %v", err)
+	}
+	if _, err := rw.Write([]byte("entry\n")); err == nil {
+		t.Error("Expected the write to fail for the missing directory")
+	}
+	rw.Close()
+
+	// An existing directory is used as it is
+	existing := filepath.Join(tempDir, "app.log")
+	rw, err = NewRotateWriter(existing, WithCreateDirs(false))
+	if err != nil {
+		t.Fatalf("Failed to create rotate writer: %v", err)
+	}
+	defer rw.Close()
+	if _, err := os.Stat(existing); err != nil {
+		t.Errorf("Expected the log file to be created: %v", err)
 	}
 }
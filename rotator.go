@@ -0,0 +1,72 @@
+package dy
+
+import (
+	"errors"
+	"io"
+)
+
+// ErrNoRotator is returned by Rotate when the logger's output cannot rotate
+var ErrNoRotator = errors.New("logger output does not support rotation")
+
+// Rotator is implemented by outputs that can rotate on demand, such as
+// RotateWriter
+type Rotator interface {
+	ForceRotate() error
+}
+
+// Rotator returns the first output in the logger's output chain that can
+// rotate. Writers wrapping other writers are searched through their
+// Unwrap() io.Writer or Unwrap() []io.Writer method, which wrapping writers
+// should implement to be transparent to Rotate.
+func (l *Logger) Rotator() (Rotator, bool) {
+	if l == nil {
+		return nil, false
+	}
+
+	l.mu.Lock()
+	out := l.out
+	l.mu.Unlock()
+
+	return findRotator(out, 0)
+}
+
+// Rotate forces a rotation of the logger's output, returning ErrNoRotator if
+// the output chain holds no Rotator
+func (l *Logger) Rotate() error {
+	rotator, ok := l.Rotator()
+	if !ok {
+		return ErrNoRotator
+	}
+	return rotator.ForceRotate()
+}
+
+// Rotate forces a rotation of the default logger's output
+func Rotate() error {
+	return DefaultLogger.Rotate()
+}
+
+// maxOutputChainDepth bounds the search of an output chain, in case a
+// writer unwraps to itself
+const maxOutputChainDepth = 16
+
+// findRotator searches w and the writers it wraps for a Rotator
+func findRotator(w io.Writer, depth int) (Rotator, bool) {
+	if w == nil || depth > maxOutputChainDepth {
+		return nil, false
+	}
+	if rotator, ok := w.(Rotator); ok {
+		return rotator, true
+	}
+
+	switch u := w.(type) {
+	case interface{ Unwrap() io.Writer }:
+		return findRotator(u.Unwrap(), depth+1)
+	case interface{ Unwrap() []io.Writer }:
+		for _, inner := range u.Unwrap() {
+			if rotator, ok := findRotator(inner, depth+1); ok {
+				return rotator, true
+			}
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,72 @@
+package dy
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"path/filepath"
+	"testing"
+)
+
+// wrappingWriter wraps another writer, as buffering or async outputs do
+type wrappingWriter struct{ inner io.Writer }
+
+func (w *wrappingWriter) Write(p []byte) (int, error) { return w.inner.Write(p) }
+func (w *wrappingWriter) Unwrap() io.Writer           { return w.inner }
+
+// fanOutWriter writes to several writers
+type fanOutWriter struct{ outputs []io.Writer }
+
+func (w *fanOutWriter) Write(p []byte) (int, error) { return io.MultiWriter(w.outputs...).Write(p) }
+func (w *fanOutWriter) Unwrap() []io.Writer         { return w.outputs }
+
+func TestLoggerRotate(t *testing.T) {
+	dir := t.TempDir()
+	l := New(WithRotateWriter(filepath.Join(dir, "app.log"), WithCompress(false)))
+	defer l.Close()
+
+	l.Info("before rotation")
+	if err := l.Rotate(); err != nil {
+		t.Fatalf("Rotate failed: %v", err)
+	}
+	l.GetOutput().(*RotateWriter).Wait()
+
+	backups, _ := filepath.Glob(filepath.Join(dir, "app.log.*"))
+	if len(backups) != 1 {
+		t.Errorf("Expected one backup after Rotate, found %v", backups)
+	}
+}
+
+func TestLoggerRotatorThroughWrappers(t *testing.T) {
+	dir := t.TempDir()
+	tee, err := NewRotateTeeWriter(filepath.Join(dir, "app.log"), &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("Failed to create tee writer: %v", err)
+	}
+	defer tee.Close()
+
+	l := New(WithOutput(&wrappingWriter{inner: tee}))
+	rotator, ok := l.Rotator()
+	if !ok || rotator != Rotator(tee) {
+		t.Fatalf("Expected the tee writer as rotator, got %v, %v", rotator, ok)
+	}
+	if err := l.Rotate(); err != nil {
+		t.Errorf("Rotate failed: %v", err)
+	}
+
+	// Writers wrapping several writers are searched in order
+	fanOut := &fanOutWriter{outputs: []io.Writer{&bytes.Buffer{}, tee.File()}}
+	if rotator, ok := findRotator(fanOut, 0); !ok || rotator != Rotator(tee.File()) {
+		t.Errorf("Expected the file writer behind the fan-out, got %v", rotator)
+	}
+}
+
+func TestLoggerRotateWithoutRotator(t *testing.T) {
+	l := New(WithOutput(&bytes.Buffer{}))
+	if err := l.Rotate(); !errors.Is(err, ErrNoRotator) {
+		t.Errorf("Expected ErrNoRotator, got %v", err)
+	}
+	if _, ok := l.Rotator(); ok {
+		t.Error("Expected no rotator for a buffer output")
+	}
+}
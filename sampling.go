@@ -0,0 +1,109 @@
+package dy
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+// sampler limits how often identical entries are logged within a time window
+type sampler struct {
+	mu         sync.Mutex
+	first      int
+	thereafter int
+	tick       time.Duration
+	counts     map[string]int
+	resetAt    time.Time
+}
+
+// WithSampling limits repeated entries: within each tick, the first entries
+// with the same level and message format are logged and after that only every
+// thereafter-th one. A thereafter of 0 drops everything past first. DPanic and
+// Fatal entries are never sampled.
+func WithSampling(first, thereafter int, tick time.Duration) Option {
+	return func(l *Logger) {
+		l.sampler = &sampler{
+			first:      first,
+			thereafter: thereafter,
+			tick:       tick,
+			counts:     make(map[string]int),
+		}
+	}
+}
+
+// allow reports whether an entry with the given level and format should be logged
+func (s *sampler) allow(level Level, format string) bool {
+	if s == nil || level.Severity() >= DPanicLevel.Severity() {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if !now.Before(s.resetAt) {
+		s.counts = make(map[string]int)
+		s.resetAt = now.Add(s.tick)
+	}
+
+	key := level.String() + "\x00" + format
+	s.counts[key]++
+	n := s.counts[key]
+
+	if n <= s.first {
+		return true
+	}
+	return s.thereafter > 0 && (n-s.first)%s.thereafter == 0
+}
+
+// traceSampler keeps or drops all entries of a trace together
+type traceSampler struct {
+	threshold uint32 // Entries are kept when the trace ID hash is below this
+	field     string // Context field holding the trace ID
+}
+
+// EnableDistributedSampling creates a new logger that keeps only a
+// samplingRate fraction of traces. The decision is based on an FNV hash of the
+// traceIDField context value, so every entry of a trace is either kept or
+// dropped, in this process and in any other using the same rate. Entries
+// without a trace ID and DPanic or Fatal entries are always kept; a rate of
+// 1.0 or more disables sampling.
+func (l *Logger) EnableDistributedSampling(samplingRate float64, traceIDField string) *Logger {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	child := l.newChild()
+	child.context = l.context.Clone()
+	child.traceSampler = nil
+	if samplingRate < 1 {
+		if samplingRate < 0 {
+			samplingRate = 0
+		}
+		child.traceSampler = &traceSampler{
+			threshold: uint32(samplingRate * (1 << 32)),
+			field:     traceIDField,
+		}
+	}
+	return child
+}
+
+// allow reports whether an entry with the given fields should be logged
+func (s *traceSampler) allow(level Level, fields []ContextField) bool {
+	if s == nil || level.Severity() >= DPanicLevel.Severity() {
+		return true
+	}
+
+	for _, field := range fields {
+		if field.Key == s.field {
+			h := fnv.New32a()
+			fmt.Fprint(h, field.Value)
+			return h.Sum32() < s.threshold
+		}
+	}
+	return true
+}
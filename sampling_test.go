@@ -0,0 +1,56 @@
+package dy
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestEnableDistributedSampling(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false)).EnableDistributedSampling(0.5, "trace_id")
+
+	kept := 0
+	for i := 0; i < 200; i++ {
+		traceLogger := l.WithContext("trace_id", fmt.Sprintf("trace-%d", i))
+
+		buf.Reset()
+		for j := 0; j < 3; j++ {
+			traceLogger.Info("step %d", j)
+		}
+
+		// A trace is either complete or absent
+		switch n := strings.Count(buf.String(), "\n"); n {
+		case 0:
+		case 3:
+			kept++
+		default:
+			t.Fatalf("Expected all or none of a trace's entries, got %d", n)
+		}
+	}
+	if kept < 60 || kept > 140 {
+		t.Errorf("Expected roughly half the traces kept, got %d of 200", kept)
+	}
+
+	buf.Reset()
+	l.Info("no trace id")
+	if buf.Len() == 0 {
+		t.Error("Expected entries without a trace ID to be kept")
+	}
+}
+
+func TestDistributedSamplingRates(t *testing.T) {
+	var buf bytes.Buffer
+	base := New(WithOutput(&buf), WithTimestamp(false)).WithContext("trace_id", "abc")
+
+	base.EnableDistributedSampling(0, "trace_id").Info("dropped")
+	if buf.Len() != 0 {
+		t.Errorf("Expected rate 0 to drop every trace, got: %s", buf.String())
+	}
+
+	base.EnableDistributedSampling(0, "trace_id").EnableDistributedSampling(1.0, "trace_id").Info("kept")
+	if !strings.Contains(buf.String(), "kept") {
+		t.Errorf("Expected rate 1.0 to disable sampling, got: %s", buf.String())
+	}
+}
@@ -0,0 +1,62 @@
+package dy
+
+import (
+	"errors"
+	"sync"
+)
+
+// sentinel is an error registered with RegisterSentinels
+type sentinel struct {
+	name string
+	err  error
+}
+
+// sentinelRegistry holds the sentinels checked by WithError
+var sentinelRegistry struct {
+	sync.RWMutex
+	sentinels []sentinel
+}
+
+// RegisterSentinels registers sentinel errors that WithError checks with
+// errors.Is. The names of matching sentinels are listed in the "matches"
+// field of the logged error, using each sentinel's message as its name.
+// Use RegisterNamedSentinel to choose the name, e.g. "ErrNotFound".
+func RegisterSentinels(sentinels ...error) {
+	for _, err := range sentinels {
+		if err != nil {
+			RegisterNamedSentinel(err.Error(), err)
+		}
+	}
+}
+
+// RegisterNamedSentinel registers a sentinel error reported under name
+func RegisterNamedSentinel(name string, err error) {
+	if err == nil {
+		return
+	}
+
+	sentinelRegistry.Lock()
+	defer sentinelRegistry.Unlock()
+
+	for i, s := range sentinelRegistry.sentinels {
+		if s.err == err {
+			sentinelRegistry.sentinels[i].name = name
+			return
+		}
+	}
+	sentinelRegistry.sentinels = append(sentinelRegistry.sentinels, sentinel{name: name, err: err})
+}
+
+// matchSentinels returns the names of the registered sentinels err matches
+func matchSentinels(err error) []string {
+	sentinelRegistry.RLock()
+	defer sentinelRegistry.RUnlock()
+
+	var matches []string
+	for _, s := range sentinelRegistry.sentinels {
+		if errors.Is(err, s.err) {
+			matches = append(matches, s.name)
+		}
+	}
+	return matches
+}
@@ -0,0 +1,36 @@
+package dy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestRegisterSentinels(t *testing.T) {
+	errNotFound := errors.New("not found")
+	errDenied := errors.New("permission denied")
+	errOther := errors.New("other")
+	RegisterNamedSentinel("ErrNotFound", errNotFound)
+	RegisterSentinels(errDenied)
+
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false), WithJSONFormat(true))
+
+	err := fmt.Errorf("load user: %w", errNotFound)
+	l.WithError(err).Error("lookup failed")
+	if !strings.Contains(buf.String(), `"matches":["ErrNotFound"]`) {
+		t.Errorf("Expected matching sentinel in output, got: %s", buf.String())
+	}
+
+	buf.Reset()
+	l.WithError(errOther).Error("unrelated")
+	if strings.Contains(buf.String(), `"matches"`) {
+		t.Errorf("Expected no matches for unregistered error, got: %s", buf.String())
+	}
+
+	if got := matchSentinels(errDenied); len(got) != 1 || got[0] != "permission denied" {
+		t.Errorf("Expected sentinel named after its message, got %v", got)
+	}
+}
@@ -0,0 +1,91 @@
+package dy
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// WithSourceSnippets attaches the source line of the top n application frames
+// to error stack traces. It is meant for local development: files that cannot
+// be read (stripped binaries, containers) are skipped silently, and snippets
+// are left out of JSON output unless WithSourceSnippetsInJSON is also set.
+func WithSourceSnippets(n int) Option {
+	return func(l *Logger) {
+		if n < 0 {
+			n = 0
+		}
+		l.stack.snippets = n
+	}
+}
+
+// WithSourceContextLines includes up to n lines before and after each source snippet
+func WithSourceContextLines(n int) Option {
+	return func(l *Logger) {
+		if n < 0 {
+			n = 0
+		}
+		l.stack.snippetContext = n
+	}
+}
+
+// WithSourceSnippetsInJSON allows source snippets in JSON output
+func WithSourceSnippetsInJSON(enable bool) Option {
+	return func(l *Logger) {
+		l.stack.snippetsInJSON = enable
+	}
+}
+
+// sourceSnippet is a cached source line with its surrounding context
+type sourceSnippet struct {
+	line    string
+	context []string
+}
+
+// snippetCache caches snippets per file:line and context size
+var snippetCache sync.Map
+
+// readSnippet returns the trimmed source line at file:line along with up to
+// contextLines lines around it. It returns false if the file cannot be read.
+func readSnippet(file string, line, contextLines int) (sourceSnippet, bool) {
+	key := fmt.Sprintf("%s:%d:%d", file, line, contextLines)
+	if cached, ok := snippetCache.Load(key); ok {
+		snippet, _ := cached.(*sourceSnippet)
+		if snippet == nil {
+			return sourceSnippet{}, false
+		}
+		return *snippet, true
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		// Remember misses too so missing files are not retried on every error
+		snippetCache.Store(key, (*sourceSnippet)(nil))
+		return sourceSnippet{}, false
+	}
+
+	lines := strings.Split(string(data), "\n")
+	if line < 1 || line > len(lines) {
+		snippetCache.Store(key, (*sourceSnippet)(nil))
+		return sourceSnippet{}, false
+	}
+
+	snippet := &sourceSnippet{line: strings.TrimSpace(lines[line-1])}
+	if contextLines > 0 {
+		start := line - 1 - contextLines
+		if start < 0 {
+			start = 0
+		}
+		end := line + contextLines
+		if end > len(lines) {
+			end = len(lines)
+		}
+		for i := start; i < end; i++ {
+			snippet.context = append(snippet.context, fmt.Sprintf("%d: %s", i+1, strings.TrimRight(lines[i], " \t\r")))
+		}
+	}
+
+	snippetCache.Store(key, snippet)
+	return *snippet, true
+}
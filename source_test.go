@@ -0,0 +1,65 @@
+package dy
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSourceSnippets(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false), WithSourceSnippets(1))
+
+	l.WithError(errors.New("boom")).Error("failed") // snippet marker
+
+	output := buf.String()
+	if !strings.Contains(output, "> l.WithError(errors.New(\"boom\")).Error(\"failed\") // snippet marker") {
+		t.Errorf("Expected source snippet in output, got: %s", output)
+	}
+}
+
+func TestSourceSnippetsContext(t *testing.T) {
+	l := New(WithOutput(&bytes.Buffer{}), WithSourceSnippets(1), WithSourceContextLines(2))
+
+	stack := errorStack(t, l.WithError(errors.New("boom")))
+	if len(stack[0].SourceContext) != 5 {
+		t.Errorf("Expected 5 lines of source context, got: %v", stack[0].SourceContext)
+	}
+	if len(stack) > 1 && stack[1].Source != "" {
+		t.Errorf("Expected only the top frame to have a snippet, got: %s", stack[1].Source)
+	}
+}
+
+func TestSourceSnippetsDisabledInJSON(t *testing.T) {
+	l := New(WithOutput(&bytes.Buffer{}), WithJSONFormat(true), WithSourceSnippets(1))
+	if stack := errorStack(t, l.WithError(errors.New("boom"))); stack[0].Source != "" {
+		t.Errorf("Expected no snippet in JSON mode, got: %s", stack[0].Source)
+	}
+
+	l = New(WithOutput(&bytes.Buffer{}), WithJSONFormat(true), WithSourceSnippets(1), WithSourceSnippetsInJSON(true))
+	if stack := errorStack(t, l.WithError(errors.New("boom"))); stack[0].Source == "" {
+		t.Errorf("Expected snippet when explicitly enabled for JSON")
+	}
+}
+
+func TestSourceSnippetsDisabledInJSONAutoStack(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithJSONFormat(true), WithSourceSnippets(2)).WithAutoStack(ErrorLevel)
+
+	l.Error("failed")
+
+	output := buf.String()
+	if !strings.Contains(output, `"stack"`) {
+		t.Fatalf("Expected an auto stack in output, got: %s", output)
+	}
+	if strings.Contains(output, `"source"`) {
+		t.Errorf("Expected no snippet in JSON mode, got: %s", output)
+	}
+}
+
+func TestReadSnippetMissingFile(t *testing.T) {
+	if _, ok := readSnippet("/nonexistent/file.go", 10, 2); ok {
+		t.Errorf("Expected missing file to be skipped")
+	}
+}
@@ -0,0 +1,54 @@
+package dy
+
+import (
+	"errors"
+	"fmt"
+)
+
+// maxSQLQueryLen is the number of runes of a query WithSQLContext logs
+const maxSQLQueryLen = 512
+
+// WithSQLArgsMasked sets whether WithSQLContext leaves the values of query
+// arguments out of the context. Arguments often hold personal data, so they
+// are masked unless this is set to false, in which case they are logged as
+// "sql.args".
+func WithSQLArgsMasked(mask bool) Option {
+	return func(l *Logger) {
+		l.sqlArgs = !mask
+	}
+}
+
+// WithSQLContext creates a new logger with the context of a failed database
+// query: the query as "sql.query", cut to 512 characters, the number of
+// arguments as "sql.args_count" and the type of err as "sql.error_type".
+// Errors with a SQLState method, as returned by PostgreSQL drivers, add
+// "sql.state", and errors with a Number method, as returned by the MySQL
+// driver, add "sql.error_number". Argument values are only logged when
+// WithSQLArgsMasked(false) is set.
+func (l *Logger) WithSQLContext(err error, query string, args []interface{}) *Logger {
+	if l == nil {
+		return nil
+	}
+
+	query, _ = truncateString(query, maxSQLQueryLen)
+	fields := map[string]interface{}{
+		"sql.query":      query,
+		"sql.args_count": len(args),
+	}
+	if err != nil {
+		fields["sql.error_type"] = fmt.Sprintf("%T", err)
+
+		var pgErr interface{ SQLState() string }
+		if errors.As(err, &pgErr) {
+			fields["sql.state"] = pgErr.SQLState()
+		}
+		var mysqlErr interface{ Number() uint16 }
+		if errors.As(err, &mysqlErr) {
+			fields["sql.error_number"] = mysqlErr.Number()
+		}
+	}
+	if l.sqlArgs && len(args) > 0 {
+		fields["sql.args"] = append([]interface{}(nil), args...)
+	}
+	return l.WithFields(fields)
+}
@@ -0,0 +1,71 @@
+package dy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// pgError mimics the errors of PostgreSQL drivers
+type pgError struct{ code string }
+
+func (e *pgError) Error() string    { return "pq: duplicate key value" }
+func (e *pgError) SQLState() string { return e.code }
+
+// mysqlError mimics the errors of the MySQL driver
+type mysqlError struct{ number uint16 }
+
+func (e *mysqlError) Error() string  { return "Error 1062: Duplicate entry" }
+func (e *mysqlError) Number() uint16 { return e.number }
+
+func TestWithSQLContext(t *testing.T) {
+	l := New(WithOutput(&bytes.Buffer{}))
+	err := fmt.Errorf("insert user: %w", &pgError{code: "23505"})
+	child := l.WithSQLContext(err, "INSERT INTO users (email) VALUES ($1)", []interface{}{"a@example.com"})
+
+	want := map[string]interface{}{
+		"sql.query":      "INSERT INTO users (email) VALUES ($1)",
+		"sql.args_count": 1,
+		"sql.error_type": "*fmt.wrapError",
+		"sql.state":      "23505",
+	}
+	for key, value := range want {
+		if got, _ := child.ContextValue(key); got != value {
+			t.Errorf("Expected %s = %v, got %v", key, value, got)
+		}
+	}
+	if _, ok := child.ContextValue("sql.args"); ok {
+		t.Error("Expected argument values to be masked by default")
+	}
+
+	child = l.WithSQLContext(&mysqlError{number: 1062}, "INSERT INTO users (email) VALUES (?)", nil)
+	if got, _ := child.ContextValue("sql.error_number"); got != uint16(1062) {
+		t.Errorf("Expected MySQL error number 1062, got %v", got)
+	}
+	if _, ok := child.ContextValue("sql.state"); ok {
+		t.Error("Expected no SQL state for a MySQL error")
+	}
+}
+
+func TestWithSQLContextTruncatesQuery(t *testing.T) {
+	l := New(WithOutput(&bytes.Buffer{}))
+	query := "SELECT " + strings.Repeat("column, ", 100) + "id FROM t"
+	child := l.WithSQLContext(errors.New("timeout"), query, nil)
+
+	got, _ := child.ContextValue("sql.query")
+	if s := got.(string); len([]rune(s)) != maxSQLQueryLen+len(truncationSuffix) || !strings.HasPrefix(query, strings.TrimSuffix(s, truncationSuffix)) {
+		t.Errorf("Expected the query cut to %d characters, got %q", maxSQLQueryLen, s)
+	}
+}
+
+func TestWithSQLArgsMasked(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithSQLArgsMasked(false))
+	l.WithSQLContext(errors.New("failed"), "SELECT * FROM users WHERE id = $1", []interface{}{42}).Error("query failed")
+
+	if !strings.Contains(buf.String(), "sql.args: [42]") {
+		t.Errorf("Expected argument values when unmasked, got: %s", buf.String())
+	}
+}
@@ -0,0 +1,55 @@
+package dy
+
+import (
+	"log"
+	"strings"
+	"sync"
+)
+
+// stdLogWriter forwards lines written by the standard library logger to a Logger
+type stdLogWriter struct {
+	logger *Logger
+	level  Level
+}
+
+// Write logs every line in p as a separate entry
+func (w *stdLogWriter) Write(p []byte) (int, error) {
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		if line == "" {
+			continue
+		}
+		w.logger.Log(w.level, "%s", line)
+	}
+	return len(p), nil
+}
+
+// CaptureStdLog redirects the standard library's global logger (log.Printf
+// and friends, often used by dependencies) to l at the given level, with a
+// "source: stdlog" context field. The date and time flags of the standard
+// logger are cleared while captured since dy adds its own timestamp. The
+// returned function restores the previous output and flags; calling it more
+// than once has no effect. Captures may be nested as long as they are
+// restored in reverse order.
+//
+// Example usage:
+//
+//	restore := dy.CaptureStdLog(logger, dy.InfoLevel)
+//	defer restore()
+func CaptureStdLog(l *Logger, level Level) (restore func()) {
+	prevOut := log.Writer()
+	prevFlags := log.Flags()
+
+	log.SetOutput(&stdLogWriter{
+		logger: l.WithContext("source", "stdlog"),
+		level:  level,
+	})
+	log.SetFlags(prevFlags &^ (log.Ldate | log.Ltime | log.Lmicroseconds | log.LUTC))
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			log.SetOutput(prevOut)
+			log.SetFlags(prevFlags)
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package dy
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestCaptureStdLog(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithJSONFormat(true), WithTimestamp(false))
+
+	prevFlags := log.Flags()
+	restore := CaptureStdLog(l, WarnLevel)
+
+	// Third-party style logging
+	log.Println("connection pool exhausted")
+
+	var entry LogEntry
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to parse JSON output %q: %v", buf.String(), err)
+	}
+	if entry.Level != "WARN" || entry.Message != "connection pool exhausted" {
+		t.Errorf("Unexpected entry: %+v", entry)
+	}
+	if entry.Context["source"] != "stdlog" {
+		t.Errorf("Expected source field, got: %v", entry.Context)
+	}
+
+	restore()
+	restore() // idempotent
+
+	if log.Writer() != os.Stderr {
+		t.Errorf("Expected original output to be restored")
+	}
+	if log.Flags() != prevFlags {
+		t.Errorf("Expected original flags to be restored, got %d", log.Flags())
+	}
+}
+
+func TestCaptureStdLogNested(t *testing.T) {
+	var outer, inner bytes.Buffer
+	restoreOuter := CaptureStdLog(New(WithOutput(&outer), WithTimestamp(false)), InfoLevel)
+	defer restoreOuter()
+
+	restoreInner := CaptureStdLog(New(WithOutput(&inner), WithTimestamp(false)), InfoLevel)
+	log.Printf("to inner")
+	restoreInner()
+	log.Printf("to outer")
+
+	if !strings.Contains(inner.String(), "to inner") || strings.Contains(inner.String(), "to outer") {
+		t.Errorf("Unexpected inner output: %q", inner.String())
+	}
+	if !strings.Contains(outer.String(), "to outer") || strings.Contains(outer.String(), "to inner") {
+		t.Errorf("Unexpected outer output: %q", outer.String())
+	}
+}
+
+func TestCaptureStdLogKeepsMessageTimestamps(t *testing.T) {
+	var buf bytes.Buffer
+	restore := CaptureStdLog(New(WithOutput(&buf), WithTimestamp(false)), InfoLevel)
+	defer restore()
+
+	// Only the flags' timestamp is left out, not one that is part of the message
+	log.Println("2024/01/02 10:00:00 job ran")
+
+	expected := "[INFO] 2024/01/02 10:00:00 job ran {source: stdlog}\n"
+	if got := buf.String(); got != expected {
+		t.Errorf("CaptureStdLog output = %q, want %q", got, expected)
+	}
+}
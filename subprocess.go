@@ -0,0 +1,91 @@
+package dy
+
+import (
+	"errors"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// maxSubprocessStderr is the number of trailing bytes of a subprocess's
+// stderr kept by WithSubprocessContext
+const maxSubprocessStderr = 64 * 1024
+
+// WithCmdContext creates a new logger with cmd's command line as the
+// "subprocess.cmd" context field
+func (l *Logger) WithCmdContext(cmd *exec.Cmd) *Logger {
+	if cmd == nil {
+		return l
+	}
+	return l.WithContext("subprocess.cmd", cmd.String())
+}
+
+// WithSubprocessContext returns a function that runs cmd, capturing its
+// stderr, and logs a "subprocess failed" error if it fails. The entry holds
+// the command line as "subprocess.cmd", the last 64 KiB of stderr as
+// "subprocess.stderr", the exit code as "subprocess.exit_code" when the
+// command ran, and the error. A Stderr already set on cmd still receives
+// the output. The function returns the error of cmd.Run.
+//
+// Example usage:
+//
+//	cmd := exec.Command("terraform", "apply", "-auto-approve")
+//	if err := logger.WithSubprocessContext(cmd)(); err != nil {
+//	    return err
+//	}
+func (l *Logger) WithSubprocessContext(cmd *exec.Cmd) func() error {
+	if l == nil {
+		return cmd.Run
+	}
+
+	stderr := &tailBuffer{max: maxSubprocessStderr}
+	if cmd.Stderr != nil {
+		cmd.Stderr = io.MultiWriter(cmd.Stderr, stderr)
+	} else {
+		cmd.Stderr = stderr
+	}
+
+	return func() error {
+		err := cmd.Run()
+		if err == nil {
+			return nil
+		}
+
+		fields := map[string]interface{}{
+			"subprocess.stderr": strings.TrimRight(stderr.String(), "\n"),
+		}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			fields["subprocess.exit_code"] = exitErr.ExitCode()
+		}
+		l.WithCmdContext(cmd).WithFields(fields).WithError(err).Error("subprocess failed")
+		return err
+	}
+}
+
+// tailBuffer keeps the last max bytes written to it
+type tailBuffer struct {
+	mu  sync.Mutex
+	max int
+	buf []byte
+}
+
+// Write appends p, dropping the oldest bytes beyond the limit
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.buf = append(b.buf, p...)
+	if extra := len(b.buf) - b.max; extra > 0 {
+		b.buf = append(b.buf[:0], b.buf[extra:]...)
+	}
+	return len(p), nil
+}
+
+// String returns the bytes kept
+func (b *tailBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return string(b.buf)
+}
@@ -0,0 +1,64 @@
+package dy
+
+import (
+	"bytes"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestWithSubprocessContext(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh is not available")
+	}
+
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false))
+
+	var own bytes.Buffer
+	cmd := exec.Command(sh, "-c", "echo 'disk quota exceeded' >&2; exit 3")
+	cmd.Stderr = &own
+	if err := l.WithSubprocessContext(cmd)(); err == nil {
+		t.Fatal("Expected the command to fail")
+	}
+
+	output := buf.String()
+	for _, want := range []string{"subprocess failed", "subprocess.stderr: disk quota exceeded", "subprocess.exit_code: 3", "subprocess.cmd: " + sh + " -c"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output, got: %s", want, output)
+		}
+	}
+	if own.String() != "disk quota exceeded\n" {
+		t.Errorf("Expected the existing Stderr to still get the output, got %q", own.String())
+	}
+
+	// Successful commands are not logged
+	buf.Reset()
+	if err := l.WithSubprocessContext(exec.Command(sh, "-c", "echo warning >&2"))(); err != nil {
+		t.Fatalf("Expected the command to succeed, got %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Expected no entry for a successful command, got: %s", buf.String())
+	}
+}
+
+func TestWithSubprocessContextNotFound(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false))
+	if err := l.WithSubprocessContext(exec.Command("dy-no-such-command"))(); err == nil {
+		t.Fatal("Expected the command to fail")
+	}
+	if !strings.Contains(buf.String(), "subprocess failed") || strings.Contains(buf.String(), "exit_code") {
+		t.Errorf("Expected an entry without exit code, got: %s", buf.String())
+	}
+}
+
+func TestTailBuffer(t *testing.T) {
+	b := &tailBuffer{max: 5}
+	b.Write([]byte("abc"))
+	b.Write([]byte("defgh"))
+	if got := b.String(); got != "defgh" {
+		t.Errorf("Expected the last 5 bytes, got %q", got)
+	}
+}
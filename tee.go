@@ -0,0 +1,40 @@
+package dy
+
+// ContextTee is called with each context field added to a logger
+type ContextTee func(key string, value interface{})
+
+// WithContextTee creates a new logger that calls fn for every context field
+// added through WithContext, WithFields or With, by it or by loggers derived
+// from it, for example to copy the field onto the active tracing span. Tees
+// run in order of registration, after the field is added and in the goroutine
+// adding it, so they must be fast. A nil fn is ignored.
+func (l *Logger) WithContextTee(fn func(key string, value interface{})) *Logger {
+	if l == nil {
+		return nil
+	}
+
+	if fn == nil {
+		return l
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	child := l.newChild()
+	child.context = l.context.Clone()
+	// Copy the tees so that siblings never share an appended array
+	child.tees = append(l.tees[:len(l.tees):len(l.tees)], fn)
+	return child
+}
+
+// tee passes the added fields to the logger's tees
+func (l *Logger) tee(fields ...ContextField) {
+	if len(l.tees) == 0 {
+		return
+	}
+	for _, field := range fields {
+		for _, fn := range l.tees {
+			fn(field.Key, field.Value)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package dy
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestWithContextTee(t *testing.T) {
+	var calls []string
+	first := func(key string, value interface{}) {
+		calls = append(calls, fmt.Sprintf("first %s=%v", key, value))
+	}
+	second := func(key string, value interface{}) {
+		calls = append(calls, fmt.Sprintf("second %s=%v", key, value))
+	}
+
+	base := New(WithOutput(&bytes.Buffer{})).WithContext("service", "api")
+	l := base.WithContextTee(first).WithContextTee(second)
+	if len(calls) != 0 {
+		t.Fatalf("Expected no calls for existing context, got %v", calls)
+	}
+
+	l = l.WithContext("request_id", "req-1")
+	l.With(ContextField{Key: "user_id", Value: 42})
+	l.WithFields(map[string]interface{}{"step": "validate"})
+
+	want := []string{
+		"first request_id=req-1", "second request_id=req-1",
+		"first user_id=42", "second user_id=42",
+		"first step=validate", "second step=validate",
+	}
+	if fmt.Sprint(calls) != fmt.Sprint(want) {
+		t.Errorf("Expected calls %v, got %v", want, calls)
+	}
+
+	// The parent is not teed
+	calls = nil
+	base.WithContext("other", 1)
+	if len(calls) != 0 {
+		t.Errorf("Expected no calls from the parent, got %v", calls)
+	}
+}
+
+func TestWithContextTeeSiblings(t *testing.T) {
+	var a, b int
+	base := New(WithOutput(&bytes.Buffer{})).WithContextTee(func(string, interface{}) {})
+	left := base.WithContextTee(func(string, interface{}) { a++ })
+	right := base.WithContextTee(func(string, interface{}) { b++ })
+
+	left.WithContext("k", "v")
+	right.WithContext("k", "v")
+	right.WithContext("k", "v")
+	if a != 1 || b != 2 {
+		t.Errorf("Expected sibling tees to stay apart, got %d and %d calls", a, b)
+	}
+}
+
+func TestWithContextTeeCanUseLogger(t *testing.T) {
+	var buf bytes.Buffer
+	var l *Logger
+	l = New(WithOutput(&buf)).WithContextTee(func(key string, value interface{}) {
+		// Tees run without the logger's lock held
+		l.Info("added %s", key)
+	})
+	l.WithContext("k", "v")
+
+	if !bytes.Contains(buf.Bytes(), []byte("added k")) {
+		t.Errorf("Expected the tee to log, got: %s", buf.String())
+	}
+}
@@ -0,0 +1,32 @@
+package dy
+
+import "time"
+
+// WithTime creates a new logger with t formatted as RFC3339Nano under key,
+// e.g. "2024-01-02T15:04:05.123456789Z", instead of the noisy default
+// rendering of a time.Time field. The value is a plain string in both the
+// text and JSON formats, so WithTimeEncoding does not apply to it.
+func (l *Logger) WithTime(key string, t time.Time) *Logger {
+	return l.WithTimeLayout(key, t, time.RFC3339Nano)
+}
+
+// WithTimeLayout is like WithTime but formats t with layout, or RFC3339Nano
+// when layout is empty. Times follow WithUTC.
+func (l *Logger) WithTimeLayout(key string, t time.Time, layout string) *Logger {
+	if l == nil {
+		return nil
+	}
+
+	if layout == "" {
+		layout = time.RFC3339Nano
+	}
+
+	l.mu.Lock()
+	utc := l.utc
+	l.mu.Unlock()
+	if utc {
+		t = t.UTC()
+	}
+
+	return l.WithContext(key, t.Format(layout))
+}
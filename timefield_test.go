@@ -0,0 +1,52 @@
+package dy
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithTime(t *testing.T) {
+	created := time.Date(2024, 1, 2, 15, 4, 5, 123456789, time.UTC)
+
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithJSONFormat(true)).WithTime("created_at", created)
+	l.Info("order placed")
+
+	var entry struct {
+		Context map[string]interface{} `json:"context"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to parse JSON output %q: %v", buf.String(), err)
+	}
+	if got := entry.Context["created_at"]; got != "2024-01-02T15:04:05.123456789Z" {
+		t.Errorf("Expected an RFC3339Nano string, got %#v", got)
+	}
+
+	buf.Reset()
+	l.DisableJSONFormat()
+	l.Info("order placed")
+	if !strings.Contains(buf.String(), "created_at: 2024-01-02T15:04:05.123456789Z") {
+		t.Errorf("Expected the same value in text mode, got %q", buf.String())
+	}
+}
+
+func TestWithTimeLayout(t *testing.T) {
+	local := time.FixedZone("CET", 3600)
+	created := time.Date(2024, 1, 2, 15, 4, 5, 0, local)
+
+	l := New(WithOutput(&syncBuffer{}))
+	if got, _ := l.WithTimeLayout("day", created, time.DateOnly).ContextValue("day"); got != "2024-01-02" {
+		t.Errorf("Expected the custom layout, got %v", got)
+	}
+	if got, _ := l.WithTimeLayout("at", created, "").ContextValue("at"); got != "2024-01-02T15:04:05+01:00" {
+		t.Errorf("Expected RFC3339Nano for an empty layout, got %v", got)
+	}
+
+	utc := New(WithOutput(&syncBuffer{}), WithUTC(true))
+	if got, _ := utc.WithTime("at", created).ContextValue("at"); got != "2024-01-02T14:04:05Z" {
+		t.Errorf("Expected the time in UTC, got %v", got)
+	}
+}
@@ -0,0 +1,26 @@
+package dy
+
+import "time"
+
+// Trace starts timing an operation and returns a function that finishes it.
+// finish(nil) logs "<name> completed" at DebugLevel and finish(err) logs
+// "<name> failed" at ErrorLevel with the error attached; both entries carry
+// the elapsed time in milliseconds as elapsed_ms:
+//
+//	finish := log.Trace("load user")
+//	user, err := store.Load(id)
+//	finish(err)
+func (l *Logger) Trace(name string) (finish func(err error)) {
+	start := time.Now()
+
+	return func(err error) {
+		elapsed := time.Since(start)
+		entryLogger := l.WithContext("elapsed_ms", float64(elapsed)/float64(time.Millisecond))
+
+		if err != nil {
+			entryLogger.WithError(err).log(ErrorLevel, "%s failed", name)
+			return
+		}
+		entryLogger.log(DebugLevel, "%s completed", name)
+	}
+}
@@ -0,0 +1,40 @@
+package dy
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTrace(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false), WithLevel(DebugLevel), WithCallerInfo(true))
+
+	finish := l.Trace("load user")
+	time.Sleep(2 * time.Millisecond)
+	finish(nil)
+
+	output := buf.String()
+	if !strings.HasPrefix(output, "[DEBUG]") || !strings.Contains(output, "load user completed") {
+		t.Errorf("Expected a DEBUG completion entry, got: %s", output)
+	}
+	if !strings.Contains(output, "[timing_test.go:") {
+		t.Errorf("Expected caller to be the finish call site, got: %s", output)
+	}
+	if !strings.Contains(output, "elapsed_ms: ") {
+		t.Errorf("Expected elapsed_ms field, got: %s", output)
+	}
+
+	buf.Reset()
+	l.Trace("save user")(errors.New("disk full"))
+
+	output = buf.String()
+	if !strings.HasPrefix(output, "[ERROR]") || !strings.Contains(output, "save user failed") {
+		t.Errorf("Expected an ERROR failure entry, got: %s", output)
+	}
+	if !strings.Contains(output, "Error: disk full") || !strings.Contains(output, "elapsed_ms: ") {
+		t.Errorf("Expected error details and elapsed_ms, got: %s", output)
+	}
+}
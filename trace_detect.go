@@ -0,0 +1,165 @@
+package dy
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// TraceContextDetector extracts the trace and span id of the tracing backend
+// it knows about from ctx, reporting false when ctx carries none
+type TraceContextDetector func(ctx context.Context) (traceID, spanID string, ok bool)
+
+var (
+	traceDetectorsMu sync.RWMutex
+	traceDetectors   []TraceContextDetector
+)
+
+// RegisterTraceContextDetector adds a detector consulted by
+// WithContextFromTrace. Registered detectors run in registration order and
+// before the built-in ones reading headers attached with
+// ContextWithTraceHeaders, so a tracing SDK can be supported without the
+// package importing it:
+//
+//	dy.RegisterTraceContextDetector(func(ctx context.Context) (string, string, bool) {
+//	    sc := trace.SpanContextFromContext(ctx)
+//	    return sc.TraceID().String(), sc.SpanID().String(), sc.IsValid()
+//	})
+func RegisterTraceContextDetector(fn TraceContextDetector) {
+	if fn == nil {
+		return
+	}
+	traceDetectorsMu.Lock()
+	defer traceDetectorsMu.Unlock()
+	traceDetectors = append(traceDetectors, fn)
+}
+
+// traceHeadersKey is the context key of the headers attached by
+// ContextWithTraceHeaders
+type traceHeadersKey struct{}
+
+// ContextWithTraceHeaders returns a copy of ctx carrying the trace headers
+// of an incoming request, for the built-in detectors of WithContextFromTrace
+func ContextWithTraceHeaders(ctx context.Context, headers http.Header) context.Context {
+	return context.WithValue(ctx, traceHeadersKey{}, headers)
+}
+
+// builtinTraceDetectors read the headers attached by ContextWithTraceHeaders
+// in priority order: W3C traceparent, B3 single header, B3 multi header and
+// Datadog
+var builtinTraceDetectors = []func(http.Header) (traceID, spanID string, ok bool){
+	detectTraceparent,
+	detectB3Single,
+	detectB3Multi,
+	detectDatadog,
+}
+
+// WithContextFromTrace creates a new logger with the trace and span id found
+// in ctx as "trace.id" and "span.id" context fields. Registered detectors are
+// tried first, then the headers attached with ContextWithTraceHeaders; the
+// first that finds a valid trace id wins. A missing span id is left out, and
+// the logger is returned unchanged when no trace is found.
+func (l *Logger) WithContextFromTrace(ctx context.Context) *Logger {
+	traceID, spanID, ok := detectTrace(ctx)
+	if !ok {
+		return l
+	}
+
+	fields := map[string]interface{}{"trace.id": traceID}
+	if spanID != "" {
+		fields["span.id"] = spanID
+	}
+	return l.WithFields(fields)
+}
+
+// detectTrace returns the ids found by the first detector with a valid trace
+// id
+func detectTrace(ctx context.Context) (traceID, spanID string, ok bool) {
+	if ctx == nil {
+		return "", "", false
+	}
+
+	traceDetectorsMu.RLock()
+	detectors := traceDetectors
+	traceDetectorsMu.RUnlock()
+
+	for _, detect := range detectors {
+		if traceID, spanID, ok := detect(ctx); ok && validTraceID(traceID) {
+			return traceID, spanID, true
+		}
+	}
+
+	headers, _ := ctx.Value(traceHeadersKey{}).(http.Header)
+	if headers == nil {
+		return "", "", false
+	}
+	for _, detect := range builtinTraceDetectors {
+		if traceID, spanID, ok := detect(headers); ok && validTraceID(traceID) {
+			return traceID, spanID, true
+		}
+	}
+	return "", "", false
+}
+
+// validTraceID reports whether id is set and not all zeros, which tracing
+// formats use for an absent trace
+func validTraceID(id string) bool {
+	return strings.Trim(id, "0") != ""
+}
+
+// detectTraceparent reads a W3C traceparent header
+func detectTraceparent(h http.Header) (string, string, bool) {
+	header := h.Get(traceparentHeader)
+	if header == "" {
+		return "", "", false
+	}
+	info, err := ParseTraceparent(header)
+	if err != nil {
+		return "", "", false
+	}
+	return info.TraceID, info.ParentID, true
+}
+
+// detectB3Single reads a B3 single header, "{trace}-{span}[-{sampled}[-{parent}]]".
+// A header carrying only the sampling decision has no trace.
+func detectB3Single(h http.Header) (string, string, bool) {
+	parts := strings.Split(h.Get("b3"), "-")
+	if len(parts) < 2 || !isB3TraceID(parts[0]) || !isLowerHex(parts[1], 16) {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// detectB3Multi reads the X-B3-TraceId and X-B3-SpanId headers
+func detectB3Multi(h http.Header) (string, string, bool) {
+	traceID := strings.ToLower(h.Get("X-B3-TraceId"))
+	if !isB3TraceID(traceID) {
+		return "", "", false
+	}
+	spanID := strings.ToLower(h.Get("X-B3-SpanId"))
+	if !isLowerHex(spanID, 16) {
+		spanID = ""
+	}
+	return traceID, spanID, true
+}
+
+// detectDatadog reads the decimal x-datadog-trace-id and x-datadog-parent-id
+// headers
+func detectDatadog(h http.Header) (string, string, bool) {
+	traceID := h.Get("X-Datadog-Trace-Id")
+	if _, err := strconv.ParseUint(traceID, 10, 64); err != nil {
+		return "", "", false
+	}
+	spanID := h.Get("X-Datadog-Parent-Id")
+	if _, err := strconv.ParseUint(spanID, 10, 64); err != nil {
+		spanID = ""
+	}
+	return traceID, spanID, true
+}
+
+// isB3TraceID reports whether s is a 64 or 128 bit B3 trace id
+func isB3TraceID(s string) bool {
+	return isLowerHex(s, 16) || isLowerHex(s, 32)
+}
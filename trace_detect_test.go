@@ -0,0 +1,139 @@
+package dy
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+// withTraceDetectors replaces the registered detectors for the duration of t
+func withTraceDetectors(t *testing.T, detectors ...TraceContextDetector) {
+	traceDetectorsMu.Lock()
+	saved := traceDetectors
+	traceDetectors = nil
+	traceDetectorsMu.Unlock()
+	t.Cleanup(func() {
+		traceDetectorsMu.Lock()
+		traceDetectors = saved
+		traceDetectorsMu.Unlock()
+	})
+
+	for _, fn := range detectors {
+		RegisterTraceContextDetector(fn)
+	}
+}
+
+func TestWithContextFromTraceHeaders(t *testing.T) {
+	withTraceDetectors(t)
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		traceID string
+		spanID  string
+	}{
+		{
+			name:    "traceparent",
+			headers: map[string]string{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+			traceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+			spanID:  "00f067aa0ba902b7",
+		},
+		{
+			name:    "b3 single",
+			headers: map[string]string{"b3": "80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-1-05e3ac9a4f6e3b90"},
+			traceID: "80f198ee56343ba864fe8b2a57d3eff7",
+			spanID:  "e457b5a2e4d86bd1",
+		},
+		{
+			name:    "b3 multi",
+			headers: map[string]string{"X-B3-TraceId": "a3ce929d0e0e4736", "X-B3-SpanId": "e457b5a2e4d86bd1"},
+			traceID: "a3ce929d0e0e4736",
+			spanID:  "e457b5a2e4d86bd1",
+		},
+		{
+			name:    "datadog",
+			headers: map[string]string{"X-Datadog-Trace-Id": "1234567890", "X-Datadog-Parent-Id": "987"},
+			traceID: "1234567890",
+			spanID:  "987",
+		},
+		{
+			name: "priority",
+			headers: map[string]string{
+				"X-Datadog-Trace-Id": "1234567890",
+				"b3":                 "80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1",
+				"traceparent":        "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			},
+			traceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+			spanID:  "00f067aa0ba902b7",
+		},
+		{
+			name: "invalid formats skipped",
+			headers: map[string]string{
+				"traceparent":        "00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+				"b3":                 "1",
+				"X-Datadog-Trace-Id": "42",
+			},
+			traceID: "42",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := http.Header{}
+			for k, v := range tt.headers {
+				headers.Set(k, v)
+			}
+			ctx := ContextWithTraceHeaders(context.Background(), headers)
+			l := New(WithOutput(&syncBuffer{})).WithContextFromTrace(ctx)
+
+			if got, _ := l.ContextValue("trace.id"); got != tt.traceID {
+				t.Errorf("Expected trace.id %q, got %v", tt.traceID, got)
+			}
+			got, ok := l.ContextValue("span.id")
+			if tt.spanID == "" && ok {
+				t.Errorf("Expected no span.id, got %v", got)
+			} else if tt.spanID != "" && got != tt.spanID {
+				t.Errorf("Expected span.id %q, got %v", tt.spanID, got)
+			}
+		})
+	}
+}
+
+func TestWithContextFromTraceRegistered(t *testing.T) {
+	type spanKey struct{}
+	withTraceDetectors(t,
+		func(ctx context.Context) (string, string, bool) {
+			return "00000000000000000000000000000000", "", true
+		},
+		func(ctx context.Context) (string, string, bool) {
+			id, ok := ctx.Value(spanKey{}).(string)
+			return id, "span-1", ok
+		},
+	)
+
+	// Registered detectors win over headers, invalid ids fall through
+	headers := http.Header{}
+	headers.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx := context.WithValue(ContextWithTraceHeaders(context.Background(), headers), spanKey{}, "otel-trace")
+
+	l := New(WithOutput(&syncBuffer{})).WithContextFromTrace(ctx)
+	if got, _ := l.ContextValue("trace.id"); got != "otel-trace" {
+		t.Errorf("Expected the registered detector's trace id, got %v", got)
+	}
+	if got, _ := l.ContextValue("span.id"); got != "span-1" {
+		t.Errorf("Expected the registered detector's span id, got %v", got)
+	}
+}
+
+func TestWithContextFromTraceMissing(t *testing.T) {
+	withTraceDetectors(t)
+
+	l := New(WithOutput(&syncBuffer{}))
+	if got := l.WithContextFromTrace(context.Background()); got != l {
+		t.Error("Expected the logger unchanged without a trace")
+	}
+	ctx := ContextWithTraceHeaders(context.Background(), http.Header{})
+	if got := l.WithContextFromTrace(ctx); got != l {
+		t.Error("Expected the logger unchanged without trace headers")
+	}
+}
@@ -0,0 +1,53 @@
+package dy
+
+// TraceMethodWithResult logs entry and exit of a method like TraceFunction,
+// and the returned function logs the method's return values on exit:
+//
+//	exit := l.TraceMethodWithResult("input:", x)
+//	defer func() { exit(result, err) }()
+//
+// The last value is taken as the method's error when it is an error, or nil
+// following other values. A non-nil error is logged as "error" in the
+// ErrorData format, and the other values as "result", a single value as is
+// and several as a list. The returned function is deferred in a closure so
+// that it sees the final values of named results.
+func (l *Logger) TraceMethodWithResult(args ...interface{}) func(result ...interface{}) {
+	exit := l.traceEnter(1, args) // skip TraceMethodWithResult
+	if exit == nil {
+		return func(...interface{}) {}
+	}
+	return func(result ...interface{}) {
+		exit(1, l.resultFields(result)) // skip this function
+	}
+}
+
+// resultFields returns the "result" and "error" fields for return values
+func (l *Logger) resultFields(result []interface{}) []ContextField {
+	var fields []ContextField
+
+	var err error
+	if n := len(result); n > 0 {
+		last := result[n-1]
+		if e, ok := last.(error); ok {
+			err = e
+			result = result[:n-1]
+		} else if last == nil && n > 1 {
+			result = result[:n-1]
+		}
+	}
+
+	switch len(result) {
+	case 0:
+	case 1:
+		fields = append(fields, ContextField{Key: "result", Value: result[0]})
+	default:
+		fields = append(fields, ContextField{Key: "result", Value: result})
+	}
+	if err != nil {
+		// The stack would only show the tracing code
+		stack := l.errorStackConfig()
+		stack.noErrorStacks = true
+		fields = append(fields, ContextField{Key: "error", Value: extractErrorData(err, 0, stack)})
+	}
+	return fields
+}
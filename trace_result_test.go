@@ -0,0 +1,86 @@
+package dy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// parse doubles s, failing for empty input
+func parse(l *Logger, s string) (result string, err error) {
+	exit := l.TraceMethodWithResult("input:", s)
+	defer func() { exit(result, err) }()
+	if s == "" {
+		return "", NewError("empty input", "E_EMPTY", nil)
+	}
+	return s + s, nil
+}
+
+func TestTraceMethodWithResult(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false), WithTrace(true), WithLevel(DebugLevel))
+
+	if got, _ := parse(l, "ab"); got != "abab" {
+		t.Fatalf("Unexpected result %q", got)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected entry and exit lines, got: %s", buf.String())
+	}
+	if !strings.Contains(lines[0], "Entering parse input:ab") {
+		t.Errorf("Expected the arguments on entry, got: %s", lines[0])
+	}
+	if !strings.Contains(lines[1], "Exiting parse") || !strings.Contains(lines[1], "{result: abab}") {
+		t.Errorf("Expected the result on exit, got: %s", lines[1])
+	}
+	if strings.Contains(lines[1], "error") {
+		t.Errorf("Expected no error field for a nil error, got: %s", lines[1])
+	}
+
+	buf.Reset()
+	parse(l, "")
+	if !strings.Contains(buf.String(), "{result: , error: empty input (code=E_EMPTY)}") {
+		t.Errorf("Expected the error on exit, got: %s", buf.String())
+	}
+}
+
+func TestTraceMethodWithResultJSON(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false), WithTrace(true), WithLevel(DebugLevel), WithJSONFormat(true))
+
+	func() (n int, m map[string]int, err error) {
+		exit := l.TraceMethodWithResult()
+		defer func() { exit(n, m, err) }()
+		return 3, map[string]int{"a": 1}, errors.New("partial")
+	}()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	var exit LogEntry
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &exit); err != nil {
+		t.Fatalf("Failed to decode exit entry: %v", err)
+	}
+	if exit.TraceType != "exit" {
+		t.Fatalf("Expected the exit entry last, got %+v", exit)
+	}
+
+	result, _ := exit.Context["result"].([]interface{})
+	if len(result) != 2 || result[0] != float64(3) || result[1].(map[string]interface{})["a"] != float64(1) {
+		t.Errorf("Expected the marshaled results, got %v", exit.Context["result"])
+	}
+	errData, _ := exit.Context["error"].(map[string]interface{})
+	if errData["message"] != "partial" {
+		t.Errorf("Expected the error as ErrorData, got %v", exit.Context["error"])
+	}
+}
+
+func TestTraceMethodWithResultDisabled(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTrace(false))
+	l.TraceMethodWithResult("x")("result", nil)
+	if buf.Len() != 0 {
+		t.Errorf("Expected no output with tracing disabled, got: %s", buf.String())
+	}
+}
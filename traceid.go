@@ -0,0 +1,96 @@
+package dy
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// defaultTraceIDKey is the context key WithNewTraceID uses unless
+// WithTraceIDKey sets another
+const defaultTraceIDKey = "trace_id"
+
+// traceIDGenerator creates the ids attached by WithNewTraceID
+type traceIDGenerator func() string
+
+// WithTraceIDGenerator sets the function creating the ids attached by
+// WithNewTraceID. By default ids are 16 random bytes, hex encoded.
+func WithTraceIDGenerator(fn func() string) Option {
+	return func(l *Logger) {
+		l.traceIDGen = fn
+	}
+}
+
+// WithTraceIDKey sets the context key WithNewTraceID attaches ids under. The
+// default is "trace_id".
+func WithTraceIDKey(key string) Option {
+	return func(l *Logger) {
+		l.traceIDKey = key
+	}
+}
+
+// WithNewTraceID creates a new logger with a freshly generated trace id as a
+// context field, for request-scoped loggers in services without distributed
+// tracing. The id can be read back with ContextValue.
+func (l *Logger) WithNewTraceID() *Logger {
+	return l.WithTraceID("")
+}
+
+// WithTraceID creates a new logger with id as the trace id context field,
+// under the key set with WithTraceIDKey, e.g. for a correlation id received
+// from the caller. An empty id is replaced by a generated one, as with
+// WithNewTraceID.
+func (l *Logger) WithTraceID(id string) *Logger {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	gen := l.traceIDGen
+	key := l.traceIDKey
+	l.mu.Unlock()
+
+	if gen == nil {
+		gen = randomTraceID
+	}
+	if key == "" {
+		key = defaultTraceIDKey
+	}
+	if id == "" {
+		id = gen()
+	}
+	return l.WithContext(key, id)
+}
+
+// ContextValue returns the value of the context field key and whether the
+// logger has one. When key was added more than once the latest value is
+// returned.
+func (l *Logger) ContextValue(key string) (interface{}, bool) {
+	if l == nil {
+		return nil, false
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.context == nil {
+		return nil, false
+	}
+	for i := len(l.context.Fields) - 1; i >= 0; i-- {
+		if field := l.context.Fields[i]; field.Key == key {
+			if sv, ok := field.Value.(secureValue); ok {
+				return sv.value, true
+			}
+			return field.Value, true
+		}
+	}
+	return nil, false
+}
+
+// randomTraceID returns 16 random bytes, hex encoded
+func randomTraceID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "00000000000000000000000000000000"
+	}
+	return hex.EncodeToString(b[:])
+}
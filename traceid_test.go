@@ -0,0 +1,57 @@
+package dy
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWithNewTraceID(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false))
+
+	first := l.WithNewTraceID()
+	second := l.WithNewTraceID()
+
+	id, ok := first.ContextValue("trace_id")
+	if !ok {
+		t.Fatal("Expected a trace_id context field")
+	}
+	if s, _ := id.(string); len(s) != 32 {
+		t.Errorf("Expected a 32 character hex id, got %v", id)
+	}
+	if other, _ := second.ContextValue("trace_id"); other == id {
+		t.Errorf("Expected distinct ids, got %v twice", id)
+	}
+
+	first.Info("request")
+	if !strings.Contains(buf.String(), "{trace_id: "+id.(string)+"}") {
+		t.Errorf("Expected the trace id in output, got: %s", buf.String())
+	}
+}
+
+func TestWithTraceIDGenerator(t *testing.T) {
+	l := New(WithOutput(&bytes.Buffer{}),
+		WithTraceIDGenerator(func() string { return "fixed" }),
+		WithTraceIDKey("correlation_id"))
+
+	child := l.WithContext("user", "alice").WithNewTraceID()
+	if id, ok := child.ContextValue("correlation_id"); !ok || id != "fixed" {
+		t.Errorf("Expected generated id under the configured key, got %v, %v", id, ok)
+	}
+}
+
+func TestContextValue(t *testing.T) {
+	l := New(WithOutput(&bytes.Buffer{}))
+	if _, ok := l.ContextValue("user"); ok {
+		t.Error("Expected no value on an empty context")
+	}
+
+	child := l.WithContext("user", "alice").WithContext("user", "bob").WithContextSecure("token", "s3cret", DebugLevel)
+	if v, _ := child.ContextValue("user"); v != "bob" {
+		t.Errorf("Expected the latest value, got %v", v)
+	}
+	if v, _ := child.ContextValue("token"); v != "s3cret" {
+		t.Errorf("Expected the secure value itself, got %v", v)
+	}
+}
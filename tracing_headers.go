@@ -0,0 +1,176 @@
+package dy
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// traceparentHeader is the W3C Trace Context header carrying the trace
+const traceparentHeader = "traceparent"
+
+// errInvalidTraceparent is wrapped by the errors of ParseTraceparent
+var errInvalidTraceparent = errors.New("invalid traceparent")
+
+// TraceInfo is the trace context carried by a W3C traceparent header
+type TraceInfo struct {
+	TraceID  string // 32 lowercase hex characters
+	ParentID string // Span id of the caller, 16 lowercase hex characters
+	Flags    byte   // Trace flags, bit 0 is the sampled flag
+}
+
+// Sampled reports whether the caller sampled the trace
+func (t TraceInfo) Sampled() bool {
+	return t.Flags&0x01 != 0
+}
+
+// Traceparent formats t as a version 00 traceparent header value
+func (t TraceInfo) Traceparent() string {
+	return fmt.Sprintf("00-%s-%s-%02x", t.TraceID, t.ParentID, t.Flags)
+}
+
+// ParseTraceparent parses a W3C traceparent header value such as
+// "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01". Values with an
+// unknown version are accepted as long as they start with the version 00
+// fields. Malformed values, version ff and all-zero ids are rejected.
+func ParseTraceparent(header string) (TraceInfo, error) {
+	header = strings.TrimSpace(header)
+	parts := strings.SplitN(header, "-", 5)
+	if len(parts) < 4 {
+		return TraceInfo{}, fmt.Errorf("%w: expected 4 fields", errInvalidTraceparent)
+	}
+
+	version := parts[0]
+	if !isLowerHex(version, 2) || version == "ff" {
+		return TraceInfo{}, fmt.Errorf("%w: bad version %q", errInvalidTraceparent, version)
+	}
+	// Version 00 has exactly four fields, later versions may add more
+	if version == "00" && len(parts) != 4 {
+		return TraceInfo{}, fmt.Errorf("%w: unexpected fields after flags", errInvalidTraceparent)
+	}
+	if !isLowerHex(parts[1], 32) || strings.Trim(parts[1], "0") == "" {
+		return TraceInfo{}, fmt.Errorf("%w: bad trace id %q", errInvalidTraceparent, parts[1])
+	}
+	if !isLowerHex(parts[2], 16) || strings.Trim(parts[2], "0") == "" {
+		return TraceInfo{}, fmt.Errorf("%w: bad parent id %q", errInvalidTraceparent, parts[2])
+	}
+	if !isLowerHex(parts[3], 2) {
+		return TraceInfo{}, fmt.Errorf("%w: bad flags %q", errInvalidTraceparent, parts[3])
+	}
+
+	flags, _ := strconv.ParseUint(parts[3], 16, 8)
+	return TraceInfo{TraceID: parts[1], ParentID: parts[2], Flags: byte(flags)}, nil
+}
+
+// TraceparentFromRequest returns the trace context of r's traceparent header.
+// It reports false when the header is missing or malformed, in which case
+// the request should be handled as the start of a new trace.
+func TraceparentFromRequest(r *http.Request) (TraceInfo, bool) {
+	header := r.Header.Get(traceparentHeader)
+	if header == "" {
+		return TraceInfo{}, false
+	}
+	info, err := ParseTraceparent(header)
+	return info, err == nil
+}
+
+// InjectTraceparent sets the traceparent header of an outgoing request to
+// continue the trace in info, with a new random span id as the parent id
+func InjectTraceparent(h http.Header, info TraceInfo) {
+	var span [8]byte
+	rand.Read(span[:])
+	info.ParentID = hex.EncodeToString(span[:])
+	h.Set(traceparentHeader, info.Traceparent())
+}
+
+// WithTraceInfo creates a new logger with the trace id and parent span id of
+// info as "trace_id" and "parent_span_id" context fields
+func (l *Logger) WithTraceInfo(info TraceInfo) *Logger {
+	return l.WithContext("trace_id", info.TraceID).WithContext("parent_span_id", info.ParentID)
+}
+
+// WithB3Headers creates a new logger with the Zipkin B3 trace headers found
+// in headers as context fields: X-B3-TraceId as "trace.id", X-B3-SpanId as
+// "span.id", X-B3-ParentSpanId as "parent.span.id" and X-B3-Sampled as a
+// boolean "trace.sampled". Missing headers are left out, and the logger is
+// returned unchanged when none is present.
+func (l *Logger) WithB3Headers(headers http.Header) *Logger {
+	fields := make(map[string]interface{})
+	if v := headers.Get("X-B3-TraceId"); v != "" {
+		fields["trace.id"] = v
+	}
+	if v := headers.Get("X-B3-SpanId"); v != "" {
+		fields["span.id"] = v
+	}
+	if v := headers.Get("X-B3-ParentSpanId"); v != "" {
+		fields["parent.span.id"] = v
+	}
+	if sampled, ok := parseB3Sampled(headers.Get("X-B3-Sampled")); ok {
+		fields["trace.sampled"] = sampled
+	}
+	// The debug flag implies sampling
+	if headers.Get("X-B3-Flags") == "1" {
+		fields["trace.sampled"] = true
+	}
+
+	if len(fields) == 0 {
+		return l
+	}
+	return l.WithFields(fields)
+}
+
+// WithDatadogHeaders creates a new logger with the Datadog trace headers found
+// in headers as context fields: x-datadog-trace-id as "trace.id",
+// x-datadog-parent-id as "parent.span.id" and x-datadog-sampling-priority as
+// a boolean "trace.sampled". Missing headers are left out, and the logger is
+// returned unchanged when none is present.
+func (l *Logger) WithDatadogHeaders(headers http.Header) *Logger {
+	fields := make(map[string]interface{})
+	if v := headers.Get("X-Datadog-Trace-Id"); v != "" {
+		fields["trace.id"] = v
+	}
+	if v := headers.Get("X-Datadog-Parent-Id"); v != "" {
+		fields["parent.span.id"] = v
+	}
+	if v := headers.Get("X-Datadog-Sampling-Priority"); v != "" {
+		// Priorities above zero keep the trace, the others drop it
+		if priority, err := strconv.Atoi(v); err == nil {
+			fields["trace.sampled"] = priority > 0
+		}
+	}
+
+	if len(fields) == 0 {
+		return l
+	}
+	return l.WithFields(fields)
+}
+
+// parseB3Sampled parses an X-B3-Sampled value, which is "1" or "0" in current
+// B3 and "true" or "false" in older implementations
+func parseB3Sampled(v string) (sampled bool, ok bool) {
+	switch strings.ToLower(v) {
+	case "1", "true", "d":
+		return true, true
+	case "0", "false":
+		return false, true
+	}
+	return false, false
+}
+
+// isLowerHex reports whether s is n lowercase hex characters
+func isLowerHex(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
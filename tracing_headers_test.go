@@ -0,0 +1,145 @@
+package dy
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestWithB3Headers(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false))
+
+	headers := http.Header{}
+	headers.Set("X-B3-TraceId", "80f198ee56343ba864fe8b2a57d3eff7")
+	headers.Set("X-B3-SpanId", "e457b5a2e4d86bd1")
+	headers.Set("X-B3-ParentSpanId", "05e3ac9a4f6e3b90")
+	headers.Set("X-B3-Sampled", "1")
+	l.WithB3Headers(headers).Info("request")
+
+	output := buf.String()
+	for _, want := range []string{
+		"trace.id: 80f198ee56343ba864fe8b2a57d3eff7",
+		"span.id: e457b5a2e4d86bd1",
+		"parent.span.id: 05e3ac9a4f6e3b90",
+		"trace.sampled: true",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output, got: %s", want, output)
+		}
+	}
+}
+
+func TestWithB3HeadersMissing(t *testing.T) {
+	l := New(WithOutput(&bytes.Buffer{}))
+	if got := l.WithB3Headers(http.Header{}); got != l {
+		t.Error("Expected the logger unchanged without B3 headers")
+	}
+
+	var buf bytes.Buffer
+	l = New(WithOutput(&buf), WithTimestamp(false))
+	headers := http.Header{}
+	headers.Set("X-B3-Sampled", "false")
+	l.WithB3Headers(headers).Info("request")
+	if got := buf.String(); got != "[INFO] request {trace.sampled: false}\n" {
+		t.Errorf("Unexpected output: %q", got)
+	}
+}
+
+func TestWithDatadogHeaders(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false))
+
+	headers := http.Header{}
+	headers.Set("x-datadog-trace-id", "1234567890")
+	headers.Set("x-datadog-parent-id", "987654321")
+	headers.Set("x-datadog-sampling-priority", "-1")
+	l.WithDatadogHeaders(headers).Info("request")
+
+	output := buf.String()
+	for _, want := range []string{
+		"trace.id: 1234567890",
+		"parent.span.id: 987654321",
+		"trace.sampled: false",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output, got: %s", want, output)
+		}
+	}
+}
+
+func TestParseTraceparent(t *testing.T) {
+	info, err := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	if err != nil {
+		t.Fatalf("ParseTraceparent failed: %v", err)
+	}
+	if info.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || info.ParentID != "00f067aa0ba902b7" || !info.Sampled() {
+		t.Errorf("Unexpected trace info: %+v", info)
+	}
+	if got := info.Traceparent(); got != "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01" {
+		t.Errorf("Traceparent() = %q", got)
+	}
+
+	// Later versions may append fields
+	if _, err := ParseTraceparent("cc-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00-extra"); err != nil {
+		t.Errorf("Expected a future version to parse, got %v", err)
+	}
+
+	for _, header := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"0-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e473-00f067aa0ba902b7-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-1",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+	} {
+		if _, err := ParseTraceparent(header); err == nil {
+			t.Errorf("Expected %q to be rejected", header)
+		}
+	}
+}
+
+func TestTraceparentFromRequest(t *testing.T) {
+	r, _ := http.NewRequest(http.MethodGet, "/", nil)
+	if _, ok := TraceparentFromRequest(r); ok {
+		t.Error("Expected no trace info without a traceparent header")
+	}
+
+	r.Header.Set("traceparent", "00-zz-00f067aa0ba902b7-01")
+	if _, ok := TraceparentFromRequest(r); ok {
+		t.Error("Expected a malformed traceparent header to be ignored")
+	}
+
+	r.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	info, ok := TraceparentFromRequest(r)
+	if !ok {
+		t.Fatal("Expected trace info from the traceparent header")
+	}
+
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false))
+	l.WithTraceInfo(info).Info("request")
+	if got := buf.String(); got != "[INFO] request {trace_id: 4bf92f3577b34da6a3ce929d0e0e4736, parent_span_id: 00f067aa0ba902b7}\n" &&
+		got != "[INFO] request {parent_span_id: 00f067aa0ba902b7, trace_id: 4bf92f3577b34da6a3ce929d0e0e4736}\n" {
+		t.Errorf("Unexpected output: %q", got)
+	}
+}
+
+func TestInjectTraceparent(t *testing.T) {
+	info := TraceInfo{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", ParentID: "00f067aa0ba902b7", Flags: 1}
+	headers := http.Header{}
+	InjectTraceparent(headers, info)
+
+	out, err := ParseTraceparent(headers.Get("traceparent"))
+	if err != nil {
+		t.Fatalf("Injected header does not parse: %v", err)
+	}
+	if out.TraceID != info.TraceID || out.ParentID == info.ParentID || !out.Sampled() {
+		t.Errorf("Expected the same trace with a new span, got %+v", out)
+	}
+}
@@ -0,0 +1,87 @@
+package dy
+
+import "strings"
+
+// FieldTransformer rewrites a field before it is encoded. It returns the new
+// key and value, and false to drop the field.
+type FieldTransformer func(key string, value interface{}) (string, interface{}, bool)
+
+// WithFieldTransformer adds a transformer applied to every context field and
+// error attribute of each entry, before filters and encoding. Transformers
+// run in registration order and work on copies, so the logger's context and
+// those of related loggers are never modified.
+func WithFieldTransformer(t FieldTransformer) Option {
+	return func(l *Logger) {
+		l.transforms = append(l.transforms, t)
+	}
+}
+
+// LowercaseKeys returns a transformer that lowercases every key
+func LowercaseKeys() FieldTransformer {
+	return func(key string, value interface{}) (string, interface{}, bool) {
+		return strings.ToLower(key), value, true
+	}
+}
+
+// StripKeyPrefix returns a transformer that removes prefix from keys
+func StripKeyPrefix(prefix string) FieldTransformer {
+	return func(key string, value interface{}) (string, interface{}, bool) {
+		return strings.TrimPrefix(key, prefix), value, true
+	}
+}
+
+// RenameKey returns a transformer that renames the key from to to
+func RenameKey(from, to string) FieldTransformer {
+	return func(key string, value interface{}) (string, interface{}, bool) {
+		if key == from {
+			return to, value, true
+		}
+		return key, value, true
+	}
+}
+
+// transformField runs a field through every transformer
+func transformField(key string, value interface{}, transforms []FieldTransformer) (string, interface{}, bool) {
+	for _, t := range transforms {
+		var keep bool
+		if key, value, keep = t(key, value); !keep {
+			return "", nil, false
+		}
+	}
+	return key, value, true
+}
+
+// transformFields returns a transformed copy of fields
+func transformFields(fields []ContextField, transforms []FieldTransformer) []ContextField {
+	result := make([]ContextField, 0, len(fields))
+	for _, field := range fields {
+		key, value, keep := transformField(field.Key, field.Value, transforms)
+		if !keep {
+			continue
+		}
+		if data, ok := value.(ErrorData); ok {
+			value = transformErrorData(data, transforms)
+		}
+		result = append(result, ContextField{Key: key, Value: value})
+	}
+	return result
+}
+
+// transformErrorData returns a copy of data with transformed attributes,
+// including those of its causes
+func transformErrorData(data ErrorData, transforms []FieldTransformer) ErrorData {
+	if len(data.Attributes) > 0 {
+		attrs := make(map[string]interface{}, len(data.Attributes))
+		for k, v := range data.Attributes {
+			if key, value, keep := transformField(k, v, transforms); keep {
+				attrs[key] = value
+			}
+		}
+		data.Attributes = attrs
+	}
+	if data.Cause != nil {
+		cause := transformErrorData(*data.Cause, transforms)
+		data.Cause = &cause
+	}
+	return data
+}
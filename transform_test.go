@@ -0,0 +1,55 @@
+package dy
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWithFieldTransformer(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(
+		WithOutput(&buf),
+		WithTimestamp(false),
+		WithFieldTransformer(RenameKey("userId", "user_id")),
+		WithFieldTransformer(StripKeyPrefix("legacy_")),
+		WithFieldTransformer(LowercaseKeys()),
+		WithFieldTransformer(func(key string, value interface{}) (string, interface{}, bool) {
+			return key, value, key != "password"
+		}),
+	)
+
+	parent := l.WithFields(map[string]interface{}{"userId": 7, "legacy_Region": "eu", "password": "x"})
+	parent.Info("transformed")
+
+	output := buf.String()
+	for _, want := range []string{"user_id: 7", "region: eu"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output, got: %s", want, output)
+		}
+	}
+	if strings.Contains(output, "password") || strings.Contains(output, "userId") {
+		t.Errorf("Expected fields renamed or dropped, got: %s", output)
+	}
+
+	// The logger's own context is left untouched
+	keys := map[string]bool{}
+	for _, field := range parent.context.Fields {
+		keys[field.Key] = true
+	}
+	if !keys["userId"] || !keys["password"] {
+		t.Errorf("Expected context to be unmodified, got %v", parent.context.Fields)
+	}
+}
+
+func TestFieldTransformerErrorAttributes(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false), WithJSONFormat(true), WithFieldTransformer(LowercaseKeys()))
+
+	err := NewError("boom", "E_BOOM", map[string]interface{}{"RequestID": "abc"})
+	l.WithError(err).Error("failed")
+
+	if !strings.Contains(buf.String(), `"requestid":"abc"`) {
+		t.Errorf("Expected error attributes transformed, got: %s", buf.String())
+	}
+}
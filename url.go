@@ -0,0 +1,160 @@
+package dy
+
+import (
+	"net/url"
+	"strings"
+)
+
+// redactedValue replaces the value of sensitive query parameters
+const redactedValue = "[REDACTED]"
+
+// defaultSensitiveParams are the query parameters redacted unless
+// WithSensitiveParams is used
+var defaultSensitiveParams = map[string]bool{
+	"access_token":  true,
+	"api_key":       true,
+	"apikey":        true,
+	"client_secret": true,
+	"password":      true,
+	"refresh_token": true,
+	"secret":        true,
+	"signature":     true,
+	"token":         true,
+}
+
+// WithSensitiveParams sets the query parameter names redacted by
+// WithContextFromURL, replacing the defaults. Names are case-insensitive.
+func WithSensitiveParams(names ...string) Option {
+	return func(l *Logger) {
+		l.sensitive = make(map[string]bool, len(names))
+		for _, name := range names {
+			l.sensitive[strings.ToLower(name)] = true
+		}
+	}
+}
+
+// WithContextFromURL creates a new logger with the components of u as
+// url.scheme, url.host, url.path, url.query and url.fragment context fields.
+// Empty components are omitted and sensitive query parameters are redacted.
+func (l *Logger) WithContextFromURL(u *url.URL) *Logger {
+	if l == nil {
+		return nil
+	}
+
+	if u == nil {
+		return l
+	}
+
+	l.mu.Lock()
+	sensitive := l.sensitive
+	l.mu.Unlock()
+	if sensitive == nil {
+		sensitive = defaultSensitiveParams
+	}
+
+	fields := make(map[string]interface{})
+	if u.Scheme != "" {
+		fields["url.scheme"] = u.Scheme
+	}
+	if u.Host != "" {
+		fields["url.host"] = u.Host
+	}
+	if u.Path != "" {
+		fields["url.path"] = u.Path
+	}
+	if u.RawQuery != "" {
+		fields["url.query"] = redactQuery(u.RawQuery, sensitive)
+	}
+	if u.Fragment != "" {
+		fields["url.fragment"] = u.Fragment
+	}
+
+	if len(fields) == 0 {
+		return l
+	}
+	return l.WithFields(fields)
+}
+
+// redactQuery replaces the values of sensitive parameters in a raw query,
+// keeping the parameter order and encoding of everything else
+func redactQuery(rawQuery string, sensitive map[string]bool) string {
+	parts := strings.Split(rawQuery, "&")
+	for i, part := range parts {
+		key, _, hasValue := strings.Cut(part, "=")
+		if !hasValue {
+			continue
+		}
+		if name, err := url.QueryUnescape(key); err == nil && sensitive[strings.ToLower(name)] {
+			parts[i] = key + "=" + redactedValue
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// WithQueryParams creates a new logger with the parameters of rawQuery as
+// "query.<key>" context fields. When include names are given only those
+// parameters are added, matched case-insensitively. Parameters with several
+// values are logged comma-joined, sensitive parameters (see
+// WithSensitiveParams) are redacted, and malformed pairs are skipped. The
+// logger is returned unchanged when no parameter is added.
+func (l *Logger) WithQueryParams(rawQuery string, include ...string) *Logger {
+	if len(include) == 0 {
+		return l.withQueryParams(rawQuery, nil)
+	}
+	return l.withQueryParams(rawQuery, func(key string) bool {
+		return containsFold(include, key)
+	})
+}
+
+// WithQueryParamsExclude is like WithQueryParams but adds every parameter
+// except the excluded ones, for leaving out personal data or credentials
+func (l *Logger) WithQueryParamsExclude(rawQuery string, exclude ...string) *Logger {
+	return l.withQueryParams(rawQuery, func(key string) bool {
+		return !containsFold(exclude, key)
+	})
+}
+
+// withQueryParams adds the parameters of rawQuery accepted by keep, or all
+// of them when keep is nil
+func (l *Logger) withQueryParams(rawQuery string, keep func(key string) bool) *Logger {
+	if l == nil {
+		return nil
+	}
+
+	// ParseQuery returns the valid pairs along with the first error
+	values, _ := url.ParseQuery(rawQuery)
+
+	l.mu.Lock()
+	sensitive := l.sensitive
+	l.mu.Unlock()
+	if sensitive == nil {
+		sensitive = defaultSensitiveParams
+	}
+
+	fields := make(map[string]interface{})
+	for key, vals := range values {
+		if keep != nil && !keep(key) {
+			continue
+		}
+		if sensitive[strings.ToLower(key)] {
+			fields["query."+key] = redactedValue
+			continue
+		}
+		fields["query."+key] = strings.Join(vals, ",")
+	}
+
+	if len(fields) == 0 {
+		return l
+	}
+	return l.WithFields(fields)
+}
+
+// containsFold reports whether names holds name, ignoring case
+func containsFold(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
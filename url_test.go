@@ -0,0 +1,100 @@
+package dy
+
+import (
+	"bytes"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestWithContextFromURL(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false))
+
+	u, _ := url.Parse("https://api.example.com/v1/users?id=42&Token=s3cret&page=2#top")
+	l.WithContextFromURL(u).Info("request")
+
+	output := buf.String()
+	for _, want := range []string{
+		"url.scheme: https",
+		"url.host: api.example.com",
+		"url.path: /v1/users",
+		"url.query: id=42&Token=[REDACTED]&page=2",
+		"url.fragment: top",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output, got: %s", want, output)
+		}
+	}
+	if strings.Contains(output, "s3cret") {
+		t.Errorf("Expected token to be redacted, got: %s", output)
+	}
+}
+
+func TestWithSensitiveParams(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false), WithSensitiveParams("session"))
+
+	u, _ := url.Parse("/login?session=abc&token=xyz")
+	l.WithContextFromURL(u).WithContext("child", true).Info("login")
+
+	output := buf.String()
+	if !strings.Contains(output, "url.query: session=[REDACTED]&token=xyz") {
+		t.Errorf("Expected only configured params redacted, got: %s", output)
+	}
+	if strings.Contains(output, "url.scheme") || strings.Contains(output, "url.fragment") {
+		t.Errorf("Expected empty components omitted, got: %s", output)
+	}
+}
+
+func TestWithQueryParams(t *testing.T) {
+	l := New(WithOutput(&bytes.Buffer{}))
+	query := "id=42&tag=a&tag=b&Email=x%40example.com&token=s3cret"
+
+	all := l.WithQueryParams(query)
+	for key, want := range map[string]string{
+		"query.id":    "42",
+		"query.tag":   "a,b",
+		"query.Email": "x@example.com",
+		"query.token": redactedValue,
+	} {
+		if got, _ := all.ContextValue(key); got != want {
+			t.Errorf("Expected %s=%q, got %v", key, want, got)
+		}
+	}
+
+	some := l.WithQueryParams(query, "ID", "tag")
+	if got, _ := some.ContextValue("query.id"); got != "42" {
+		t.Errorf("Expected included query.id, got %v", got)
+	}
+	if _, ok := some.ContextValue("query.Email"); ok {
+		t.Error("Expected query.Email to be left out")
+	}
+
+	if got := l.WithQueryParams(query, "missing"); got != l {
+		t.Error("Expected the logger unchanged when nothing is included")
+	}
+	if got := l.WithQueryParams(""); got != l {
+		t.Error("Expected the logger unchanged for an empty query")
+	}
+}
+
+func TestWithQueryParamsExclude(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false))
+
+	// The malformed pair is skipped, the others are kept
+	l.WithQueryParamsExclude("id=42&email=x%40example.com&bad=%zz&page=2", "EMAIL").Info("request")
+
+	output := buf.String()
+	for _, want := range []string{"query.id: 42", "query.page: 2"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output, got: %s", want, output)
+		}
+	}
+	for _, unwanted := range []string{"email", "query.bad"} {
+		if strings.Contains(output, unwanted) {
+			t.Errorf("Expected %q to be left out, got: %s", unwanted, output)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package dy
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// truncationSuffix is appended to field values cut by WithContextMaxValueLen
+const truncationSuffix = "..."
+
+// WithContextMaxValueLen creates a new logger that cuts every context field
+// value longer than maxLen runes, appending "...". In text output the length
+// of the value as printed with %v is checked; in JSON output strings are cut
+// directly and other values are replaced by their cut JSON encoding. Numbers,
+// booleans and error details are never cut. A maxLen of 0 or less removes the
+// limit.
+func (l *Logger) WithContextMaxValueLen(maxLen int) *Logger {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	child := l.newChild()
+	child.context = l.context.Clone()
+	child.maxValueLen = max(maxLen, 0)
+
+	return child
+}
+
+// truncateFields returns fields with values longer than maxLen runes cut.
+// fields is returned unchanged when no value is cut.
+func truncateFields(fields []ContextField, maxLen int, useJSON bool) []ContextField {
+	var result []ContextField
+	for i, field := range fields {
+		value, changed := truncateValue(field.Value, maxLen, useJSON)
+		if !changed {
+			continue
+		}
+		if result == nil {
+			result = make([]ContextField, len(fields))
+			copy(result, fields)
+		}
+		result[i].Value = value
+	}
+
+	if result == nil {
+		return fields
+	}
+	return result
+}
+
+// truncateValue cuts v to maxLen runes, reporting whether it changed
+func truncateValue(v interface{}, maxLen int, useJSON bool) (interface{}, bool) {
+	switch value := v.(type) {
+	case nil, ErrorData:
+		return v, false
+	case string:
+		return truncateString(value, maxLen)
+	case json.RawMessage:
+		return truncateString(string(value), maxLen)
+	}
+
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return v, false
+	}
+
+	var s string
+	if useJSON {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return v, false
+		}
+		s = string(data)
+	} else {
+		s = fmt.Sprintf("%v", v)
+	}
+	if cut, ok := truncateString(s, maxLen); ok {
+		return cut, true
+	}
+	return v, false
+}
+
+// truncateString cuts s to maxLen runes, reporting whether it was cut
+func truncateString(s string, maxLen int) (string, bool) {
+	if len(s) <= maxLen {
+		return s, false
+	}
+	runes := 0
+	for i := range s {
+		if runes == maxLen {
+			return s[:i] + truncationSuffix, true
+		}
+		runes++
+	}
+	return s, false
+}
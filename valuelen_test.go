@@ -0,0 +1,71 @@
+package dy
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWithContextMaxValueLenText(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false)).WithContextMaxValueLen(10)
+
+	l.WithFields(map[string]interface{}{
+		"query": "SELECT * FROM users WHERE id = 1",
+		"ids":   []int{1, 2, 3, 4, 5, 6},
+		"count": 12345678901234,
+		"short": "héllo",
+	}).Info("query")
+
+	output := buf.String()
+	for _, want := range []string{
+		"query: SELECT * F...",
+		"ids: [1 2 3 4 5...",
+		"count: 12345678901234",
+		"short: héllo",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output, got: %s", want, output)
+		}
+	}
+}
+
+func TestWithContextMaxValueLenJSON(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithJSONFormat(true), WithTimestamp(false)).WithContextMaxValueLen(8)
+
+	l.WithFields(map[string]interface{}{
+		"query":   "SELECT * FROM users",
+		"user":    map[string]string{"name": "alice"},
+		"enabled": true,
+	}).Info("query")
+
+	output := buf.String()
+	for _, want := range []string{
+		`"query":"SELECT *..."`,
+		`"user":"{\"name\":..."`,
+		`"enabled":true`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %s in output, got: %s", want, output)
+		}
+	}
+}
+
+func TestTruncateString(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		cut  bool
+	}{
+		{"short", "short", false},
+		{"exactly10!", "exactly10!", false},
+		{"ñññññññññññ", "ññññññññññ...", true},
+	}
+	for _, tt := range tests {
+		got, cut := truncateString(tt.in, 10)
+		if got != tt.want || cut != tt.cut {
+			t.Errorf("truncateString(%q) = %q, %v; want %q, %v", tt.in, got, cut, tt.want, tt.cut)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package dy
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// LogValuer is implemented by types that control how they are logged. When a
+// context field value or error attribute implements LogValuer, the value
+// returned by LogValue is logged in its place, so a type such as a user ID can
+// mask itself wherever it is logged.
+type LogValuer interface {
+	LogValue() interface{}
+}
+
+// maxLogValueDepth bounds the LogValue calls made for a single value, in case
+// LogValue returns another LogValuer
+const maxLogValueDepth = 8
+
+// resolveValue returns the value to log for v. A LogValuer is replaced by its
+// LogValue, then an encoding.TextMarshaler by its text and a fmt.Stringer by
+// its string. Other values, and values with a dedicated encoding such as times,
+// durations, byte fields, stack traces and errors, are returned unchanged.
+func resolveValue(v interface{}) interface{} {
+	resolved, _ := resolveValueChanged(v)
+	return resolved
+}
+
+// resolveValueChanged is like resolveValue but also reports whether v was replaced
+func resolveValueChanged(v interface{}) (interface{}, bool) {
+	changed := false
+	for i := 0; i < maxLogValueDepth; i++ {
+		lv, ok := v.(LogValuer)
+		if !ok || isNilPointer(v) {
+			break
+		}
+		v, changed = lv.LogValue(), true
+	}
+
+	switch value := v.(type) {
+	case nil, time.Time, time.Duration, bytesValue, StackTrace, json.RawMessage, error:
+		return v, changed
+	case ErrorData:
+		return resolveErrorData(value), true
+	}
+	if isNilPointer(v) {
+		return v, changed
+	}
+
+	switch value := v.(type) {
+	case encoding.TextMarshaler:
+		text, err := value.MarshalText()
+		if err != nil {
+			return fmt.Sprintf("<%v>", err), true
+		}
+		return string(text), true
+	case fmt.Stringer:
+		return value.String(), true
+	}
+	return v, changed
+}
+
+// resolveFields returns fields with their values resolved by resolveValue.
+// fields is returned unchanged when no value needs resolving.
+func resolveFields(fields []ContextField) []ContextField {
+	var result []ContextField
+	for i, field := range fields {
+		value, changed := resolveValueChanged(field.Value)
+		if !changed {
+			continue
+		}
+		if result == nil {
+			result = make([]ContextField, len(fields))
+			copy(result, fields)
+		}
+		result[i].Value = value
+	}
+
+	if result == nil {
+		return fields
+	}
+	return result
+}
+
+// resolveErrorData returns a copy of data with its attributes, and those of
+// its causes, resolved by resolveValue
+func resolveErrorData(data ErrorData) ErrorData {
+	if len(data.Attributes) > 0 {
+		attrs := make(map[string]interface{}, len(data.Attributes))
+		for k, v := range data.Attributes {
+			attrs[k] = resolveValue(v)
+		}
+		data.Attributes = attrs
+	}
+	if data.Cause != nil {
+		cause := resolveErrorData(*data.Cause)
+		data.Cause = &cause
+	}
+	return data
+}
+
+// isNilPointer reports whether v is a nil pointer, whose methods may panic
+func isNilPointer(v interface{}) bool {
+	rv := reflect.ValueOf(v)
+	return rv.Kind() == reflect.Ptr && rv.IsNil()
+}
@@ -0,0 +1,96 @@
+package dy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// maskedID masks itself through LogValuer
+type maskedID string
+
+func (id maskedID) LogValue() interface{} {
+	if len(id) <= 4 {
+		return "****"
+	}
+	return "****" + string(id[len(id)-4:])
+}
+
+// textIP implements encoding.TextMarshaler
+type textIP struct{ a, b, c, d byte }
+
+func (ip textIP) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%d.%d.%d.%d", ip.a, ip.b, ip.c, ip.d)), nil
+}
+
+// stringerColor implements fmt.Stringer
+type stringerColor struct{ Internal int }
+
+func (c stringerColor) String() string { return "blue" }
+
+// chainedValuer returns another LogValuer
+type chainedValuer struct{}
+
+func (chainedValuer) LogValue() interface{} { return maskedID("1234567890") }
+
+func TestLogValuerJSON(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithJSONFormat(true), WithTimestamp(false))
+
+	l.WithFields(map[string]interface{}{
+		"user":    maskedID("user-123456"),
+		"ip":      textIP{10, 0, 0, 1},
+		"color":   stringerColor{Internal: 7},
+		"chained": chainedValuer{},
+		"plain":   42,
+	}).Info("login")
+
+	var entry LogEntry
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to parse output %q: %v", buf.String(), err)
+	}
+	want := map[string]interface{}{
+		"user":    "****3456",
+		"ip":      "10.0.0.1",
+		"color":   "blue",
+		"chained": "****7890",
+		"plain":   float64(42),
+	}
+	for key, value := range want {
+		if got := entry.Context[key]; got != value {
+			t.Errorf("Field %q = %v, want %v", key, got, value)
+		}
+	}
+}
+
+func TestLogValuerText(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false))
+
+	l.WithContext("user", maskedID("user-123456")).Info("login")
+
+	if got := buf.String(); got != "[INFO] login {user: ****3456}\n" {
+		t.Errorf("Unexpected output: %q", got)
+	}
+}
+
+func TestLogValuerErrorAttributes(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithJSONFormat(true), WithTimestamp(false))
+
+	err := NewError("lookup failed", "NOT_FOUND", map[string]interface{}{"user": maskedID("user-123456")})
+	l.WithError(err).Error("request failed")
+
+	if strings.Contains(buf.String(), "user-123456") || !strings.Contains(buf.String(), `"user":"****3456"`) {
+		t.Errorf("Expected masked error attribute, got: %s", buf.String())
+	}
+}
+
+func TestResolveValueNilPointer(t *testing.T) {
+	var id *textIP
+	if got := resolveValue(id); got != id {
+		t.Errorf("Expected nil pointer unchanged, got %v", got)
+	}
+}
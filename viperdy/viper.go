@@ -0,0 +1,26 @@
+// Package viperdy adds viper configuration to dy loggers. It is kept out of
+// the dy module so that only programs using viper depend on it.
+package viperdy
+
+import (
+	"github.com/spf13/viper"
+	"github.com/zakirkun/dy"
+)
+
+// WithConfig creates a new logger with every key of v and its value as a
+// context field, redacting sensitive keys and those matching the exclude
+// patterns, see dy.Logger.WithConfigMap
+func WithConfig(l *dy.Logger, v *viper.Viper, exclude ...string) *dy.Logger {
+	if l == nil {
+		return nil
+	}
+	if v == nil {
+		return l
+	}
+
+	cm := make(map[string]string)
+	for _, key := range v.AllKeys() {
+		cm[key] = v.GetString(key)
+	}
+	return l.WithConfigMap(cm, exclude...)
+}
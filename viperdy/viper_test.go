@@ -0,0 +1,32 @@
+package viperdy
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/zakirkun/dy"
+)
+
+func TestWithConfig(t *testing.T) {
+	v := viper.New()
+	v.Set("server.port", 8080)
+	v.Set("db.password", "hunter2")
+	v.Set("db.dsn", "postgres://app@db/shop")
+
+	l := WithConfig(dy.New(dy.WithOutput(&bytes.Buffer{})), v, "*.dsn")
+
+	for key, want := range map[string]string{
+		"server.port": "8080",
+		"db.password": "[REDACTED]",
+		"db.dsn":      "[REDACTED]",
+	} {
+		if got, _ := l.ContextValue(key); got != want {
+			t.Errorf("Expected %s=%q, got %v", key, want, got)
+		}
+	}
+
+	if got := WithConfig(dy.New(dy.WithOutput(&bytes.Buffer{})), nil); got == nil {
+		t.Errorf("Expected a nil viper instance to return the logger unchanged")
+	}
+}
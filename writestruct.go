@@ -0,0 +1,37 @@
+package dy
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// WriteStruct logs v at level under the context key key, with key as the
+// message. In text output v is rendered with %+v, so struct field names are
+// shown; in JSON output it is marshaled and embedded as a nested object. If v
+// cannot be marshaled it is rendered with %+v instead and the error is added
+// as a "<key>_error" field.
+func (l *Logger) WriteStruct(level Level, key string, v interface{}) {
+	if l == nil {
+		return
+	}
+
+	if level.Severity() < l.level.Severity() && l.ring == nil {
+		return
+	}
+
+	l.mu.Lock()
+	useJSON := l.jsonFormat
+	l.mu.Unlock()
+
+	fields := make(map[string]interface{}, 2)
+	if !useJSON {
+		fields[key] = fmt.Sprintf("%+v", v)
+	} else if data, err := json.Marshal(v); err != nil {
+		fields[key] = fmt.Sprintf("%+v", v)
+		fields[key+"_error"] = fmt.Sprintf("failed to marshal: %v", err)
+	} else {
+		fields[key] = json.RawMessage(data)
+	}
+
+	l.WithFields(fields).log(level, "%s", key)
+}
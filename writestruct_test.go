@@ -0,0 +1,68 @@
+package dy
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+type writeStructRequest struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Retry  int    `json:"retry"`
+}
+
+func TestWriteStructText(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithTimestamp(false), WithLevel(DebugLevel))
+
+	l.WriteStruct(DebugLevel, "request", writeStructRequest{Method: "GET", Path: "/users", Retry: 1})
+
+	want := "[DEBUG] request {request: {Method:GET Path:/users Retry:1}}\n"
+	if got := buf.String(); got != want {
+		t.Errorf("WriteStruct output = %q, want %q", got, want)
+	}
+}
+
+func TestWriteStructJSON(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithJSONFormat(true), WithTimestamp(false))
+
+	l.WriteStruct(InfoLevel, "request", &writeStructRequest{Method: "GET", Path: "/users"})
+
+	var entry struct {
+		Message string `json:"message"`
+		Context struct {
+			Request writeStructRequest `json:"request"`
+		} `json:"context"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to parse output %q: %v", buf.String(), err)
+	}
+	if entry.Message != "request" || entry.Context.Request.Path != "/users" {
+		t.Errorf("Expected nested request object, got: %s", buf.String())
+	}
+}
+
+func TestWriteStructMarshalError(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithJSONFormat(true), WithTimestamp(false))
+
+	l.WriteStruct(InfoLevel, "payload", struct{ Fn func() }{})
+
+	output := buf.String()
+	if !strings.Contains(output, `"payload":"{Fn:\u003cnil\u003e}"`) || !strings.Contains(output, `"payload_error":"failed to marshal: json: unsupported type: func()"`) {
+		t.Errorf("Expected %%+v fallback and a field error, got: %s", output)
+	}
+}
+
+func TestWriteStructBelowLevel(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithOutput(&buf), WithLevel(InfoLevel))
+
+	l.WriteStruct(DebugLevel, "request", writeStructRequest{})
+	if buf.Len() != 0 {
+		t.Errorf("Expected nothing below the level, got: %s", buf.String())
+	}
+}